@@ -77,12 +77,16 @@ func (d *DataDispatchCenter) doDataLoop() {
 	data := NewMyData(nil)
 	channels := make([]*_CallbackBox, 0, 10)
 	isInit := false
+	var sequence uint64
 	for {
 		select {
 		case event, ok := <-d.eventChannel:
 			if ok {
 				isInit = true
 				data.DoEvent(event)
+				if s, is := event.(ISequence); is {
+					sequence = s.Sequence()
+				}
 				next := channels[:0]
 				for _, c := range channels {
 					if err := c.handler(event); err != nil {
@@ -99,7 +103,7 @@ func (d *DataDispatchCenter) doDataLoop() {
 					if !isInit {
 						channels = append(channels, hbox)
 					} else {
-						if err := hbox.handler(InitEvent(data.GET())); err == nil {
+						if err := hbox.handler(&SnapshotEvent{InitEvent: data.GET(), sequence: sequence}); err == nil {
 							channels = append(channels, hbox)
 						}
 					}
@@ -105,3 +105,13 @@ func (r InitEvent) Data() []byte {
 func (r InitEvent) All() map[string]map[string][]byte {
 	return r
 }
+
+// SnapshotEvent 新监听者首先收到的全量数据，sequence 为快照包含的最后一个带序号事件
+type SnapshotEvent struct {
+	InitEvent
+	sequence uint64
+}
+
+func (s *SnapshotEvent) Sequence() uint64 {
+	return s.sequence
+}
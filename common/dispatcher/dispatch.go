@@ -17,6 +17,11 @@ type IEvent interface {
 	All() map[string]map[string][]byte
 }
 
+// ISequence 带序号的事件，序号由发送方按发送顺序递增分配
+type ISequence interface {
+	Sequence() uint64
+}
+
 type CallBackFunc func(e IEvent) error
 
 func (f CallBackFunc) DataEvent(e IEvent) error {
@@ -51,6 +51,19 @@ type NConfig struct {
 	Peer           UrlConfig       `json:"peer"`
 	Client         UrlConfig       `json:"client"`
 	Gateway        ListenUrl       `json:"gateway" yaml:"gateway"`
+	// Workers 每个节点启动的 worker 进程数，默认为 1
+	Workers int `json:"workers" yaml:"workers"`
+	// Restart admin、worker 进程异常退出后的重启限制
+	Restart RestartConfig `json:"restart" yaml:"restart"`
+	// Grace worker 进程重启时旧进程排空已有请求的最长时间，单位秒，默认 30
+	Grace int `json:"grace" yaml:"grace"`
+}
+
+// RestartConfig Window 秒内最多重启 Max 次，超过后进程进入 failed 状态
+type RestartConfig struct {
+	// Max 未配置时为 5，<=0 时不限制重启次数
+	Max    *int `json:"max" yaml:"max"`
+	Window int  `json:"window" yaml:"window"`
 }
 type Certificate struct {
 	Key  string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -197,4 +210,17 @@ func initial(c *NConfig) {
 	if len(c.Gateway.AdvertiseUrls) == 0 {
 		c.Gateway.AdvertiseUrls = createAdvertiseUrls(c.Gateway.ListenUrls)
 	}
+	if c.Workers <= 0 {
+		c.Workers = 1
+	}
+	if c.Restart.Max == nil {
+		max := 5
+		c.Restart.Max = &max
+	}
+	if c.Restart.Window <= 0 {
+		c.Restart.Window = 60
+	}
+	if c.Grace <= 0 {
+		c.Grace = 30
+	}
 }
@@ -14,7 +14,7 @@ func Example() {
 
 func TestGetListens(t *testing.T) {
 	type args struct {
-		ucs []UrlConfig
+		ucs []ListenUrl
 	}
 	tests := []struct {
 		name string
@@ -24,10 +24,9 @@ func TestGetListens(t *testing.T) {
 		{
 			name: "test",
 			args: args{
-				ucs: []UrlConfig{
+				ucs: []ListenUrl{
 					{
 						ListenUrls:    []string{"http://0.0.0.0:8088", "http://0.0.0.0", "https://0.0.0.0", "http://192.168.0.5", "https://192.168.0.5"},
-						Certificate:   nil,
 						AdvertiseUrls: nil,
 					},
 				},
@@ -44,3 +43,40 @@ func TestGetListens(t *testing.T) {
 		})
 	}
 }
+
+func TestInitialRestart(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{
+			name: "absent",
+			data: "version: 2\n",
+			want: 5,
+		}, {
+			name: "unlimited",
+			data: "version: 2\nrestart:\n  max: 0\n",
+			want: 0,
+		}, {
+			name: "negative",
+			data: "version: 2\nrestart:\n  max: -1\n",
+			want: -1,
+		}, {
+			name: "set",
+			data: "version: 2\nrestart:\n  max: 3\n",
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, err := readConfig([]byte(tt.data))
+			if err != nil {
+				t.Fatal("readConfig():", err)
+			}
+			initial(c)
+			assert.Equalf(t, tt.want, *c.Restart.Max, "Restart.Max")
+			assert.Equalf(t, 60, c.Restart.Window, "Restart.Window")
+		})
+	}
+}
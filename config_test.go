@@ -1,8 +1,9 @@
-package eosc
+package eosc_test
 
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/eolinker/eosc"
 	"github.com/eolinker/eosc/utils/schema"
 	_ "github.com/stretchr/testify/assert"
 	"reflect"
@@ -10,8 +11,8 @@ import (
 
 func Example() {
 	type MyConfig struct {
-		Id     string    `json:"id" require:"" readonly:"true"`
-		Target RequireId `json:"target" skill:"service.service.IService"`
+		Id     string         `json:"id" require:"" readonly:"true"`
+		Target eosc.RequireId `json:"target" skill:"service.service.IService"`
 	}
 	sc, err := schema.Generate(reflect.TypeOf(MyConfig{}), nil)
 	if err != nil {
@@ -31,8 +31,8 @@ func Rundebug(name string) {
 
 		listen, err := net.Listen("tcp", addr)
 		if err != nil {
-			log.Warn("fail to listen pprof:", addr)
-			panic(err)
+			// 多个 worker 进程共用同一个环境变量，只有第一个能监听成功
+			log.Warn("fail to listen pprof:", addr, ":", err)
 			return
 		}
 		lAddr := listen.Addr().(*net.TCPAddr)
@@ -2,8 +2,13 @@ package eosc
 
 import (
 	"fmt"
+
+	"github.com/eolinker/eosc/metrics"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+var _ ICollectorRegister = (*ExtenderRegister)(nil)
+
 var (
 // DefaultProfessionDriverRegister IExtenderDriverRegister = NewExtenderRegister()
 )
@@ -21,6 +26,11 @@ func (p *ExtenderRegister) RegisterExtenderDriver(name string, factory IExtender
 	return nil
 }
 
+// RegisterCollector 注册到当前进程的指标中，由 /system/metrics 输出
+func (p *ExtenderRegister) RegisterCollector(collector prometheus.Collector) error {
+	return metrics.Register(collector)
+}
+
 func (p *ExtenderRegister) GetDriver(name string) (IExtenderDriverFactory, bool) {
 
 	if v, has := p.data.Get(name); has {
@@ -39,6 +49,11 @@ type IExtenderDriverRegister interface {
 	RegisterExtenderDriver(name string, factory IExtenderDriverFactory) error
 }
 
+// ICollectorRegister 可选实现，插件对 IExtenderDriverRegister 做类型断言后注册自定义的 prometheus collector
+type ICollectorRegister interface {
+	RegisterCollector(collector prometheus.Collector) error
+}
+
 type IExtenderDrivers interface {
 	GetDriver(name string) (IExtenderDriverFactory, bool)
 }
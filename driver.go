@@ -1,6 +1,7 @@
 package eosc
 
 import (
+	"fmt"
 	"reflect"
 )
 
@@ -13,6 +14,11 @@ type IExtenderConfigChecker interface {
 	Check(v interface{}, workers map[RequireId]IWorker) error
 }
 
+// IExtenderConfigRequires 配置类型中无法声明依赖字段的 driver（如进程外插件）由该接口解析依赖
+type IExtenderConfigRequires interface {
+	Requires(v interface{}, workers IWorkers) (map[RequireId]IWorker, error)
+}
+
 type IExtenderDriver interface {
 	ConfigType() reflect.Type
 	Create(id, name string, v interface{}, workers map[RequireId]IWorker) (IWorker, error)
@@ -41,3 +47,32 @@ type ISettings interface {
 	CheckVariable(name string, variable IVariable) (err error)
 	GetConfig(name string) interface{}
 }
+
+// IExtenderDriverMigrator driver 的配置结构发生变化时实现，用于升级已保存的旧版本配置
+type IExtenderDriverMigrator interface {
+	// SchemaVersion 当前配置结构的版本
+	SchemaVersion() int
+	// Migrate 将 fromVersion 版本的配置升级为当前版本
+	Migrate(fromVersion int, body []byte) ([]byte, error)
+}
+
+// SchemaVersion 返回 driver 当前的配置版本，未实现 IExtenderDriverMigrator 时为 0
+func SchemaVersion(driver IExtenderDriver) int {
+	if m, ok := driver.(IExtenderDriverMigrator); ok {
+		return m.SchemaVersion()
+	}
+	return 0
+}
+
+// MigrateConfig 将 fromVersion 版本的配置升级为 driver 当前版本，返回升级后的配置以及是否进行了升级
+func MigrateConfig(driver IExtenderDriver, fromVersion int, body []byte) ([]byte, bool, error) {
+	m, ok := driver.(IExtenderDriverMigrator)
+	if !ok || fromVersion >= m.SchemaVersion() {
+		return body, false, nil
+	}
+	data, err := m.Migrate(fromVersion, body)
+	if err != nil {
+		return nil, false, fmt.Errorf("migrate config from schema %d to %d:%w", fromVersion, m.SchemaVersion(), err)
+	}
+	return data, true, nil
+}
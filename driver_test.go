@@ -0,0 +1,72 @@
+package eosc
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var errMigrate = errors.New("unknown field")
+
+type plainDriver struct{}
+
+func (plainDriver) ConfigType() reflect.Type { return nil }
+func (plainDriver) Create(id, name string, v interface{}, workers map[RequireId]IWorker) (IWorker, error) {
+	return nil, nil
+}
+
+// migrateDriver 版本 2 的配置，每升级一个版本在配置后追加版本号
+type migrateDriver struct {
+	plainDriver
+}
+
+func (migrateDriver) SchemaVersion() int { return 2 }
+func (migrateDriver) Migrate(fromVersion int, body []byte) ([]byte, error) {
+	if bytes.Equal(body, []byte("bad")) {
+		return nil, errMigrate
+	}
+	for v := fromVersion + 1; v <= 2; v++ {
+		body = append(body, byte('0'+v))
+	}
+	return body, nil
+}
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   IExtenderDriver
+		from     int
+		body     string
+		want     string
+		migrated bool
+		wantErr  bool
+	}{
+		{name: "no migrator", driver: plainDriver{}, from: 0, body: "v", want: "v"},
+		{name: "current", driver: migrateDriver{}, from: 2, body: "v", want: "v"},
+		{name: "newer", driver: migrateDriver{}, from: 3, body: "v", want: "v"},
+		{name: "one version", driver: migrateDriver{}, from: 1, body: "v", want: "v2", migrated: true},
+		{name: "two versions", driver: migrateDriver{}, from: 0, body: "v", want: "v12", migrated: true},
+		{name: "failed", driver: migrateDriver{}, from: 0, body: "bad", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, migrated, err := MigrateConfig(tt.driver, tt.from, []byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MigrateConfig() error = %v", err)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, errMigrate) {
+					t.Errorf("MigrateConfig() error = %v, want wrapped %v", err, errMigrate)
+				}
+				return
+			}
+			if string(got) != tt.want || migrated != tt.migrated {
+				t.Errorf("MigrateConfig() = %s, %v, want %s, %v", got, migrated, tt.want, tt.migrated)
+			}
+		})
+	}
+	if v := SchemaVersion(plainDriver{}); v != 0 {
+		t.Errorf("SchemaVersion() = %d for driver without migrator", v)
+	}
+}
@@ -0,0 +1,53 @@
+package balance
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+// WeightAttr 节点权重的属性名，未设置或不合法时权重为 1
+const WeightAttr = "weight"
+
+var (
+	ErrorNoApp       = errors.New("no app")
+	ErrorNoValidNode = errors.New("no valid node")
+)
+
+// nodes 返回当前 app 中可用的节点，跳过 Down 与 Leave 的节点
+func nodes(ctx eocontext.EoContext) ([]eocontext.INode, error) {
+	app := ctx.GetApp()
+	if app == nil {
+		return nil, ErrorNoApp
+	}
+	all := app.Nodes()
+	ns := make([]eocontext.INode, 0, len(all))
+	for _, n := range all {
+		if n == nil {
+			continue
+		}
+		switch n.Status() {
+		case eocontext.Down, eocontext.Leave:
+			continue
+		}
+		ns = append(ns, n)
+	}
+	if len(ns) == 0 {
+		return nil, ErrorNoValidNode
+	}
+	return ns, nil
+}
+
+// Weight 节点权重
+func Weight(node eocontext.INode) int {
+	v, has := node.GetAttrByName(WeightAttr)
+	if !has {
+		return 1
+	}
+	w, err := strconv.Atoi(v)
+	if err != nil || w <= 0 {
+		return 1
+	}
+	return w
+}
@@ -0,0 +1,150 @@
+package balance
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eolinker/eosc/eocontext"
+	"github.com/eolinker/eosc/eocontext/eotest"
+)
+
+type testContext struct {
+	eocontext.EoContext
+	app    eocontext.EoApp
+	labels map[string]string
+	finish eocontext.FinishHandler
+}
+
+func (c *testContext) GetApp() eocontext.EoApp                   { return c.app }
+func (c *testContext) GetLabel(name string) string               { return c.labels[name] }
+func (c *testContext) GetFinish() eocontext.FinishHandler        { return c.finish }
+func (c *testContext) SetFinish(handler eocontext.FinishHandler) { c.finish = handler }
+
+func node(id string, weight int, status eocontext.NodeStatus) *eotest.Node {
+	n := eotest.NewNode(id+":80", eocontext.Attrs{WeightAttr: fmt.Sprint(weight)})
+	n.SetStatus(status)
+	return n
+}
+
+func newApp(n int) *eotest.App {
+	nodes := make([]eocontext.INode, 0, n)
+	for i := 0; i < n; i++ {
+		nodes = append(nodes, node(fmt.Sprint("n", i), 1, eocontext.Running))
+	}
+	return eotest.NewApp("", nodes...)
+}
+
+func selectIds(t *testing.T, h eocontext.BalanceHandler, ctx *testContext, n int) string {
+	ids := ""
+	for i := 0; i < n; i++ {
+		node, err := h.Select(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids += node.IP()
+	}
+	return ids
+}
+
+func TestSelect(t *testing.T) {
+	weighted := eotest.NewApp("", node("a", 5, eocontext.Running), node("b", 1, eocontext.Running), node("c", 1, eocontext.Running))
+	withDown := eotest.NewApp("", node("a", 1, eocontext.Down), node("b", 1, eocontext.Running), node("c", 1, eocontext.Leave), node("d", 1, eocontext.Running))
+	tests := []struct {
+		name    string
+		handler eocontext.BalanceHandler
+		app     eocontext.EoApp
+		times   int
+		want    string
+	}{
+		{name: "round robin", handler: NewRoundRobin(), app: withDown, times: 4, want: "bdbd"},
+		{name: "smooth weighted", handler: NewWeightedRoundRobin(), app: weighted, times: 7, want: "aabacaa"},
+		{name: "weighted skip down", handler: NewWeightedRoundRobin(), app: withDown, times: 4, want: "bdbd"},
+		{name: "least connections", handler: NewLeastConnections(), app: withDown, times: 2, want: "db"},
+		{name: "two choices", handler: NewTwoChoices(), app: eotest.NewApp("", node("a", 1, eocontext.Down), node("b", 1, eocontext.Running)), times: 3, want: "bbb"},
+		{name: "hash", handler: NewConsistentHash(LabelKey("user")), app: withDown, times: 3, want: "bbb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &testContext{app: tt.app, labels: map[string]string{"user": "u1"}}
+			if got := selectIds(t, tt.handler, ctx, tt.times); got != tt.want {
+				t.Errorf("Select() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoValidNode(t *testing.T) {
+	ctx := &testContext{app: eotest.NewApp("", node("a", 1, eocontext.Down))}
+	for _, h := range []eocontext.BalanceHandler{NewRoundRobin(), NewWeightedRoundRobin(), NewLeastConnections(), NewTwoChoices(), NewConsistentHash(LabelKey("user"))} {
+		if _, err := h.Select(ctx); err != ErrorNoValidNode {
+			t.Errorf("%T Select() error = %v, want %v", h, err, ErrorNoValidNode)
+		}
+	}
+}
+
+func TestLeastConnectionsRelease(t *testing.T) {
+	h := NewLeastConnections()
+	app := newApp(2)
+	first := &testContext{app: app}
+	a, _ := h.Select(first)
+	b, _ := h.Select(&testContext{app: app})
+	if a.ID() == b.ID() {
+		t.Fatalf("busy node %s selected twice", a.ID())
+	}
+	first.finish.Finish(first)
+	// a 的请求结束后请求数更少
+	if n, _ := h.Select(&testContext{app: app}); n.ID() != a.ID() {
+		t.Errorf("Select() = %s, want %s", n.ID(), a.ID())
+	}
+}
+
+func TestConsistentHashRemap(t *testing.T) {
+	h := NewConsistentHash(LabelKey("user"))
+	app := newApp(10)
+	before := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		ctx := &testContext{app: app, labels: map[string]string{"user": fmt.Sprint(i)}}
+		n, _ := h.Select(ctx)
+		before[fmt.Sprint(i)] = n.ID()
+	}
+	app.Nodes()[3].Down()
+	moved := 0
+	for key, id := range before {
+		n, _ := h.Select(&testContext{app: app, labels: map[string]string{"user": key}})
+		if n.ID() == "n3:80" {
+			t.Fatalf("down node selected for %s", key)
+		}
+		if id != "n3:80" && n.ID() != id {
+			moved++
+		}
+	}
+	if moved > 0 {
+		t.Errorf("%d keys moved between running nodes", moved)
+	}
+}
+
+func benchmarkSelect(b *testing.B, h eocontext.BalanceHandler) {
+	app := newApp(32)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			ctx := &testContext{app: app, labels: map[string]string{"user": fmt.Sprint(i)}}
+			n, err := h.Select(ctx)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if ctx.finish != nil {
+				ctx.finish.Finish(ctx)
+			}
+			_ = n
+			i++
+		}
+	})
+}
+
+func BenchmarkRoundRobin(b *testing.B)         { benchmarkSelect(b, NewRoundRobin()) }
+func BenchmarkWeightedRoundRobin(b *testing.B) { benchmarkSelect(b, NewWeightedRoundRobin()) }
+func BenchmarkLeastConnections(b *testing.B)   { benchmarkSelect(b, NewLeastConnections()) }
+func BenchmarkTwoChoices(b *testing.B)         { benchmarkSelect(b, NewTwoChoices()) }
+func BenchmarkConsistentHash(b *testing.B)     { benchmarkSelect(b, NewConsistentHash(LabelKey("user"))) }
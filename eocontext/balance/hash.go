@@ -0,0 +1,141 @@
+package balance
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/eolinker/eosc/eocontext"
+	http_context "github.com/eolinker/eosc/eocontext/http-context"
+)
+
+// hashReplicas 每单位权重在哈希环上的虚拟节点数
+const hashReplicas = 64
+
+var _ eocontext.BalanceHandler = (*ConsistentHash)(nil)
+
+// KeyFunc 从请求中取哈希 key
+type KeyFunc func(ctx eocontext.EoContext) string
+
+// LabelKey 以 label 的值为 key
+func LabelKey(name string) KeyFunc {
+	return func(ctx eocontext.EoContext) string {
+		return ctx.GetLabel(name)
+	}
+}
+
+// HeaderKey 以请求 header 的值为 key，非 http 请求时 key 为空
+func HeaderKey(name string) KeyFunc {
+	return func(ctx eocontext.EoContext) string {
+		hc, ok := ctx.(http_context.IHttpContext)
+		if !ok {
+			return ""
+		}
+		return hc.Request().Header().GetHeader(name)
+	}
+}
+
+// ConsistentHash 一致性哈希，节点变化时只有少量 key 改变归属；key 为空时退化为轮询
+type ConsistentHash struct {
+	key      KeyFunc
+	fallback *RoundRobin
+
+	locker sync.RWMutex
+	ring   *hashRing
+}
+
+func NewConsistentHash(key KeyFunc) *ConsistentHash {
+	return &ConsistentHash{key: key, fallback: NewRoundRobin()}
+}
+
+func (h *ConsistentHash) Select(ctx eocontext.EoContext) (eocontext.INode, error) {
+	key := h.key(ctx)
+	if key == "" {
+		return h.fallback.Select(ctx)
+	}
+	ns, err := nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id := h.getRing(ns).get(crc32.ChecksumIEEE([]byte(key)))
+	for _, n := range ns {
+		if n.ID() == id {
+			return n, nil
+		}
+	}
+	return ns[0], nil
+}
+
+// getRing 节点列表不变时复用哈希环
+func (h *ConsistentHash) getRing(ns []eocontext.INode) *hashRing {
+	sign := signature(ns)
+	h.locker.RLock()
+	r := h.ring
+	h.locker.RUnlock()
+	if r != nil && r.sign == sign {
+		return r
+	}
+	r = newHashRing(sign, ns)
+	h.locker.Lock()
+	h.ring = r
+	h.locker.Unlock()
+	return r
+}
+
+func signature(ns []eocontext.INode) string {
+	b := strings.Builder{}
+	for _, n := range ns {
+		b.WriteString(n.ID())
+		b.WriteByte('*')
+		b.WriteString(strconv.Itoa(Weight(n)))
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
+type hashRing struct {
+	sign   string
+	hashes []uint32
+	ids    []string
+}
+
+func newHashRing(sign string, ns []eocontext.INode) *hashRing {
+	type point struct {
+		hash uint32
+		id   string
+	}
+	points := make([]point, 0, len(ns)*hashReplicas)
+	for _, n := range ns {
+		id := n.ID()
+		for i := 0; i < Weight(n)*hashReplicas; i++ {
+			points = append(points, point{hash: crc32.ChecksumIEEE([]byte(id + "#" + strconv.Itoa(i))), id: id})
+		}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].hash == points[j].hash {
+			return points[i].id < points[j].id
+		}
+		return points[i].hash < points[j].hash
+	})
+	r := &hashRing{
+		sign:   sign,
+		hashes: make([]uint32, len(points)),
+		ids:    make([]string, len(points)),
+	}
+	for i, p := range points {
+		r.hashes[i], r.ids[i] = p.hash, p.id
+	}
+	return r
+}
+
+func (r *hashRing) get(hash uint32) string {
+	i := sort.Search(len(r.hashes), func(i int) bool {
+		return r.hashes[i] >= hash
+	})
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.ids[i]
+}
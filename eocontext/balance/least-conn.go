@@ -0,0 +1,118 @@
+package balance
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+var (
+	_ eocontext.BalanceHandler = (*LeastConnections)(nil)
+	_ eocontext.BalanceHandler = (*TwoChoices)(nil)
+)
+
+// connections 记录每个节点进行中的请求数
+type connections struct {
+	active sync.Map
+}
+
+func (c *connections) counter(id string) *int64 {
+	v, ok := c.active.Load(id)
+	if !ok {
+		v, _ = c.active.LoadOrStore(id, new(int64))
+	}
+	return v.(*int64)
+}
+
+func (c *connections) load(node eocontext.INode) int64 {
+	return atomic.LoadInt64(c.counter(node.ID()))
+}
+
+// acquire 增加节点的请求数，请求结束（FinishHandler 执行）时减少
+func (c *connections) acquire(ctx eocontext.EoContext, node eocontext.INode) {
+	counter := c.counter(node.ID())
+	atomic.AddInt64(counter, 1)
+	ctx.SetFinish(&releaseFinish{
+		FinishHandler: ctx.GetFinish(),
+		counter:       counter,
+	})
+}
+
+type releaseFinish struct {
+	eocontext.FinishHandler
+	counter *int64
+	once    sync.Once
+}
+
+func (r *releaseFinish) Finish(ctx eocontext.EoContext) error {
+	r.once.Do(func() {
+		atomic.AddInt64(r.counter, -1)
+	})
+	if r.FinishHandler != nil {
+		return r.FinishHandler.Finish(ctx)
+	}
+	return nil
+}
+
+// LeastConnections 选择进行中请求数与权重之比最小的节点，相同时轮流选择
+type LeastConnections struct {
+	connections
+	next uint64
+}
+
+func NewLeastConnections() *LeastConnections {
+	return &LeastConnections{}
+}
+
+func (l *LeastConnections) Select(ctx eocontext.EoContext) (eocontext.INode, error) {
+	ns, err := nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start := int(atomic.AddUint64(&l.next, 1) % uint64(len(ns)))
+	var best eocontext.INode
+	var bestActive, bestWeight int64
+	for i := range ns {
+		n := ns[(start+i)%len(ns)]
+		active, weight := l.load(n), int64(Weight(n))
+		// active/weight < bestActive/bestWeight
+		if best == nil || active*bestWeight < bestActive*weight {
+			best, bestActive, bestWeight = n, active, weight
+		}
+	}
+	l.acquire(ctx, best)
+	return best, nil
+}
+
+// TwoChoices 随机选出两个节点，取进行中请求数较少的一个
+type TwoChoices struct {
+	connections
+}
+
+func NewTwoChoices() *TwoChoices {
+	return &TwoChoices{}
+}
+
+func (t *TwoChoices) Select(ctx eocontext.EoContext) (eocontext.INode, error) {
+	ns, err := nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	best := ns[0]
+	if len(ns) > 1 {
+		i := rand.Intn(len(ns))
+		j := rand.Intn(len(ns) - 1)
+		if j >= i {
+			j++
+		}
+		a, b := ns[i], ns[j]
+		best = a
+		if t.load(b)*int64(Weight(a)) < t.load(a)*int64(Weight(b)) {
+			best = b
+		}
+	}
+	t.acquire(ctx, best)
+	return best, nil
+}
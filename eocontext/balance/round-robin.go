@@ -0,0 +1,27 @@
+package balance
+
+import (
+	"sync/atomic"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+var _ eocontext.BalanceHandler = (*RoundRobin)(nil)
+
+// RoundRobin 轮询
+type RoundRobin struct {
+	next uint64
+}
+
+func NewRoundRobin() *RoundRobin {
+	return &RoundRobin{}
+}
+
+func (r *RoundRobin) Select(ctx eocontext.EoContext) (eocontext.INode, error) {
+	ns, err := nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	i := atomic.AddUint64(&r.next, 1) - 1
+	return ns[i%uint64(len(ns))], nil
+}
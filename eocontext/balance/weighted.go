@@ -0,0 +1,49 @@
+package balance
+
+import (
+	"sync"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+var _ eocontext.BalanceHandler = (*WeightedRoundRobin)(nil)
+
+// WeightedRoundRobin 平滑加权轮询，与 nginx 的算法一致，权重取自节点的 weight 属性
+type WeightedRoundRobin struct {
+	locker  sync.Mutex
+	current map[string]int
+}
+
+func NewWeightedRoundRobin() *WeightedRoundRobin {
+	return &WeightedRoundRobin{current: make(map[string]int)}
+}
+
+func (r *WeightedRoundRobin) Select(ctx eocontext.EoContext) (eocontext.INode, error) {
+	ns, err := nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	var best eocontext.INode
+	bestWeight, total := 0, 0
+	for _, n := range ns {
+		w := Weight(n)
+		total += w
+		c := r.current[n.ID()] + w
+		r.current[n.ID()] = c
+		if best == nil || c > bestWeight {
+			best, bestWeight = n, c
+		}
+	}
+	r.current[best.ID()] -= total
+	// 节点列表变化后清理已不存在的节点
+	if len(r.current) > len(ns) {
+		alive := make(map[string]int, len(ns))
+		for _, n := range ns {
+			alive[n.ID()] = r.current[n.ID()]
+		}
+		r.current = alive
+	}
+	return best, nil
+}
@@ -0,0 +1,40 @@
+package eocontext
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration 配置中的时长，json 中为 "10s"、"300ms" 这样的字符串，数字按毫秒处理
+type Duration time.Duration
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch value := v.(type) {
+	case float64:
+		*d = Duration(time.Duration(value * float64(time.Millisecond)))
+	case string:
+		t, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*d = Duration(t)
+	case nil:
+		*d = 0
+	default:
+		return fmt.Errorf("invalid duration:%s", data)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package eotest 测试用的 eocontext 节点与 app，供各个包的测试共用
+package eotest
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+// Node 测试用的节点，ID 为地址 host:port，初始状态为 Running
+type Node struct {
+	locker sync.Mutex
+	addr   string
+	status eocontext.NodeStatus
+	attrs  eocontext.Attrs
+}
+
+func NewNode(addr string, attrs eocontext.Attrs) *Node {
+	return &Node{addr: addr, status: eocontext.Running, attrs: attrs}
+}
+
+func (n *Node) GetAttrs() eocontext.Attrs { return n.attrs }
+func (n *Node) GetAttrByName(name string) (string, bool) {
+	v, has := n.attrs[name]
+	return v, has
+}
+func (n *Node) ID() string   { return n.addr }
+func (n *Node) Addr() string { return n.addr }
+func (n *Node) IP() string {
+	host, _, err := net.SplitHostPort(n.addr)
+	if err != nil {
+		return n.addr
+	}
+	return host
+}
+func (n *Node) Port() int {
+	_, port, _ := net.SplitHostPort(n.addr)
+	p, _ := strconv.Atoi(port)
+	return p
+}
+func (n *Node) Status() eocontext.NodeStatus {
+	n.locker.Lock()
+	defer n.locker.Unlock()
+	return n.status
+}
+
+// SetStatus 直接设置节点状态
+func (n *Node) SetStatus(status eocontext.NodeStatus) {
+	n.locker.Lock()
+	defer n.locker.Unlock()
+	n.status = status
+}
+func (n *Node) Up()    { n.SetStatus(eocontext.Running) }
+func (n *Node) Down()  { n.SetStatus(eocontext.Down) }
+func (n *Node) Leave() { n.SetStatus(eocontext.Leave) }
+
+// App 测试用的 app，超时为 1 秒
+type App struct {
+	scheme string
+	nodes  []eocontext.INode
+}
+
+// NewApp scheme 为空时使用 http
+func NewApp(scheme string, nodes ...eocontext.INode) *App {
+	if scheme == "" {
+		scheme = "http"
+	}
+	return &App{scheme: scheme, nodes: nodes}
+}
+
+func (a *App) Nodes() []eocontext.INode { return a.nodes }
+func (a *App) Scheme() string           { return a.scheme }
+func (a *App) TimeOut() time.Duration   { return time.Second }
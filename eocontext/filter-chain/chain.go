@@ -0,0 +1,213 @@
+package filter_chain
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/eolinker/eosc/eocontext"
+	grpc_context "github.com/eolinker/eosc/eocontext/grpc-context"
+	http_context "github.com/eolinker/eosc/eocontext/http-context"
+	"github.com/eolinker/eosc/eocontext/trace"
+	"github.com/eolinker/eosc/log"
+	"github.com/eolinker/eosc/utils/config"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	_ eocontext.IChain    = (*Chain)(nil)
+	_ eocontext.IChainPro = (*Chain)(nil)
+)
+
+// PanicError filter panic 后返回的错误
+type PanicError struct {
+	Filter string
+	Value  interface{}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("filter %s panic: %v", e.Filter, e.Value)
+}
+
+type entry struct {
+	filter eocontext.IFilter
+	match  IMatchFilter
+	name   string
+}
+
+// Chain 按阶段与优先级排序的 filter 链，执行时不分配内存；filter 的 panic 转换为 PanicError 并返回 500
+// ctx 开启追踪时，每个 filter 的执行记录为一个 span
+type Chain struct {
+	entries []entry
+	// nodes 每个位置对应的 next，执行时直接取用
+	nodes []node
+	pool  sync.Pool
+}
+
+func NewChain(filters ...eocontext.IFilter) *Chain {
+	entries := make([]entry, 0, len(filters))
+	for _, f := range filters {
+		if f == nil {
+			continue
+		}
+		e := entry{filter: f, name: filterName(f)}
+		if m, ok := f.(IMatchFilter); ok {
+			e.match = m
+		}
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		pi, ri := declared(entries[i].filter)
+		pj, rj := declared(entries[j].filter)
+		if pi != pj {
+			return pi < pj
+		}
+		return ri > rj
+	})
+	c := &Chain{entries: entries}
+	c.nodes = newNodes(c, nil)
+	c.pool.New = func() interface{} {
+		return &run{}
+	}
+	return c
+}
+
+func declared(f eocontext.IFilter) (Phase, int) {
+	if p, ok := f.(IPhaseFilter); ok {
+		return p.Phase(), p.Priority()
+	}
+	return PhaseAccess, 0
+}
+
+func filterName(f eocontext.IFilter) string {
+	if n, ok := f.(INamedFilter); ok {
+		return n.Name()
+	}
+	return config.TypeNameOf(f)
+}
+
+// Filters 排序后的 filter
+func (c *Chain) Filters() eocontext.Filters {
+	fs := make(eocontext.Filters, len(c.entries))
+	for i, e := range c.entries {
+		fs[i] = e.filter
+	}
+	return fs
+}
+
+func (c *Chain) DoChain(ctx eocontext.EoContext) error {
+	return c.nodes[0].DoChain(ctx)
+}
+
+// Chain 执行 filter 链，append 的 filter 在链的末尾按传入顺序执行
+func (c *Chain) Chain(ctx eocontext.EoContext, append ...eocontext.IFilter) error {
+	if len(append) == 0 {
+		return c.DoChain(ctx)
+	}
+	r := c.pool.Get().(*run)
+	r.reset(c, append)
+	err := r.nodes[0].DoChain(ctx)
+	r.extra = nil
+	c.pool.Put(r)
+	return err
+}
+
+func (c *Chain) Destroy() {
+	for _, e := range c.entries {
+		e.filter.Destroy()
+	}
+}
+
+// run 带有 append filter 的一次执行，复用 nodes
+type run struct {
+	extra []eocontext.IFilter
+	nodes []node
+}
+
+func (r *run) reset(c *Chain, extra []eocontext.IFilter) {
+	r.extra = extra
+	size := len(c.entries) + len(extra) + 1
+	if cap(r.nodes) < size {
+		r.nodes = make([]node, size)
+	}
+	r.nodes = r.nodes[:size]
+	for i := range r.nodes {
+		r.nodes[i] = node{chain: c, run: r, index: i}
+	}
+}
+
+func newNodes(c *Chain, r *run) []node {
+	nodes := make([]node, len(c.entries)+1)
+	for i := range nodes {
+		nodes[i] = node{chain: c, run: r, index: i}
+	}
+	return nodes
+}
+
+// node 链上的一个位置，DoChain 执行该位置的 filter，next 为下一个位置
+type node struct {
+	chain *Chain
+	run   *run
+	index int
+}
+
+func (n *node) next() *node {
+	if n.run != nil {
+		return &n.run.nodes[n.index+1]
+	}
+	return &n.chain.nodes[n.index+1]
+}
+
+func (n *node) DoChain(ctx eocontext.EoContext) (err error) {
+	entries := n.chain.entries
+	var filter eocontext.IFilter
+	var name string
+	if n.index < len(entries) {
+		e := &entries[n.index]
+		if e.match != nil && !e.match.Match(ctx) {
+			return n.next().DoChain(ctx)
+		}
+		filter, name = e.filter, e.name
+	} else if n.run != nil && n.index-len(entries) < len(n.run.extra) {
+		filter = n.run.extra[n.index-len(entries)]
+		if m, ok := filter.(IMatchFilter); ok && !m.Match(ctx) {
+			return n.next().DoChain(ctx)
+		}
+	} else {
+		return nil
+	}
+	span := trace.FromContext(ctx)
+	if span != nil {
+		if name == "" {
+			name = filterName(filter)
+		}
+		span = trace.Start(ctx, name)
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			if name == "" {
+				name = filterName(filter)
+			}
+			log.Errorf("filter %s panic: %v\n%s", name, v, debug.Stack())
+			respondPanic(ctx)
+			err = &PanicError{Filter: name, Value: v}
+		}
+		span.SetError(err)
+		span.End()
+	}()
+	return filter.DoFilter(ctx, n.next())
+}
+
+func (n *node) Destroy() {}
+
+// respondPanic 按请求的协议返回服务端错误
+func respondPanic(ctx eocontext.EoContext) {
+	switch c := ctx.(type) {
+	case http_context.IHttpContext:
+		c.Response().SetStatus(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	case grpc_context.IGrpcContext:
+		c.Response().SetStatus(codes.Internal, "internal error")
+	}
+}
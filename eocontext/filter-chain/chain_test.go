@@ -0,0 +1,166 @@
+package filter_chain
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/eolinker/eosc/eocontext"
+	http_context "github.com/eolinker/eosc/eocontext/http-context"
+	"github.com/eolinker/eosc/eocontext/trace"
+)
+
+type recordFilter struct {
+	name  string
+	trace *[]string
+	panic bool
+}
+
+func (f *recordFilter) DoFilter(ctx eocontext.EoContext, next eocontext.IChain) error {
+	*f.trace = append(*f.trace, f.name)
+	if f.panic {
+		panic("boom")
+	}
+	return next.DoChain(ctx)
+}
+
+func (f *recordFilter) Name() string { return f.name }
+func (f *recordFilter) Destroy()     {}
+
+type testResponse struct {
+	http_context.IResponse
+	code int
+}
+
+func (r *testResponse) SetStatus(code int, status string) { r.code = code }
+
+type testContext struct {
+	http_context.IHttpContext
+	labels   map[string]string
+	response testResponse
+}
+
+func (c *testContext) GetLabel(name string) string       { return c.labels[name] }
+func (c *testContext) Value(key interface{}) interface{} { return nil }
+func (c *testContext) Response() http_context.IResponse  { return &c.response }
+
+func TestChain(t *testing.T) {
+	var trace []string
+	f := func(name string) *recordFilter {
+		return &recordFilter{name: name, trace: &trace}
+	}
+	isAdmin := func(ctx eocontext.EoContext) bool {
+		return ctx.GetLabel("user") == "admin"
+	}
+	c := NewChain(
+		Declare(f("log"), PhaseLog, 0, nil),
+		f("auth"),
+		Declare(f("proxy"), PhaseProxy, 0, nil),
+		Declare(f("limit"), PhaseAccess, 10, nil),
+		Declare(f("admin-rewrite"), PhaseRewrite, 0, isAdmin),
+	)
+	tests := []struct {
+		name   string
+		user   string
+		append []eocontext.IFilter
+		want   string
+	}{
+		{name: "order", user: "guest", want: "limit,auth,proxy,log"},
+		{name: "match", user: "admin", want: "limit,auth,admin-rewrite,proxy,log"},
+		{name: "append", user: "guest", append: []eocontext.IFilter{f("extra")}, want: "limit,auth,proxy,log,extra"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace = trace[:0]
+			ctx := &testContext{labels: map[string]string{"user": tt.user}}
+			if err := c.Chain(ctx, tt.append...); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(trace, ","); got != tt.want {
+				t.Errorf("Chain() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChainPanic(t *testing.T) {
+	var trace []string
+	c := NewChain(&recordFilter{name: "outer", trace: &trace}, Declare(&recordFilter{name: "broken", trace: &trace, panic: true}, PhaseProxy, 0, nil))
+	ctx := &testContext{}
+	err := c.DoChain(ctx)
+	var pe *PanicError
+	if !errors.As(err, &pe) || pe.Filter != "broken" {
+		t.Fatalf("DoChain() error = %v", err)
+	}
+	if ctx.response.code != http.StatusInternalServerError {
+		t.Errorf("status = %d", ctx.response.code)
+	}
+	if strings.Join(trace, ",") != "outer,broken" {
+		t.Errorf("trace = %v", trace)
+	}
+}
+
+type tracedContext struct {
+	eocontext.EoContext
+	values map[interface{}]interface{}
+}
+
+func (c *tracedContext) Value(key interface{}) interface{} { return c.values[key] }
+func (c *tracedContext) WithValue(key, val interface{})    { c.values[key] = val }
+
+func TestChainTrace(t *testing.T) {
+	var calls []string
+	exporter := trace.NewMemoryExporter()
+	c := NewChain(&recordFilter{name: "auth", trace: &calls}, Declare(&recordFilter{name: "broken", trace: &calls, panic: true}, PhaseProxy, 0, nil))
+	ctx := &tracedContext{values: make(map[interface{}]interface{})}
+	traced, root := trace.NewTracer(exporter).Begin(ctx, "request")
+	c.DoChain(traced)
+	root.End()
+
+	spans := exporter.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("exported %d spans", len(spans))
+	}
+	broken, auth := spans[0], spans[1]
+	if broken.Name != "broken" || broken.ParentID != auth.SpanID || broken.Error == "" {
+		t.Errorf("broken span: %+v", broken)
+	}
+	if auth.Name != "auth" || auth.ParentID != root.SpanID().String() {
+		t.Errorf("auth span: %+v", auth)
+	}
+}
+
+type passFilter struct{}
+
+func (passFilter) DoFilter(ctx eocontext.EoContext, next eocontext.IChain) error {
+	return next.DoChain(ctx)
+}
+
+func (passFilter) Destroy() {}
+
+func TestChainAllocs(t *testing.T) {
+	c := NewChain(passFilter{}, Declare(passFilter{}, PhaseLog, 0, func(ctx eocontext.EoContext) bool { return true }), passFilter{})
+	ctx := &testContext{}
+	extra := []eocontext.IFilter{passFilter{}}
+	c.Chain(ctx, extra...)
+	if n := testing.AllocsPerRun(100, func() {
+		c.DoChain(ctx)
+		c.Chain(ctx, extra...)
+	}); n != 0 {
+		t.Errorf("allocs per run = %v", n)
+	}
+}
+
+func BenchmarkChain(b *testing.B) {
+	fs := make([]eocontext.IFilter, 10)
+	for i := range fs {
+		fs[i] = passFilter{}
+	}
+	c := NewChain(fs...)
+	ctx := &testContext{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.DoChain(ctx)
+	}
+}
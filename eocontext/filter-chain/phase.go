@@ -0,0 +1,71 @@
+package filter_chain
+
+import (
+	"github.com/eolinker/eosc/eocontext"
+)
+
+// Phase filter 所在的阶段，按声明顺序执行
+type Phase int
+
+const (
+	PhaseAccess Phase = iota
+	PhaseRewrite
+	PhaseProxy
+	PhaseResponse
+	PhaseLog
+)
+
+var phaseNames = []string{"access", "rewrite", "proxy", "response", "log"}
+
+func (p Phase) String() string {
+	if p < 0 || int(p) >= len(phaseNames) {
+		return "unknown"
+	}
+	return phaseNames[p]
+}
+
+// IPhaseFilter 声明阶段与优先级的 filter，同一阶段内优先级高的先执行；未实现时为 PhaseAccess、优先级 0
+type IPhaseFilter interface {
+	eocontext.IFilter
+	Phase() Phase
+	Priority() int
+}
+
+// IMatchFilter 有条件执行的 filter，Match 返回 false 时跳过
+type IMatchFilter interface {
+	eocontext.IFilter
+	Match(ctx eocontext.EoContext) bool
+}
+
+// INamedFilter 出错时日志中使用的名称，未实现时使用类型名
+type INamedFilter interface {
+	Name() string
+}
+
+// Declare 为没有声明阶段、优先级、条件的 filter 补充声明，match 为空时总是执行
+func Declare(filter eocontext.IFilter, phase Phase, priority int, match func(ctx eocontext.EoContext) bool) eocontext.IFilter {
+	return &declaredFilter{IFilter: filter, phase: phase, priority: priority, match: match}
+}
+
+type declaredFilter struct {
+	eocontext.IFilter
+	phase    Phase
+	priority int
+	match    func(ctx eocontext.EoContext) bool
+}
+
+func (d *declaredFilter) Phase() Phase {
+	return d.phase
+}
+
+func (d *declaredFilter) Priority() int {
+	return d.priority
+}
+
+func (d *declaredFilter) Match(ctx eocontext.EoContext) bool {
+	return d.match == nil || d.match(ctx)
+}
+
+func (d *declaredFilter) Name() string {
+	return filterName(d.IFilter)
+}
@@ -0,0 +1,106 @@
+package grpc_context
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	ErrorInvalidMethod = errors.New("invalid grpc method")
+)
+
+// IGrpcContext gRPC 调用的上下文，一次调用（包括流式调用）对应一个上下文
+type IGrpcContext interface {
+	eocontext.EoContext
+	// FullMethod 完整的方法名，格式为 /package.Service/Method
+	FullMethod() string
+	// Service 服务名，例如 package.Service
+	Service() string
+	Method() string
+	// IsStream 是否为流式调用
+	IsStream() bool
+	Request() IRequestReader // 读取原始请求
+	Proxy() IRequest         // 读写转发请求
+	Response() IResponse     // 处理返回结果，可读可写
+	// AddMessageHook 添加消息的处理，按添加顺序执行
+	AddMessageHook(hook MessageHook)
+	Invoke(address string, timeout time.Duration) error
+}
+
+// IMetadataReader gRPC metadata 的读，key 不区分大小写
+type IMetadataReader interface {
+	Get(key string) []string
+	// First 返回 key 的第一个值
+	First(key string) string
+	Metadata() metadata.MD
+}
+
+type IMetadataWriter interface {
+	IMetadataReader
+	Set(key string, values ...string)
+	Append(key string, values ...string)
+	Delete(key string)
+}
+
+// IRequestReader 原始请求数据的读
+type IRequestReader interface {
+	Metadata() IMetadataReader
+	Host() string
+	RemoteAddr() string
+	RealIP() string
+	ContentType() string
+}
+
+// IRequest 用于组装转发的请求
+type IRequest interface {
+	Metadata() IMetadataWriter
+	Host() string
+	SetHost(host string)
+}
+
+// IResponse 返回给客户端的
+type IResponse interface {
+	Header() IMetadataWriter
+	Trailer() IMetadataWriter
+	// Code 调用结果的状态码，Message 状态说明
+	Code() codes.Code
+	Message() string
+	SetStatus(code codes.Code, message string)
+	ResponseError() error
+	ResponseTime() time.Duration
+}
+
+// MessageHook 在每个消息转发前调用，返回的数据替换原消息；返回错误时以该错误结束调用
+type MessageHook interface {
+	// OnRequest 客户端发往上游的消息
+	OnRequest(ctx IGrpcContext, msg []byte) ([]byte, error)
+	// OnResponse 上游返回给客户端的消息
+	OnResponse(ctx IGrpcContext, msg []byte) ([]byte, error)
+}
+
+func Assert(ctx eocontext.EoContext) (IGrpcContext, error) {
+	var grpcContext IGrpcContext
+	err := ctx.Assert(&grpcContext)
+	return grpcContext, err
+}
+
+// ParseFullMethod 把 /package.Service/Method 拆分为服务名与方法名
+func ParseFullMethod(fullMethod string) (service, method string, err error) {
+	if !strings.HasPrefix(fullMethod, "/") {
+		return "", "", ErrorInvalidMethod
+	}
+	i := strings.LastIndex(fullMethod, "/")
+	if i < 1 {
+		return "", "", ErrorInvalidMethod
+	}
+	service, method = fullMethod[1:i], fullMethod[i+1:]
+	if service == "" || method == "" {
+		return "", "", ErrorInvalidMethod
+	}
+	return service, method, nil
+}
@@ -0,0 +1,79 @@
+package grpc_context
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+func TestParseFullMethod(t *testing.T) {
+	tests := []struct {
+		fullMethod string
+		service    string
+		method     string
+		wantErr    bool
+	}{
+		{fullMethod: "/helloworld.Greeter/SayHello", service: "helloworld.Greeter", method: "SayHello"},
+		{fullMethod: "/Greeter/SayHello", service: "Greeter", method: "SayHello"},
+		{fullMethod: "helloworld.Greeter/SayHello", wantErr: true},
+		{fullMethod: "/helloworld.Greeter/", wantErr: true},
+		{fullMethod: "/SayHello", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fullMethod, func(t *testing.T) {
+			service, method, err := ParseFullMethod(tt.fullMethod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFullMethod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if service != tt.service || method != tt.method {
+				t.Errorf("ParseFullMethod() = %s %s, want %s %s", service, method, tt.service, tt.method)
+			}
+		})
+	}
+}
+
+type testContext struct {
+	IGrpcContext
+	grpc bool
+}
+
+func (c *testContext) Assert(i interface{}) error {
+	if v, ok := i.(*IGrpcContext); ok && c.grpc {
+		*v = c
+		return nil
+	}
+	return errors.New("not grpc")
+}
+
+type testFilter struct {
+	called bool
+}
+
+func (f *testFilter) DoGrpcFilter(ctx IGrpcContext, next eocontext.IChain) error {
+	f.called = true
+	return next.DoChain(ctx)
+}
+
+type testChain struct {
+	called bool
+}
+
+func (c *testChain) DoChain(ctx eocontext.EoContext) error {
+	c.called = true
+	return nil
+}
+
+func (c *testChain) Destroy() {}
+
+func TestDoGrpcFilter(t *testing.T) {
+	for _, grpc := range []bool{true, false} {
+		f, next := &testFilter{}, &testChain{}
+		if err := DoGrpcFilter(f, &testContext{grpc: grpc}, next); err != nil {
+			t.Fatal(err)
+		}
+		if f.called != grpc || !next.called {
+			t.Errorf("grpc=%v: filter called %v, next called %v", grpc, f.called, next.called)
+		}
+	}
+}
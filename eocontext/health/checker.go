@@ -0,0 +1,248 @@
+package health
+
+import (
+	"sync"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+	http_context "github.com/eolinker/eosc/eocontext/http-context"
+	"github.com/eolinker/eosc/log"
+)
+
+// Event 节点状态变化
+type Event struct {
+	Node   eocontext.INode
+	Status eocontext.NodeStatus
+	Reason string
+	Time   time.Time
+}
+
+// nodeState 节点连续成功、失败的次数
+type nodeState struct {
+	successes int
+	failures  int
+	// passive 被动检测连续失败的次数
+	passive int
+}
+
+// Checker 检查 app 中节点的健康状态，调用节点的 Up、Down 切换状态；Leave 的节点不处理
+type Checker struct {
+	app    eocontext.EoApp
+	conf   *Config
+	prober prober
+
+	locker   sync.Mutex
+	states   map[string]*nodeState
+	handlers []func(e *Event)
+	timers   map[string]*time.Timer
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+func NewChecker(app eocontext.EoApp, conf *Config) (*Checker, error) {
+	if app == nil {
+		return nil, ErrorNoApp
+	}
+	if err := conf.check(); err != nil {
+		return nil, err
+	}
+	c := &Checker{
+		app:    app,
+		conf:   conf,
+		states: make(map[string]*nodeState),
+		timers: make(map[string]*time.Timer),
+		stop:   make(chan struct{}),
+	}
+	if conf.Active != nil {
+		c.prober = newProber(app.Scheme(), conf.Active)
+	}
+	return c, nil
+}
+
+// OnChange 注册节点状态变化的回调
+func (c *Checker) OnChange(h func(e *Event)) {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	c.handlers = append(c.handlers, h)
+}
+
+// Start 按间隔执行主动探测，未配置主动探测时不做任何事
+func (c *Checker) Start() {
+	if c.prober == nil {
+		return
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.conf.Active.Interval.Duration())
+		defer ticker.Stop()
+		for {
+			c.Check()
+			select {
+			case <-ticker.C:
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (c *Checker) Stop() {
+	c.once.Do(func() {
+		close(c.stop)
+		c.locker.Lock()
+		for id, t := range c.timers {
+			t.Stop()
+			delete(c.timers, id)
+		}
+		c.locker.Unlock()
+	})
+	c.wg.Wait()
+}
+
+// Check 对所有节点执行一轮主动探测
+func (c *Checker) Check() {
+	if c.prober == nil {
+		return
+	}
+	nodes := c.app.Nodes()
+	errs := make([]error, len(nodes))
+	wg := sync.WaitGroup{}
+	for i, n := range nodes {
+		if n.Status() == eocontext.Leave {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, n eocontext.INode) {
+			defer wg.Done()
+			errs[i] = c.prober.probe(n)
+		}(i, n)
+	}
+	wg.Wait()
+
+	c.locker.Lock()
+	alive := make(map[string]*nodeState, len(nodes))
+	var events []*Event
+	for i, n := range nodes {
+		s := c.state(n.ID())
+		alive[n.ID()] = s
+		if n.Status() == eocontext.Leave {
+			continue
+		}
+		if errs[i] != nil {
+			s.successes = 0
+			s.failures++
+			if n.Status() == eocontext.Running && s.failures >= c.conf.Active.Unhealthy {
+				events = append(events, c.flip(n, eocontext.Down, "active probe: "+errs[i].Error()))
+			}
+			continue
+		}
+		s.failures = 0
+		s.successes++
+		if n.Status() == eocontext.Down && s.successes >= c.conf.Active.Healthy {
+			s.passive = 0
+			events = append(events, c.flip(n, eocontext.Running, "active probe succeeded"))
+		}
+	}
+	// 节点列表变化后清理已不存在的节点
+	c.states = alive
+	handlers := c.handlers
+	c.locker.Unlock()
+	c.emit(handlers, events)
+}
+
+// Observe 根据请求的转发结果做被动检测，连接失败或 5xx 计为失败
+func (c *Checker) Observe(ctx http_context.IHttpContext) {
+	if c.conf.Passive == nil {
+		return
+	}
+	proxies := ctx.Proxies()
+	if len(proxies) == 0 {
+		return
+	}
+	nodes := make(map[string]eocontext.INode)
+	for _, n := range c.app.Nodes() {
+		nodes[n.Addr()] = n
+	}
+	c.locker.Lock()
+	var events []*Event
+	for _, p := range proxies {
+		n, has := nodes[p.URI().Host()]
+		if !has || n.Status() != eocontext.Running {
+			continue
+		}
+		s := c.state(n.ID())
+		code := p.StatusCode()
+		if code > 0 && code < 500 {
+			s.passive = 0
+			continue
+		}
+		s.passive++
+		if s.passive >= c.conf.Passive.Failures {
+			s.passive = 0
+			s.successes = 0
+			events = append(events, c.flip(n, eocontext.Down, "passive: too many proxy failures"))
+			c.eject(n)
+		}
+	}
+	handlers := c.handlers
+	c.locker.Unlock()
+	c.emit(handlers, events)
+}
+
+// eject 没有主动探测时，节点在 Ejection 后重新上线
+func (c *Checker) eject(n eocontext.INode) {
+	if c.prober != nil {
+		return
+	}
+	select {
+	case <-c.stop:
+		return
+	default:
+	}
+	if t, has := c.timers[n.ID()]; has {
+		t.Stop()
+	}
+	c.timers[n.ID()] = time.AfterFunc(c.conf.Passive.Ejection.Duration(), func() {
+		c.locker.Lock()
+		delete(c.timers, n.ID())
+		if n.Status() != eocontext.Down {
+			c.locker.Unlock()
+			return
+		}
+		e := c.flip(n, eocontext.Running, "passive: ejection expired")
+		handlers := c.handlers
+		c.locker.Unlock()
+		c.emit(handlers, []*Event{e})
+	})
+}
+
+func (c *Checker) state(id string) *nodeState {
+	s, has := c.states[id]
+	if !has {
+		s = &nodeState{}
+		c.states[id] = s
+	}
+	return s
+}
+
+func (c *Checker) flip(n eocontext.INode, status eocontext.NodeStatus, reason string) *Event {
+	if status == eocontext.Down {
+		n.Down()
+		log.Warnf("health check: node %s down: %s", n.Addr(), reason)
+	} else {
+		n.Up()
+		log.Infof("health check: node %s up: %s", n.Addr(), reason)
+	}
+	return &Event{Node: n, Status: status, Reason: reason, Time: time.Now()}
+}
+
+func (c *Checker) emit(handlers []func(e *Event), events []*Event) {
+	for _, e := range events {
+		for _, h := range handlers {
+			h(e)
+		}
+	}
+}
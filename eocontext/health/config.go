@@ -0,0 +1,111 @@
+package health
+
+import (
+	"errors"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+const (
+	ProbeTCP  = "tcp"
+	ProbeHTTP = "http"
+)
+
+const (
+	defaultInterval  = 10 * time.Second
+	defaultTimeout   = 3 * time.Second
+	defaultThreshold = 3
+	defaultEjection  = 30 * time.Second
+)
+
+var (
+	ErrorNoApp          = errors.New("no app")
+	ErrorUnknownProbe   = errors.New("unknown probe type")
+	ErrorNothingToCheck = errors.New("neither active nor passive check configured")
+	ErrorNoConfig       = errors.New("no health check config")
+)
+
+// Config 健康检查配置，Active 与 Passive 至少配置一个
+type Config struct {
+	Active  *ActiveConfig  `json:"active,omitempty"`
+	Passive *PassiveConfig `json:"passive,omitempty"`
+}
+
+// ActiveConfig 主动探测配置
+type ActiveConfig struct {
+	// Type tcp 或 http
+	Type string `json:"type"`
+	// Path http 探测的路径
+	Path string `json:"path,omitempty"`
+	// Host http 探测的 Host，为空时使用节点地址
+	Host string `json:"host,omitempty"`
+	// ExpectStatus http 探测视为健康的状态码，为空时 2xx、3xx 为健康
+	ExpectStatus []int `json:"expect_status,omitempty"`
+	// Interval、Timeout 探测间隔与超时，如 "10s"
+	Interval eocontext.Duration `json:"interval,omitempty"`
+	Timeout  eocontext.Duration `json:"timeout,omitempty"`
+	// Healthy 连续成功多少次后恢复节点，Unhealthy 连续失败多少次后下线节点
+	Healthy   int `json:"healthy,omitempty"`
+	Unhealthy int `json:"unhealthy,omitempty"`
+}
+
+// PassiveConfig 被动检测配置，根据转发结果判断节点异常
+type PassiveConfig struct {
+	// Failures 连续多少次转发失败（连接失败或 5xx）后下线节点
+	Failures int `json:"failures,omitempty"`
+	// Ejection 未配置主动探测时，节点下线多久后重新上线
+	Ejection eocontext.Duration `json:"ejection,omitempty"`
+}
+
+func (c *Config) check() error {
+	if c == nil {
+		return ErrorNoConfig
+	}
+	if c.Active == nil && c.Passive == nil {
+		return ErrorNothingToCheck
+	}
+	if a := c.Active; a != nil {
+		switch a.Type {
+		case ProbeTCP, ProbeHTTP:
+		default:
+			return ErrorUnknownProbe
+		}
+		if a.Path == "" {
+			a.Path = "/"
+		}
+		if a.Interval <= 0 {
+			a.Interval = eocontext.Duration(defaultInterval)
+		}
+		if a.Timeout <= 0 {
+			a.Timeout = eocontext.Duration(defaultTimeout)
+		}
+		if a.Healthy <= 0 {
+			a.Healthy = defaultThreshold
+		}
+		if a.Unhealthy <= 0 {
+			a.Unhealthy = defaultThreshold
+		}
+	}
+	if p := c.Passive; p != nil {
+		if p.Failures <= 0 {
+			p.Failures = defaultThreshold
+		}
+		if p.Ejection <= 0 {
+			p.Ejection = eocontext.Duration(defaultEjection)
+		}
+	}
+	return nil
+}
+
+func (a *ActiveConfig) expect(code int) bool {
+	if len(a.ExpectStatus) == 0 {
+		return code >= 200 && code < 400
+	}
+	for _, s := range a.ExpectStatus {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package health
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+	"github.com/eolinker/eosc/eocontext/eotest"
+	http_context "github.com/eolinker/eosc/eocontext/http-context"
+)
+
+type testURI struct {
+	http_context.IURIWriter
+	host string
+}
+
+func (u *testURI) Host() string { return u.host }
+
+type testProxy struct {
+	http_context.IProxy
+	host string
+	code int
+}
+
+func (p *testProxy) URI() http_context.IURIWriter { return &testURI{host: p.host} }
+func (p *testProxy) StatusCode() int              { return p.code }
+
+type testContext struct {
+	http_context.IHttpContext
+	proxies []http_context.IProxy
+}
+
+func (c *testContext) Proxies() []http_context.IProxy { return c.proxies }
+
+func newNode(addr string) *eotest.Node {
+	return eotest.NewNode(addr, nil)
+}
+
+func TestActiveHTTP(t *testing.T) {
+	var code int32 = http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(int(atomic.LoadInt32(&code)))
+	}))
+	defer srv.Close()
+	node := newNode(srv.Listener.Addr().String())
+	c, err := NewChecker(eotest.NewApp("", node), &Config{Active: &ActiveConfig{Type: ProbeHTTP, Path: "/health", Healthy: 2, Unhealthy: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []*Event
+	c.OnChange(func(e *Event) { events = append(events, e) })
+
+	steps := []struct {
+		code int32
+		want eocontext.NodeStatus
+	}{
+		{code: http.StatusOK, want: eocontext.Running},
+		{code: http.StatusServiceUnavailable, want: eocontext.Running},
+		{code: http.StatusServiceUnavailable, want: eocontext.Down},
+		{code: http.StatusOK, want: eocontext.Down},
+		{code: http.StatusOK, want: eocontext.Running},
+	}
+	for i, s := range steps {
+		atomic.StoreInt32(&code, s.code)
+		c.Check()
+		if got := node.Status(); got != s.want {
+			t.Fatalf("step %d: status = %d, want %d", i, got, s.want)
+		}
+	}
+	if len(events) != 2 || events[0].Status != eocontext.Down || events[1].Status != eocontext.Running {
+		t.Errorf("events = %v", events)
+	}
+}
+
+func TestActiveTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	up := newNode(l.Addr().String())
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	down := newNode(closed.Addr().String())
+	closed.Close()
+	leave := newNode(closed.Addr().String())
+	leave.Leave()
+	defer l.Close()
+
+	c, err := NewChecker(eotest.NewApp("", up, down, leave), &Config{Active: &ActiveConfig{Type: ProbeTCP, Unhealthy: 1, Timeout: eocontext.Duration(time.Second)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Check()
+	if up.Status() != eocontext.Running || down.Status() != eocontext.Down || leave.Status() != eocontext.Leave {
+		t.Errorf("status = %d %d %d", up.Status(), down.Status(), leave.Status())
+	}
+}
+
+func TestPassive(t *testing.T) {
+	node := newNode("10.0.0.1:80")
+	c, err := NewChecker(eotest.NewApp("", node), &Config{Passive: &PassiveConfig{Failures: 2, Ejection: eocontext.Duration(50 * time.Millisecond)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+	changed := make(chan *Event, 2)
+	c.OnChange(func(e *Event) { changed <- e })
+
+	observe := func(code int) {
+		c.Observe(&testContext{proxies: []http_context.IProxy{&testProxy{host: node.Addr(), code: code}}})
+	}
+	observe(http.StatusBadGateway)
+	observe(http.StatusOK)
+	observe(0)
+	if node.Status() != eocontext.Running {
+		t.Fatal("failures should be consecutive")
+	}
+	observe(http.StatusInternalServerError)
+	if e := <-changed; e.Status != eocontext.Down || node.Status() != eocontext.Down {
+		t.Fatalf("node not ejected: %v", e)
+	}
+	select {
+	case e := <-changed:
+		if e.Status != eocontext.Running || node.Status() != eocontext.Running {
+			t.Errorf("node not restored: %v", e)
+		}
+	case <-time.After(time.Second):
+		t.Error("ejection not expired")
+	}
+}
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		conf *Config
+		want error
+	}{
+		{name: "nil", conf: nil, want: ErrorNoConfig},
+		{name: "empty", conf: &Config{}, want: ErrorNothingToCheck},
+		{name: "unknown", conf: &Config{Active: &ActiveConfig{Type: "udp"}}, want: ErrorUnknownProbe},
+		{name: "passive", conf: &Config{Passive: &PassiveConfig{}}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewChecker(eotest.NewApp(""), tt.conf); err != tt.want {
+				t.Errorf("NewChecker() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    *ActiveConfig
+		wantErr bool
+	}{
+		{name: "string", body: `{"type":"tcp","interval":"5s","timeout":"500ms"}`, want: &ActiveConfig{Interval: eocontext.Duration(5 * time.Second), Timeout: eocontext.Duration(500 * time.Millisecond)}},
+		{name: "milliseconds", body: `{"type":"tcp","interval":5000,"timeout":500}`, want: &ActiveConfig{Interval: eocontext.Duration(5 * time.Second), Timeout: eocontext.Duration(500 * time.Millisecond)}},
+		{name: "default", body: `{"type":"tcp"}`, want: &ActiveConfig{Interval: eocontext.Duration(defaultInterval), Timeout: eocontext.Duration(defaultTimeout)}},
+		{name: "invalid", body: `{"type":"tcp","interval":"5"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &Config{Active: new(ActiveConfig)}
+			err := json.Unmarshal([]byte(tt.body), conf.Active)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			conf.check()
+			if conf.Active.Interval != tt.want.Interval || conf.Active.Timeout != tt.want.Timeout {
+				t.Errorf("interval = %s, timeout = %s", conf.Active.Interval.Duration(), conf.Active.Timeout.Duration())
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+type prober interface {
+	probe(node eocontext.INode) error
+}
+
+func newProber(scheme string, conf *ActiveConfig) prober {
+	if conf.Type == ProbeTCP {
+		return &tcpProber{conf: conf}
+	}
+	if scheme == "" {
+		scheme = "http"
+	}
+	return &httpProber{
+		conf:   conf,
+		scheme: scheme,
+		client: &http.Client{
+			Timeout: conf.Timeout.Duration(),
+			// 探测结果以节点本身的返回为准
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+			Transport: &http.Transport{DisableKeepAlives: true},
+		},
+	}
+}
+
+type tcpProber struct {
+	conf *ActiveConfig
+}
+
+func (p *tcpProber) probe(node eocontext.INode) error {
+	conn, err := net.DialTimeout("tcp", node.Addr(), p.conf.Timeout.Duration())
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+type httpProber struct {
+	conf   *ActiveConfig
+	scheme string
+	client *http.Client
+}
+
+func (p *httpProber) probe(node eocontext.INode) error {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s://%s%s", p.scheme, node.Addr(), p.conf.Path), nil)
+	if err != nil {
+		return err
+	}
+	if p.conf.Host != "" {
+		req.Host = p.conf.Host
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if !p.conf.expect(resp.StatusCode) {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
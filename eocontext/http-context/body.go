@@ -0,0 +1,152 @@
+package http_context
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+)
+
+var (
+	ErrorBodyConsumed = errors.New("body stream already consumed")
+)
+
+// BufferMode 需要缓冲在内存中的 body
+type BufferMode int
+
+const (
+	BufferNone     BufferMode = 0
+	BufferRequest  BufferMode = 1 << 0
+	BufferResponse BufferMode = 1 << 1
+)
+
+// IBuffering 支持流式 body 的上下文实现该接口；filter 需要按 []byte 读写 body 时通过 RequireBuffering 声明
+type IBuffering interface {
+	Buffering() BufferMode
+	SetBuffering(mode BufferMode)
+}
+
+// IRequestBodyStream 请求 body 的流式读取，由 IBodyDataReader 的实现选择实现
+type IRequestBodyStream interface {
+	// BodyStream 请求 body，未要求缓冲时只能读取一次
+	BodyStream() (io.Reader, error)
+}
+
+// IResponseStream 流式转发上游的返回，由 IResponse 的实现选择实现
+type IResponseStream interface {
+	// BodyReader 上游返回的 body，未要求缓冲时只能读取一次
+	BodyReader() (io.Reader, error)
+	// SetBodyReader 替换写给客户端的 body，可以包装 BodyReader 做增量处理
+	SetBodyReader(r io.Reader)
+	// BodyWriter 直接写给客户端，首次写入时先发送状态与 header
+	BodyWriter() io.Writer
+}
+
+// RequireBuffering 声明 filter 需要完整的 body，上下文不支持流式 body 时总是缓冲
+func RequireBuffering(ctx IHttpContext, mode BufferMode) {
+	if b, ok := ctx.(IBuffering); ok {
+		b.SetBuffering(b.Buffering() | mode)
+	}
+}
+
+// RequestBody 流式读取请求 body，不支持时读取 RawBody
+func RequestBody(ctx IHttpContext) (io.Reader, error) {
+	body := ctx.Request().Body()
+	if s, ok := body.(IRequestBodyStream); ok {
+		return s.BodyStream()
+	}
+	data, err := body.RawBody()
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// ResponseBody 流式读取返回 body，不支持时读取 GetBody
+func ResponseBody(ctx IHttpContext) (io.Reader, error) {
+	resp := ctx.Response()
+	if s, ok := resp.(IResponseStream); ok {
+		return s.BodyReader()
+	}
+	return bytes.NewReader(resp.GetBody()), nil
+}
+
+// Body 请求或返回的 body：按流读取时只能读取一次；调用 Bytes 后缓冲在内存中，之后可以重复读取
+// 供上下文的实现使用，缓冲后 GetBody、RawBody 等按 []byte 读写的方法与流式方法结果一致
+type Body struct {
+	locker   sync.Mutex
+	stream   io.Reader
+	data     []byte
+	buffered bool
+	consumed bool
+}
+
+func NewBody(stream io.Reader) *Body {
+	return &Body{stream: stream}
+}
+
+func NewBufferedBody(data []byte) *Body {
+	return &Body{data: data, buffered: true}
+}
+
+// Buffered body 是否已在内存中
+func (b *Body) Buffered() bool {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	return b.buffered
+}
+
+// Reader 已缓冲时每次返回新的 reader，否则返回原始的流，且只能获取一次
+func (b *Body) Reader() (io.Reader, error) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	if b.buffered {
+		return bytes.NewReader(b.data), nil
+	}
+	if b.consumed {
+		return nil, ErrorBodyConsumed
+	}
+	b.consumed = true
+	if b.stream == nil {
+		return bytes.NewReader(nil), nil
+	}
+	return b.stream, nil
+}
+
+// Bytes 读取完整的 body 并缓冲，原始的流已被 Reader 取走时返回 ErrorBodyConsumed
+func (b *Body) Bytes() ([]byte, error) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	if b.buffered {
+		return b.data, nil
+	}
+	if b.consumed {
+		return nil, ErrorBodyConsumed
+	}
+	if b.stream != nil {
+		data, err := io.ReadAll(b.stream)
+		if err != nil {
+			return nil, err
+		}
+		b.data = data
+	}
+	b.stream = nil
+	b.buffered = true
+	return b.data, nil
+}
+
+// Set 用 data 替换 body
+func (b *Body) Set(data []byte) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	b.data, b.stream = data, nil
+	b.buffered, b.consumed = true, false
+}
+
+// SetReader 用流替换 body
+func (b *Body) SetReader(stream io.Reader) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	b.data, b.stream = nil, stream
+	b.buffered, b.consumed = false, false
+}
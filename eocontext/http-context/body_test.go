@@ -0,0 +1,63 @@
+package http_context
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestBody(t *testing.T) {
+	tests := []struct {
+		name string
+		// steps r: Reader，b: Bytes
+		steps   string
+		body    *Body
+		want    []string
+		wantErr []bool
+	}{
+		{name: "stream once", steps: "rr", body: NewBody(strings.NewReader("stream")), want: []string{"stream", ""}, wantErr: []bool{false, true}},
+		{name: "buffer then read", steps: "brr", body: NewBody(strings.NewReader("buffer")), want: []string{"buffer", "buffer", "buffer"}, wantErr: []bool{false, false, false}},
+		{name: "stream then buffer", steps: "rb", body: NewBody(strings.NewReader("stream")), want: []string{"stream", ""}, wantErr: []bool{false, true}},
+		{name: "buffered", steps: "rbr", body: NewBufferedBody([]byte("data")), want: []string{"data", "data", "data"}, wantErr: []bool{false, false, false}},
+		{name: "empty", steps: "br", body: NewBody(nil), want: []string{"", ""}, wantErr: []bool{false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, step := range tt.steps {
+				var got []byte
+				var err error
+				if step == 'r' {
+					var r io.Reader
+					r, err = tt.body.Reader()
+					if err == nil {
+						got, _ = io.ReadAll(r)
+					}
+				} else {
+					got, err = tt.body.Bytes()
+				}
+				if (err != nil) != tt.wantErr[i] {
+					t.Fatalf("step %d: error = %v", i, err)
+				}
+				if string(got) != tt.want[i] {
+					t.Errorf("step %d: got %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBodySet(t *testing.T) {
+	b := NewBody(strings.NewReader("origin"))
+	b.Reader()
+	b.Set([]byte("replaced"))
+	if data, err := b.Bytes(); err != nil || string(data) != "replaced" {
+		t.Errorf("Bytes() = %q, %v", data, err)
+	}
+	b.SetReader(strings.NewReader("stream"))
+	if b.Buffered() {
+		t.Error("buffered after SetReader")
+	}
+	if data, err := b.Bytes(); err != nil || string(data) != "stream" {
+		t.Errorf("Bytes() = %q, %v", data, err)
+	}
+}
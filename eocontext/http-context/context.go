@@ -24,6 +24,7 @@ type IHttpContext interface {
 	Request() IRequestReader // 读取原始请求
 	Proxy() IRequest         // 读写转发请求
 	Response() IResponse     // 处理返回结果，可读可写
+	// SendTo 转发到 address；开启追踪时 trace.Tracer.Begin 返回的上下文会记录 send span
 	SendTo(address string, timeout time.Duration) error
 	Proxies() []IProxy
 	FastFinish()
@@ -53,6 +54,8 @@ type IResponseHeader interface {
 	AddHeader(key, value string)
 	DelHeader(key string)
 }
+
+// IBodyGet 支持流式返回的上下文中，需要先通过 RequireBuffering 声明 BufferResponse
 type IBodyGet interface {
 	GetBody() []byte
 	BodyLen() int
@@ -77,6 +80,7 @@ type IBodyDataReader interface {
 	Files() (map[string][]*multipart.FileHeader, error)
 	GetForm(key string) string
 	GetFile(key string) (file []*multipart.FileHeader, has bool)
+	// RawBody 支持流式请求的上下文中，需要先通过 RequireBuffering 声明 BufferRequest
 	RawBody() ([]byte, error)
 }
 
@@ -0,0 +1,244 @@
+package http_context
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+	"github.com/fasthttp/websocket"
+)
+
+const (
+	writeControlTimeout = time.Second
+)
+
+var (
+	ErrorWebsocketIdle = errors.New("websocket idle timeout")
+)
+
+// MessageDirection websocket 消息的方向
+type MessageDirection int
+
+const (
+	// ClientToUpstream 客户端发往上游
+	ClientToUpstream MessageDirection = iota
+	// UpstreamToClient 上游返回给客户端
+	UpstreamToClient
+)
+
+// MessageAction filter 对消息的处理结果
+type MessageAction int
+
+const (
+	// MessagePass 继续交给下一个 filter，全部通过后转发
+	MessagePass MessageAction = iota
+	// MessageDrop 丢弃消息，连接保持
+	MessageDrop
+	// MessageClose 关闭两端的连接，关闭码与原因取自 CloseCode、CloseReason
+	MessageClose
+)
+
+// WebsocketMessage 一条 websocket 数据消息，filter 可以修改 Opcode 与 Payload
+type WebsocketMessage struct {
+	Direction MessageDirection
+	// Opcode websocket.TextMessage 或 websocket.BinaryMessage
+	Opcode  int
+	Payload []byte
+
+	CloseCode   int
+	CloseReason string
+}
+
+// WebsocketMessageFilter 升级后对每条数据消息调用，控制帧（ping、pong、close）不经过 filter
+type WebsocketMessageFilter interface {
+	DoWebsocketMessage(ctx IWebsocketContext, msg *WebsocketMessage) (MessageAction, error)
+}
+
+// WebsocketOptions 按路由配置的 websocket 转发参数，为 0 的项不生效
+type WebsocketOptions struct {
+	// MaxMessageSize 单条消息的最大字节数，超过时以 1009 关闭
+	MaxMessageSize int64 `json:"max_message_size,omitempty"`
+	// PingInterval 向客户端发送 ping 的间隔，PongTimeout 内没有收到 pong 时关闭连接，PongTimeout 为 0 时取 PingInterval
+	PingInterval eocontext.Duration `json:"ping_interval,omitempty"`
+	PongTimeout  eocontext.Duration `json:"pong_timeout,omitempty"`
+	// IdleTimeout 两个方向都没有数据消息的时长上限
+	IdleTimeout eocontext.Duration `json:"idle_timeout,omitempty"`
+}
+
+// IWebsocketMessageContext 支持消息级 filter 的 websocket 上下文，由 IWebsocketContext 的实现选择实现
+type IWebsocketMessageContext interface {
+	IWebsocketContext
+	AddMessageFilter(filter WebsocketMessageFilter)
+	MessageFilters() []WebsocketMessageFilter
+	SetWebsocketOptions(options *WebsocketOptions)
+	WebsocketOptions() *WebsocketOptions
+}
+
+// PipeWebsocket 在客户端与上游连接之间转发消息直到任一端关闭，每条数据消息依次经过 filters
+func PipeWebsocket(ctx IWebsocketContext, client, upstream *websocket.Conn, options *WebsocketOptions, filters []WebsocketMessageFilter) error {
+	if options == nil {
+		options = &WebsocketOptions{}
+	}
+	p := &websocketPipe{
+		ctx:      ctx,
+		client:   client,
+		upstream: upstream,
+		options:  options,
+		filters:  filters,
+		done:     make(chan struct{}),
+	}
+	return p.run()
+}
+
+type websocketPipe struct {
+	ctx      IWebsocketContext
+	client   *websocket.Conn
+	upstream *websocket.Conn
+	options  *WebsocketOptions
+	filters  []WebsocketMessageFilter
+
+	lastActive int64
+	once       sync.Once
+	err        error
+	done       chan struct{}
+}
+
+func (p *websocketPipe) run() error {
+	if p.options.MaxMessageSize > 0 {
+		p.client.SetReadLimit(p.options.MaxMessageSize)
+		p.upstream.SetReadLimit(p.options.MaxMessageSize)
+	}
+	if p.options.PingInterval > 0 {
+		pongTimeout := p.options.PongTimeout.Duration()
+		if pongTimeout <= 0 {
+			pongTimeout = p.options.PingInterval.Duration()
+		}
+		wait := p.options.PingInterval.Duration() + pongTimeout
+		p.client.SetReadDeadline(time.Now().Add(wait))
+		p.client.SetPongHandler(func(string) error {
+			return p.client.SetReadDeadline(time.Now().Add(wait))
+		})
+	}
+	p.active()
+
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		p.pump(p.client, p.upstream, ClientToUpstream)
+	}()
+	go func() {
+		defer wg.Done()
+		p.pump(p.upstream, p.client, UpstreamToClient)
+	}()
+	go func() {
+		defer wg.Done()
+		p.keepalive()
+	}()
+	wg.Wait()
+	return p.err
+}
+
+func (p *websocketPipe) active() {
+	atomic.StoreInt64(&p.lastActive, time.Now().UnixNano())
+}
+
+// pump 读取 src 的消息，经过 filter 后写往 dst
+func (p *websocketPipe) pump(src, dst *websocket.Conn, direction MessageDirection) {
+	for {
+		opcode, payload, err := src.ReadMessage()
+		if err != nil {
+			code, reason := websocket.CloseGoingAway, ""
+			if ce, ok := err.(*websocket.CloseError); ok {
+				code, reason = ce.Code, ce.Text
+				err = nil
+			} else if err == websocket.ErrReadLimit {
+				code = websocket.CloseMessageTooBig
+			}
+			p.close(code, reason, err)
+			return
+		}
+		p.active()
+		msg := &WebsocketMessage{Direction: direction, Opcode: opcode, Payload: payload}
+		action, err := p.filter(msg)
+		if err != nil {
+			p.close(websocket.CloseInternalServerErr, "", err)
+			return
+		}
+		switch action {
+		case MessageDrop:
+			continue
+		case MessageClose:
+			code := msg.CloseCode
+			if code == 0 {
+				code = websocket.ClosePolicyViolation
+			}
+			p.close(code, msg.CloseReason, nil)
+			return
+		}
+		if err := dst.WriteMessage(msg.Opcode, msg.Payload); err != nil {
+			p.close(websocket.CloseGoingAway, "", err)
+			return
+		}
+	}
+}
+
+func (p *websocketPipe) filter(msg *WebsocketMessage) (MessageAction, error) {
+	for _, f := range p.filters {
+		action, err := f.DoWebsocketMessage(p.ctx, msg)
+		if err != nil || action != MessagePass {
+			return action, err
+		}
+	}
+	return MessagePass, nil
+}
+
+// keepalive 定时向客户端发送 ping，并检查空闲时长
+func (p *websocketPipe) keepalive() {
+	var ping, idle <-chan time.Time
+	if p.options.PingInterval > 0 {
+		t := time.NewTicker(p.options.PingInterval.Duration())
+		defer t.Stop()
+		ping = t.C
+	}
+	if p.options.IdleTimeout > 0 {
+		t := time.NewTicker(p.options.IdleTimeout.Duration() / 2)
+		defer t.Stop()
+		idle = t.C
+	}
+	for {
+		select {
+		case <-p.done:
+			return
+		case now := <-ping:
+			if err := p.client.WriteControl(websocket.PingMessage, nil, now.Add(writeControlTimeout)); err != nil {
+				p.close(websocket.CloseGoingAway, "", err)
+				return
+			}
+		case now := <-idle:
+			if now.Sub(time.Unix(0, atomic.LoadInt64(&p.lastActive))) >= p.options.IdleTimeout.Duration() {
+				p.close(websocket.CloseGoingAway, "idle timeout", ErrorWebsocketIdle)
+				return
+			}
+		}
+	}
+}
+
+// close 向两端发送关闭帧并关闭连接，只执行一次
+func (p *websocketPipe) close(code int, reason string, err error) {
+	p.once.Do(func() {
+		p.err = err
+		msg := websocket.FormatCloseMessage(code, reason)
+		if code == websocket.CloseNoStatusReceived || code == websocket.CloseAbnormalClosure {
+			msg = websocket.FormatCloseMessage(websocket.CloseGoingAway, "")
+		}
+		deadline := time.Now().Add(writeControlTimeout)
+		p.client.WriteControl(websocket.CloseMessage, msg, deadline)
+		p.upstream.WriteControl(websocket.CloseMessage, msg, deadline)
+		p.client.Close()
+		p.upstream.Close()
+		close(p.done)
+	})
+}
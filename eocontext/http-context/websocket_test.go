@@ -0,0 +1,201 @@
+package http_context
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+	"github.com/fasthttp/websocket"
+)
+
+type messageFilterFunc func(msg *WebsocketMessage) (MessageAction, error)
+
+func (f messageFilterFunc) DoWebsocketMessage(ctx IWebsocketContext, msg *WebsocketMessage) (MessageAction, error) {
+	return f(msg)
+}
+
+// testFilters 客户端的消息转为大写，丢弃 secret，收到 bye 时关闭；上游的回复加上前缀
+var testFilters = []WebsocketMessageFilter{
+	messageFilterFunc(func(msg *WebsocketMessage) (MessageAction, error) {
+		if msg.Direction == UpstreamToClient {
+			msg.Payload = append([]byte("echo:"), msg.Payload...)
+			return MessagePass, nil
+		}
+		switch string(msg.Payload) {
+		case "secret":
+			return MessageDrop, nil
+		case "bye":
+			msg.CloseCode, msg.CloseReason = websocket.CloseNormalClosure, "bye"
+			return MessageClose, nil
+		}
+		msg.Payload = bytes.ToUpper(msg.Payload)
+		return MessagePass, nil
+	}),
+}
+
+var upgrader = websocket.Upgrader{}
+
+func wsURL(srv *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func newProxy(t *testing.T, options *WebsocketOptions, handler func(conn *websocket.Conn)) (*httptest.Server, chan error) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handler(conn)
+	}))
+	t.Cleanup(upstream.Close)
+	result := make(chan error, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		up, _, err := websocket.DefaultDialer.Dial(wsURL(upstream), nil)
+		if err != nil {
+			client.Close()
+			result <- err
+			return
+		}
+		result <- PipeWebsocket(nil, client, up, options, testFilters)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy, result
+}
+
+func echo(conn *websocket.Conn) {
+	for {
+		opcode, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.WriteMessage(opcode, data)
+	}
+}
+
+func TestPipeWebsocket(t *testing.T) {
+	proxy, result := newProxy(t, nil, echo)
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL(proxy), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	for _, m := range []string{"hello", "secret", "world"} {
+		conn.WriteMessage(websocket.TextMessage, []byte(m))
+	}
+	for _, want := range []string{"echo:HELLO", "echo:WORLD"} {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("got %q, want %q", data, want)
+		}
+	}
+	conn.WriteMessage(websocket.TextMessage, []byte("bye"))
+	_, _, err = conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("read after bye: %v", err)
+	}
+	if err := <-result; err != nil {
+		t.Errorf("PipeWebsocket() error = %v", err)
+	}
+}
+
+func TestPipeWebsocketLimits(t *testing.T) {
+	t.Run("message size", func(t *testing.T) {
+		closed := make(chan error, 1)
+		proxy, _ := newProxy(t, &WebsocketOptions{MaxMessageSize: 8}, func(conn *websocket.Conn) {
+			_, _, err := conn.ReadMessage()
+			closed <- err
+		})
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL(proxy), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		conn.WriteMessage(websocket.TextMessage, []byte("too large message"))
+		if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+			t.Errorf("read after large message: %v", err)
+		}
+		if err := <-closed; !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+			t.Errorf("upstream closed with %v", err)
+		}
+	})
+	t.Run("idle", func(t *testing.T) {
+		proxy, result := newProxy(t, &WebsocketOptions{IdleTimeout: eocontext.Duration(100 * time.Millisecond)}, echo)
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL(proxy), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+			t.Errorf("read while idle: %v", err)
+		}
+		if err := <-result; err != ErrorWebsocketIdle {
+			t.Errorf("PipeWebsocket() error = %v", err)
+		}
+	})
+	pingTests := []struct {
+		name    string
+		options *WebsocketOptions
+	}{
+		{name: "ping", options: &WebsocketOptions{PingInterval: eocontext.Duration(20 * time.Millisecond), PongTimeout: eocontext.Duration(20 * time.Millisecond)}},
+		{name: "ping without pong timeout", options: &WebsocketOptions{PingInterval: eocontext.Duration(20 * time.Millisecond)}},
+	}
+	for _, tt := range pingTests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy, _ := newProxy(t, tt.options, echo)
+			conn, _, err := websocket.DefaultDialer.Dial(wsURL(proxy), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			pings := make(chan struct{}, 16)
+			conn.SetPingHandler(func(data string) error {
+				pings <- struct{}{}
+				return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+			})
+			messages := make(chan string, 1)
+			go func() {
+				for {
+					_, data, err := conn.ReadMessage()
+					if err != nil {
+						close(messages)
+						return
+					}
+					messages <- string(data)
+				}
+			}()
+			for i := 0; i < 5; i++ {
+				select {
+				case <-pings:
+				case <-time.After(time.Second):
+					t.Fatal("no ping from proxy")
+				}
+			}
+			// 回复 pong 的客户端连接保持
+			conn.WriteMessage(websocket.TextMessage, []byte("alive"))
+			select {
+			case got := <-messages:
+				if got != "echo:ALIVE" {
+					t.Errorf("got %q after pings", got)
+				}
+			case <-time.After(time.Second):
+				t.Error("no reply after pings")
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+	"github.com/eolinker/eosc/eocontext/balance"
+	http_context "github.com/eolinker/eosc/eocontext/http-context"
+	"github.com/eolinker/eosc/log"
+)
+
+const (
+	defaultTimeout     = 10 * time.Second
+	defaultConcurrency = 64
+	defaultMaxBodySize = 1 << 20
+)
+
+var (
+	ErrorNoTarget = errors.New("mirror target requires app or address")
+	ErrorDropped  = errors.New("mirror dropped: too many in-flight requests")
+	ErrorBodySize = errors.New("mirror skipped: request body too large or of unknown length")
+)
+
+// hopHeaders 逐跳的 header，只对当前连接有效，不随镜像请求发送
+var hopHeaders = []string{
+	"Connection",
+	"Proxy-Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// Config 镜像配置
+type Config struct {
+	// Ratio 镜像的请求比例，0 到 1
+	Ratio float64 `json:"ratio"`
+	// Timeout 镜像请求的超时，如 "3s"，为 0 时使用 app 的超时
+	Timeout eocontext.Duration `json:"timeout,omitempty"`
+	// Concurrency 同时进行的镜像请求数上限，超过时丢弃
+	Concurrency int `json:"concurrency,omitempty"`
+	// MaxBodySize 镜像请求 body 的上限（字节），超过或长度未知（chunked）时不镜像，为 0 时使用 1MB
+	MaxBodySize int `json:"max_body_size,omitempty"`
+}
+
+// Target 镜像的目标，App 不为空时通过 Balance 选择节点，否则发往 Address（scheme://host:port）
+type Target struct {
+	App     eocontext.EoApp
+	Balance eocontext.BalanceHandler
+	Address string
+}
+
+// Mirror 把转发请求的副本异步发往另一个目标，忽略返回，不影响客户端的响应与耗时
+type Mirror struct {
+	target Target
+	conf   Config
+	client *http.Client
+	sem    chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewMirror(target Target, conf Config) (*Mirror, error) {
+	if target.App == nil && target.Address == "" {
+		return nil, ErrorNoTarget
+	}
+	if target.App != nil && target.Balance == nil {
+		target.Balance = balance.NewRoundRobin()
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = eocontext.Duration(defaultTimeout)
+		if target.App != nil && target.App.TimeOut() > 0 {
+			conf.Timeout = eocontext.Duration(target.App.TimeOut())
+		}
+	}
+	if conf.Concurrency <= 0 {
+		conf.Concurrency = defaultConcurrency
+	}
+	if conf.MaxBodySize <= 0 {
+		conf.MaxBodySize = defaultMaxBodySize
+	}
+	return &Mirror{
+		target: target,
+		conf:   conf,
+		client: &http.Client{
+			Timeout: conf.Timeout.Duration(),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		sem: make(chan struct{}, conf.Concurrency),
+	}, nil
+}
+
+// Send 按比例镜像 ctx 当前的转发请求，请求在返回前复制完成，发送在后台进行；返回是否发起了镜像
+func (m *Mirror) Send(ctx http_context.IHttpContext) bool {
+	if m.conf.Ratio <= 0 || (m.conf.Ratio < 1 && rand.Float64() >= m.conf.Ratio) {
+		return false
+	}
+	records := recordsOf(ctx)
+	req, finish, err := m.clone(ctx)
+	if err != nil {
+		records.add(&Record{Time: time.Now(), Done: true, Err: err.Error()})
+		log.Debug("mirror ", ctx.RequestId(), ": ", err)
+		return false
+	}
+	i := records.add(&Record{Address: req.URL.Host, Method: req.Method, Path: req.URL.RequestURI(), Time: time.Now()})
+	select {
+	case m.sem <- struct{}{}:
+	default:
+		records.done(i, 0, ErrorDropped)
+		finish()
+		return false
+	}
+	m.wg.Add(1)
+	go func() {
+		defer func() {
+			<-m.sem
+			finish()
+			m.wg.Done()
+		}()
+		code, err := m.do(req)
+		records.done(i, code, err)
+	}()
+	return true
+}
+
+// Wait 等待进行中的镜像请求结束
+func (m *Mirror) Wait() {
+	m.wg.Wait()
+}
+
+func (m *Mirror) do(req *http.Request) (int, error) {
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// clone 复制转发请求，发往 app 时返回的 finish 用于在镜像结束后通知负载均衡
+// 只有 body 不超过 MaxBodySize 时才要求缓冲请求 body，不影响大请求的流式转发
+func (m *Mirror) clone(ctx http_context.IHttpContext) (*http.Request, func(), error) {
+	proxy := ctx.Proxy()
+	size := proxy.ContentLength()
+	if size < 0 || size > m.conf.MaxBodySize {
+		return nil, nil, ErrorBodySize
+	}
+	var body []byte
+	if size > 0 {
+		http_context.RequireBuffering(ctx, http_context.BufferRequest)
+		data, err := proxy.Body().RawBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		body = data
+	}
+
+	finish := func() {}
+	address := m.target.Address
+	if m.target.App != nil {
+		mc := &mirrorContext{EoContext: ctx, app: m.target.App}
+		node, err := m.target.Balance.Select(mc)
+		if err != nil {
+			return nil, nil, err
+		}
+		scheme := m.target.App.Scheme()
+		if scheme == "" {
+			scheme = "http"
+		}
+		address = scheme + "://" + node.Addr()
+		finish = mc.done
+	}
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	uri := proxy.URI()
+	url := address + uri.Path()
+	if q := uri.RawQuery(); q != "" {
+		url += "?" + q
+	}
+	req, err := http.NewRequestWithContext(context.Background(), proxy.Method(), url, bytes.NewReader(body))
+	if err != nil {
+		finish()
+		return nil, nil, err
+	}
+	req.Header = proxy.Header().Headers().Clone()
+	removeHopHeaders(req.Header)
+	if host := uri.Host(); host != "" {
+		req.Host = host
+	}
+	return req, finish, nil
+}
+
+// removeHopHeaders 删除逐跳的 header 以及 Connection 中列出的 header
+func removeHopHeaders(header http.Header) {
+	for _, v := range header.Values("Connection") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				header.Del(name)
+			}
+		}
+	}
+	for _, name := range hopHeaders {
+		header.Del(name)
+	}
+}
+
+// mirrorContext 负载均衡按镜像的 app 选择节点，FinishHandler 不影响原请求
+type mirrorContext struct {
+	eocontext.EoContext
+	app    eocontext.EoApp
+	finish eocontext.FinishHandler
+}
+
+func (c *mirrorContext) GetApp() eocontext.EoApp {
+	return c.app
+}
+
+func (c *mirrorContext) GetFinish() eocontext.FinishHandler {
+	return c.finish
+}
+
+func (c *mirrorContext) SetFinish(handler eocontext.FinishHandler) {
+	c.finish = handler
+}
+
+func (c *mirrorContext) done() {
+	if c.finish != nil {
+		c.finish.Finish(c)
+	}
+}
@@ -0,0 +1,198 @@
+package mirror
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext/eotest"
+	http_context "github.com/eolinker/eosc/eocontext/http-context"
+)
+
+type testURI struct {
+	http_context.IURIWriter
+}
+
+func (u *testURI) Path() string     { return "/orders" }
+func (u *testURI) RawQuery() string { return "id=1" }
+func (u *testURI) Host() string     { return "api.example.com" }
+
+type testHeader struct {
+	http_context.IHeaderWriter
+	header http.Header
+}
+
+func (h *testHeader) Headers() http.Header { return h.header }
+
+type testBody struct {
+	http_context.IBodyDataWriter
+}
+
+func (b *testBody) RawBody() ([]byte, error) { return []byte("payload"), nil }
+
+type testRequest struct {
+	http_context.IRequest
+	header *testHeader
+	length int
+}
+
+func (r *testRequest) Method() string                     { return http.MethodPost }
+func (r *testRequest) ContentLength() int                 { return r.length }
+func (r *testRequest) URI() http_context.IURIWriter       { return &testURI{} }
+func (r *testRequest) Header() http_context.IHeaderWriter { return r.header }
+func (r *testRequest) Body() http_context.IBodyDataWriter { return &testBody{} }
+
+type testContext struct {
+	http_context.IHttpContext
+	locker sync.Mutex
+	values map[interface{}]interface{}
+	header *testHeader
+	length int
+}
+
+func newContext() *testContext {
+	header := http.Header{
+		"X-Test":     []string{"1"},
+		"Connection": []string{"keep-alive, X-Hop"},
+		"X-Hop":      []string{"1"},
+		"Keep-Alive": []string{"timeout=5"},
+	}
+	return &testContext{
+		values: make(map[interface{}]interface{}),
+		header: &testHeader{header: header},
+		length: len("payload"),
+	}
+}
+
+func (c *testContext) RequestId() string { return "test" }
+func (c *testContext) Value(key interface{}) interface{} {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	return c.values[key]
+}
+func (c *testContext) WithValue(key, val interface{}) {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	c.values[key] = val
+}
+func (c *testContext) Proxy() http_context.IRequest {
+	return &testRequest{header: c.header, length: c.length}
+}
+
+func TestSend(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		received <- r
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	targets := map[string]Target{
+		"address": {Address: srv.URL},
+		"app":     {App: eotest.NewApp("", eotest.NewNode(srv.Listener.Addr().String(), nil))},
+	}
+	for name, target := range targets {
+		t.Run(name, func(t *testing.T) {
+			m, err := NewMirror(target, Config{Ratio: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := newContext()
+			if !m.Send(ctx) {
+				t.Fatal("not mirrored")
+			}
+			m.Wait()
+			r := <-received
+			body, _ := io.ReadAll(r.Body)
+			if r.Method != http.MethodPost || r.URL.RequestURI() != "/orders?id=1" || r.Host != "api.example.com" || r.Header.Get("X-Test") != "1" || string(body) != "payload" {
+				t.Errorf("mirrored request: %s %s %s %v %s", r.Method, r.URL, r.Host, r.Header, body)
+			}
+			for _, name := range []string{"Connection", "X-Hop", "Keep-Alive"} {
+				if r.Header.Get(name) != "" {
+					t.Errorf("hop-by-hop header %s mirrored", name)
+				}
+			}
+			records := Records(ctx)
+			if len(records) != 1 || !records[0].Done || records[0].StatusCode != http.StatusAccepted {
+				t.Errorf("records = %+v", records)
+			}
+		})
+	}
+}
+
+func TestSendLimit(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+
+	m, err := NewMirror(Target{Address: srv.URL}, Config{Ratio: 1, Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := newContext()
+	start := time.Now()
+	sent := 0
+	for i := 0; i < 3; i++ {
+		if m.Send(ctx) {
+			sent++
+		}
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("slow mirror blocked the caller")
+	}
+	close(release)
+	m.Wait()
+	if sent != 1 {
+		t.Errorf("sent %d mirrors, want 1", sent)
+	}
+	dropped := 0
+	for _, r := range Records(ctx) {
+		if r.Err == ErrorDropped.Error() {
+			dropped++
+		}
+	}
+	if dropped != 2 {
+		t.Errorf("dropped %d mirrors, want 2", dropped)
+	}
+}
+
+func TestSendBodySize(t *testing.T) {
+	m, err := NewMirror(Target{Address: "127.0.0.1:1"}, Config{Ratio: 1, MaxBodySize: 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, length := range map[string]int{"chunked": -1, "too large": 2048} {
+		t.Run(name, func(t *testing.T) {
+			ctx := newContext()
+			ctx.length = length
+			if m.Send(ctx) {
+				t.Fatal("mirrored")
+			}
+			if records := Records(ctx); len(records) != 1 || records[0].Err != ErrorBodySize.Error() {
+				t.Errorf("records = %+v", records)
+			}
+		})
+	}
+}
+
+func TestSendRatio(t *testing.T) {
+	m, err := NewMirror(Target{Address: "127.0.0.1:1"}, Config{Ratio: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := newContext()
+	if m.Send(ctx) || len(Records(ctx)) != 0 {
+		t.Error("mirrored with ratio 0")
+	}
+	if _, err := NewMirror(Target{}, Config{}); err != ErrorNoTarget {
+		t.Errorf("NewMirror() error = %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package mirror
+
+import (
+	"sync"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+// Record 一次镜像请求的记录，与 IHttpContext.Proxies 分开保存，用于日志
+type Record struct {
+	Address    string
+	Method     string
+	Path       string
+	Time       time.Time
+	Done       bool
+	StatusCode int
+	Spend      time.Duration
+	Err        string
+}
+
+type recordsKey struct{}
+
+type records struct {
+	locker sync.Mutex
+	list   []Record
+}
+
+// recordsOf 请求上的镜像记录，不存在时创建
+func recordsOf(ctx eocontext.EoContext) *records {
+	if rs, ok := ctx.Value(recordsKey{}).(*records); ok {
+		return rs
+	}
+	rs := &records{}
+	ctx.WithValue(recordsKey{}, rs)
+	return rs
+}
+
+func (rs *records) add(r *Record) int {
+	rs.locker.Lock()
+	defer rs.locker.Unlock()
+	rs.list = append(rs.list, *r)
+	return len(rs.list) - 1
+}
+
+func (rs *records) done(i int, code int, err error) {
+	rs.locker.Lock()
+	defer rs.locker.Unlock()
+	r := &rs.list[i]
+	r.Done = true
+	r.StatusCode = code
+	r.Spend = time.Since(r.Time)
+	if err != nil {
+		r.Err = err.Error()
+	}
+}
+
+// Records 请求上镜像请求的记录，进行中的记录 Done 为 false
+func Records(ctx eocontext.EoContext) []Record {
+	rs, ok := ctx.Value(recordsKey{}).(*records)
+	if !ok {
+		return nil
+	}
+	rs.locker.Lock()
+	defer rs.locker.Unlock()
+	list := make([]Record, len(rs.list))
+	copy(list, rs.list)
+	return list
+}
@@ -0,0 +1,116 @@
+package policy
+
+import (
+	"sync"
+	"time"
+)
+
+// State 熔断器状态
+type State int
+
+const (
+	Closed State = iota
+	Open
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// Breaker 熔断器：连续失败达到阈值后熔断，熔断一段时间后半开，半开时放行的请求全部成功则恢复，任一失败重新熔断
+type Breaker struct {
+	conf *BreakerConfig
+	now  func() time.Time
+
+	locker    sync.Mutex
+	state     State
+	failures  int
+	openAt    time.Time
+	inflight  int
+	successes int
+}
+
+func NewBreaker(conf *BreakerConfig) *Breaker {
+	return &Breaker{conf: conf, now: time.Now}
+}
+
+func (b *Breaker) State() State {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	return b.current()
+}
+
+func (b *Breaker) current() State {
+	if b.state == Open && b.now().Sub(b.openAt) >= b.conf.Open.Duration() {
+		b.state = HalfOpen
+		b.inflight, b.successes = 0, 0
+	}
+	return b.state
+}
+
+// Allow 是否放行请求，放行后必须调用 Done 上报结果
+func (b *Breaker) Allow() error {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	switch b.current() {
+	case Open:
+		return ErrorCircuitOpen
+	case HalfOpen:
+		if b.inflight >= b.conf.HalfOpen {
+			return ErrorCircuitOpen
+		}
+		b.inflight++
+	}
+	return nil
+}
+
+// Done 上报放行请求的结果
+func (b *Breaker) Done(success bool) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	switch b.current() {
+	case Closed:
+		if success {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.conf.Failures {
+			b.open()
+		}
+	case HalfOpen:
+		if !success {
+			b.open()
+			return
+		}
+		b.successes++
+		if b.successes >= b.conf.HalfOpen {
+			b.state = Closed
+			b.failures = 0
+		}
+	}
+}
+
+// release 放行后没有发出请求时归还半开状态下的名额
+func (b *Breaker) release() {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	if b.state == HalfOpen && b.inflight > 0 {
+		b.inflight--
+	}
+}
+
+func (b *Breaker) open() {
+	b.state = Open
+	b.openAt = b.now()
+	b.failures = 0
+}
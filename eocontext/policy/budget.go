@@ -0,0 +1,65 @@
+package policy
+
+import (
+	"sync"
+	"time"
+)
+
+const budgetWindow = 10
+
+type budgetBucket struct {
+	second   int64
+	requests int
+	retries  int
+}
+
+// budget 按秒统计最近 budgetWindow 秒的请求数与重试数
+type budget struct {
+	conf *BudgetConfig
+	now  func() time.Time
+
+	locker  sync.Mutex
+	buckets [budgetWindow]budgetBucket
+}
+
+func newBudget(conf *BudgetConfig) *budget {
+	return &budget{conf: conf, now: time.Now}
+}
+
+func (b *budget) bucket() *budgetBucket {
+	sec := b.now().Unix()
+	bk := &b.buckets[sec%budgetWindow]
+	if bk.second != sec {
+		*bk = budgetBucket{second: sec}
+	}
+	return bk
+}
+
+func (b *budget) request() {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	b.bucket().requests++
+}
+
+// retry 预算足够时占用一次重试
+func (b *budget) retry() bool {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	current := b.bucket()
+	requests, retries := 0, 0
+	for _, bk := range b.buckets {
+		if bk.second > current.second-budgetWindow {
+			requests += bk.requests
+			retries += bk.retries
+		}
+	}
+	limit := int(float64(requests) * b.conf.Ratio)
+	if limit < b.conf.Min {
+		limit = b.conf.Min
+	}
+	if retries >= limit {
+		return false
+	}
+	current.retries++
+	return true
+}
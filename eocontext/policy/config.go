@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+const (
+	defaultBreakerFailures = 5
+	defaultBreakerOpen     = 30 * time.Second
+	defaultHalfOpen        = 1
+	defaultBudgetMin       = 10
+)
+
+var (
+	ErrorNoApp       = errors.New("no app")
+	ErrorNoBalance   = errors.New("no balance handler")
+	ErrorCircuitOpen = errors.New("circuit breaker is open")
+)
+
+// Config 转发策略，Retry、Breaker 为空时不重试、不熔断
+type Config struct {
+	Retry *RetryConfig `json:"retry,omitempty"`
+	// Breaker 每个节点的熔断器
+	Breaker *BreakerConfig `json:"breaker,omitempty"`
+	// AppBreaker 整个 app 的熔断器
+	AppBreaker *BreakerConfig `json:"app_breaker,omitempty"`
+}
+
+// RetryConfig 重试配置
+type RetryConfig struct {
+	// Attempts 最多尝试的次数，包括第一次
+	Attempts int `json:"attempts"`
+	// PerTryTimeout 每次尝试的超时，如 "3s"，为 0 时使用 app 的超时
+	PerTryTimeout eocontext.Duration `json:"per_try_timeout,omitempty"`
+	// StatusCodes 返回这些状态码时重试
+	StatusCodes []int `json:"status_codes,omitempty"`
+	// ConnectError 转发失败（连接失败、超时等）时重试
+	ConnectError bool `json:"connect_error,omitempty"`
+	// IdempotentOnly 只重试幂等的请求方法
+	IdempotentOnly bool          `json:"idempotent_only,omitempty"`
+	Budget         *BudgetConfig `json:"budget,omitempty"`
+}
+
+// BudgetConfig 重试预算，最近 10 秒内的重试数不超过 max(Min, 请求数*Ratio)
+type BudgetConfig struct {
+	Ratio float64 `json:"ratio"`
+	Min   int     `json:"min,omitempty"`
+}
+
+// BreakerConfig 熔断器配置
+type BreakerConfig struct {
+	// Failures 连续失败多少次后熔断
+	Failures int `json:"failures,omitempty"`
+	// Open 熔断持续多久后进入半开状态，如 "30s"
+	Open eocontext.Duration `json:"open,omitempty"`
+	// HalfOpen 半开状态下放行的请求数，全部成功后恢复
+	HalfOpen int `json:"half_open,omitempty"`
+}
+
+func (c *Config) check() {
+	if r := c.Retry; r != nil {
+		if r.Attempts < 1 {
+			r.Attempts = 1
+		}
+		if b := r.Budget; b != nil && b.Min <= 0 {
+			b.Min = defaultBudgetMin
+		}
+	}
+	for _, b := range []*BreakerConfig{c.Breaker, c.AppBreaker} {
+		if b == nil {
+			continue
+		}
+		if b.Failures <= 0 {
+			b.Failures = defaultBreakerFailures
+		}
+		if b.Open <= 0 {
+			b.Open = eocontext.Duration(defaultBreakerOpen)
+		}
+		if b.HalfOpen <= 0 {
+			b.HalfOpen = defaultHalfOpen
+		}
+	}
+}
+
+func (r *RetryConfig) retryStatus(code int) bool {
+	for _, c := range r.StatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
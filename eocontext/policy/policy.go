@@ -0,0 +1,160 @@
+package policy
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/eolinker/eosc/eocontext"
+	http_context "github.com/eolinker/eosc/eocontext/http-context"
+)
+
+// Policy 绑定到 app 的转发策略，负责选择节点、重试与熔断
+type Policy struct {
+	app    eocontext.EoApp
+	conf   *Config
+	budget *budget
+
+	appBreaker *Breaker
+	breakers   sync.Map
+}
+
+func NewPolicy(app eocontext.EoApp, conf *Config) (*Policy, error) {
+	if app == nil {
+		return nil, ErrorNoApp
+	}
+	if conf == nil {
+		conf = &Config{}
+	}
+	conf.check()
+	p := &Policy{app: app, conf: conf}
+	if conf.Retry != nil && conf.Retry.Budget != nil {
+		p.budget = newBudget(conf.Retry.Budget)
+	}
+	if conf.AppBreaker != nil {
+		p.appBreaker = NewBreaker(conf.AppBreaker)
+	}
+	return p, nil
+}
+
+// NodeState 节点熔断器的状态，未配置节点熔断时总是 Closed
+func (p *Policy) NodeState(node eocontext.INode) State {
+	if b := p.breaker(node); b != nil {
+		return b.State()
+	}
+	return Closed
+}
+
+func (p *Policy) breaker(node eocontext.INode) *Breaker {
+	if p.conf.Breaker == nil {
+		return nil
+	}
+	v, has := p.breakers.Load(node.ID())
+	if !has {
+		v, _ = p.breakers.LoadOrStore(node.ID(), NewBreaker(p.conf.Breaker))
+	}
+	return v.(*Breaker)
+}
+
+// Send 通过 ctx 的负载均衡选择节点并转发，按配置重试；每次尝试都调用一次 SendTo，因此在 Proxies 中各有一条记录
+func (p *Policy) Send(ctx http_context.IHttpContext) error {
+	balance := ctx.GetBalance()
+	if balance == nil {
+		return ErrorNoBalance
+	}
+	if p.appBreaker != nil {
+		if err := p.appBreaker.Allow(); err != nil {
+			return err
+		}
+	}
+	attempts, timeout := 1, p.app.TimeOut()
+	retry := p.conf.Retry
+	if retry != nil {
+		if !retry.IdempotentOnly || idempotent(ctx.Proxy().Method()) {
+			attempts = retry.Attempts
+		}
+		if retry.PerTryTimeout > 0 {
+			timeout = retry.PerTryTimeout.Duration()
+		}
+	}
+
+	var err error
+	attempted := false
+	for i := 0; i < attempts; i++ {
+		node, breaker, e := p.selectNode(ctx, balance)
+		if e != nil {
+			// 重试时没有可用的节点，保留上一次尝试的结果
+			if !attempted {
+				err = e
+			}
+			break
+		}
+		// 只有真正发出的请求才计入预算
+		if p.budget != nil {
+			if i == 0 {
+				p.budget.request()
+			} else if !p.budget.retry() {
+				if breaker != nil {
+					breaker.release()
+				}
+				break
+			}
+		}
+		attempted = true
+		err = ctx.SendTo(fmt.Sprintf("%s://%s", p.app.Scheme(), node.Addr()), timeout)
+		code := 0
+		if err == nil {
+			code = ctx.Response().StatusCode()
+		}
+		success := err == nil && code < 500
+		if breaker != nil {
+			breaker.Done(success)
+		}
+		if !p.shouldRetry(err, code) {
+			break
+		}
+	}
+	if p.appBreaker != nil {
+		// 节点全部熔断时没有发出请求，不算作 app 的失败
+		if attempted {
+			p.appBreaker.Done(err == nil && ctx.Response().StatusCode() < 500)
+		} else {
+			p.appBreaker.release()
+		}
+	}
+	return err
+}
+
+// selectNode 跳过已熔断的节点，最多尝试节点数次
+func (p *Policy) selectNode(ctx eocontext.EoContext, balance eocontext.BalanceHandler) (eocontext.INode, *Breaker, error) {
+	tries := len(p.app.Nodes())
+	if tries < 1 {
+		tries = 1
+	}
+	var err error
+	for i := 0; i < tries; i++ {
+		var node eocontext.INode
+		node, err = balance.Select(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		breaker := p.breaker(node)
+		if breaker == nil {
+			return node, nil, nil
+		}
+		if err = breaker.Allow(); err == nil {
+			return node, breaker, nil
+		}
+	}
+	return nil, nil, err
+}
+
+func (p *Policy) shouldRetry(err error, code int) bool {
+	r := p.conf.Retry
+	if r == nil {
+		return false
+	}
+	if err != nil {
+		return r.ConnectError
+	}
+	return r.retryStatus(code)
+}
@@ -0,0 +1,224 @@
+package policy
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+	"github.com/eolinker/eosc/eocontext/balance"
+	"github.com/eolinker/eosc/eocontext/eotest"
+	http_context "github.com/eolinker/eosc/eocontext/http-context"
+)
+
+var errorConnect = errors.New("connect refused")
+
+type testRequest struct {
+	http_context.IRequest
+	method string
+}
+
+func (r *testRequest) Method() string { return r.method }
+
+type testResponse struct {
+	http_context.IResponse
+	code int
+}
+
+func (r *testResponse) StatusCode() int { return r.code }
+
+// testContext 按节点地址返回预设的转发结果
+type testContext struct {
+	http_context.IHttpContext
+	app      eocontext.EoApp
+	balance  eocontext.BalanceHandler
+	method   string
+	results  map[string]interface{}
+	response testResponse
+	sent     []string
+}
+
+func (c *testContext) GetApp() eocontext.EoApp                   { return c.app }
+func (c *testContext) GetBalance() eocontext.BalanceHandler      { return c.balance }
+func (c *testContext) Proxy() http_context.IRequest              { return &testRequest{method: c.method} }
+func (c *testContext) Response() http_context.IResponse          { return &c.response }
+func (c *testContext) SetFinish(handler eocontext.FinishHandler) {}
+func (c *testContext) GetFinish() eocontext.FinishHandler        { return nil }
+func (c *testContext) Value(key interface{}) interface{}         { return nil }
+func (c *testContext) SendTo(address string, timeout time.Duration) error {
+	c.sent = append(c.sent, strings.TrimPrefix(address, "http://"))
+	switch r := c.results[address].(type) {
+	case error:
+		return r
+	case int:
+		c.response.code = r
+	default:
+		c.response.code = http.StatusOK
+	}
+	return nil
+}
+
+func newContext(app eocontext.EoApp, method string, results map[string]interface{}) *testContext {
+	return &testContext{app: app, balance: balance.NewRoundRobin(), method: method, results: results}
+}
+
+func TestSend(t *testing.T) {
+	app := eotest.NewApp("", eotest.NewNode("a:80", nil), eotest.NewNode("b:80", nil), eotest.NewNode("c:80", nil))
+	retry := &RetryConfig{Attempts: 3, StatusCodes: []int{http.StatusBadGateway}, ConnectError: true, IdempotentOnly: true}
+	tests := []struct {
+		name    string
+		conf    *Config
+		method  string
+		results map[string]interface{}
+		want    string
+		wantErr error
+	}{
+		{name: "no retry", conf: &Config{}, method: http.MethodGet, results: map[string]interface{}{"http://a:80": errorConnect}, want: "a:80", wantErr: errorConnect},
+		{name: "connect error", conf: &Config{Retry: retry}, method: http.MethodGet, results: map[string]interface{}{"http://a:80": errorConnect}, want: "a:80,b:80"},
+		{name: "status", conf: &Config{Retry: retry}, method: http.MethodGet, results: map[string]interface{}{"http://a:80": 502, "http://b:80": 502}, want: "a:80,b:80,c:80"},
+		{name: "attempts", conf: &Config{Retry: retry}, method: http.MethodGet, results: map[string]interface{}{"http://a:80": 502, "http://b:80": 502, "http://c:80": 502}, want: "a:80,b:80,c:80"},
+		{name: "status not retried", conf: &Config{Retry: retry}, method: http.MethodGet, results: map[string]interface{}{"http://a:80": 503}, want: "a:80"},
+		{name: "not idempotent", conf: &Config{Retry: retry}, method: http.MethodPost, results: map[string]interface{}{"http://a:80": 502}, want: "a:80"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPolicy(app, tt.conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := newContext(app, tt.method, tt.results)
+			if err := p.Send(ctx); err != tt.wantErr {
+				t.Errorf("Send() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := strings.Join(ctx.sent, ","); got != tt.want {
+				t.Errorf("Send() tried %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendSkipOpenNode(t *testing.T) {
+	app := eotest.NewApp("", eotest.NewNode("a:80", nil), eotest.NewNode("b:80", nil))
+	p, _ := NewPolicy(app, &Config{Breaker: &BreakerConfig{Failures: 1, Open: eocontext.Duration(time.Minute)}})
+	results := map[string]interface{}{"http://a:80": 500}
+	ctx := newContext(app, http.MethodGet, results)
+	p.Send(ctx)
+	p.Send(ctx)
+	p.Send(ctx)
+	if got := strings.Join(ctx.sent, ","); got != "a:80,b:80,b:80" {
+		t.Errorf("tried %s", got)
+	}
+	if p.NodeState(app.Nodes()[0]) != Open {
+		t.Errorf("node a state = %s", p.NodeState(app.Nodes()[0]))
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	b := NewBreaker(&BreakerConfig{Failures: 2, Open: eocontext.Duration(time.Second), HalfOpen: 2})
+	b.now = func() time.Time { return now }
+	steps := []struct {
+		advance time.Duration
+		success bool
+		allow   error
+		want    State
+	}{
+		{success: false, want: Closed},
+		{success: false, want: Open},
+		{allow: ErrorCircuitOpen, want: Open},
+		{advance: time.Second, success: true, want: HalfOpen},
+		{success: false, want: Open},
+		{advance: time.Second, success: true, want: HalfOpen},
+		{success: true, want: Closed},
+	}
+	for i, s := range steps {
+		now = now.Add(s.advance)
+		err := b.Allow()
+		if err != s.allow {
+			t.Fatalf("step %d: Allow() = %v, want %v", i, err, s.allow)
+		}
+		if err == nil {
+			b.Done(s.success)
+		}
+		if got := b.State(); got != s.want {
+			t.Fatalf("step %d: state = %s, want %s", i, got, s.want)
+		}
+	}
+}
+
+func TestBudget(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := newBudget(&BudgetConfig{Ratio: 0.2, Min: 1})
+	b.now = func() time.Time { return now }
+	for i := 0; i < 10; i++ {
+		b.request()
+	}
+	allowed := 0
+	for i := 0; i < 5; i++ {
+		if b.retry() {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Errorf("allowed %d retries, want 2", allowed)
+	}
+	now = now.Add(budgetWindow * time.Second)
+	if !b.retry() {
+		t.Error("budget not refilled after window")
+	}
+	if b.retry() {
+		t.Error("retry beyond min")
+	}
+}
+
+func TestSendAllOpen(t *testing.T) {
+	app := eotest.NewApp("", eotest.NewNode("a:80", nil), eotest.NewNode("b:80", nil))
+	retry := &RetryConfig{Attempts: 3, StatusCodes: []int{http.StatusBadGateway}, Budget: &BudgetConfig{Ratio: 1, Min: 10}}
+	tests := []struct {
+		name     string
+		open     []int
+		wantErr  error
+		wantCode int
+		sent     string
+		requests int
+	}{
+		// 重试时其它节点都已熔断，返回上一次的结果
+		{name: "retry without node", open: []int{1}, wantCode: http.StatusBadGateway, sent: "a:80", requests: 1},
+		// 所有节点都已熔断，没有发出请求
+		{name: "no node", open: []int{0, 1}, wantErr: ErrorCircuitOpen, sent: "", requests: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := NewPolicy(app, &Config{
+				Retry:      retry,
+				Breaker:    &BreakerConfig{Failures: 1, Open: eocontext.Duration(time.Minute)},
+				AppBreaker: &BreakerConfig{Failures: 1, Open: eocontext.Duration(time.Minute)},
+			})
+			for _, i := range tt.open {
+				p.breaker(app.Nodes()[i]).Done(false)
+			}
+			ctx := newContext(app, http.MethodGet, map[string]interface{}{"http://a:80": 502})
+			if err := p.Send(ctx); err != tt.wantErr {
+				t.Errorf("Send() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && ctx.response.code != tt.wantCode {
+				t.Errorf("status = %d, want %d", ctx.response.code, tt.wantCode)
+			}
+			if got := strings.Join(ctx.sent, ","); got != tt.sent {
+				t.Errorf("tried %s, want %s", got, tt.sent)
+			}
+			requests := 0
+			for _, bk := range p.budget.buckets {
+				requests += bk.requests
+			}
+			if requests != tt.requests {
+				t.Errorf("budget requests = %d, want %d", requests, tt.requests)
+			}
+			if tt.wantErr != nil && p.appBreaker.State() != Closed {
+				t.Errorf("app breaker = %s after rejected request", p.appBreaker.State())
+			}
+		})
+	}
+}
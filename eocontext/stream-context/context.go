@@ -0,0 +1,36 @@
+package stream_context
+
+import (
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+// IStreamContext 四层（TCP/TLS）连接的上下文，一个连接对应一个上下文
+type IStreamContext interface {
+	eocontext.EoContext
+	// Conn 客户端连接，TLS 连接为握手后的 *tls.Conn
+	Conn() net.Conn
+	RemoteAddr() net.Addr
+	// TLS 客户端的 TLS 握手信息，非 TLS 连接返回 nil
+	TLS() *tls.ConnectionState
+	// ServerName 客户端通过 SNI 请求的域名
+	ServerName() string
+	// NegotiatedProtocol ALPN 协商的协议
+	NegotiatedProtocol() string
+	// AddDataFilter 添加转发数据的处理，按添加顺序执行
+	AddDataFilter(filter DataFilter)
+	DataFilters() []DataFilter
+	// DialUpstream 通过 BalanceHandler 选择节点并建立上游连接，timeout 为 0 时使用 app 的超时
+	DialUpstream(timeout time.Duration) (net.Conn, eocontext.INode, error)
+	// Upstream 已建立的上游连接，未建立时为 nil
+	Upstream() net.Conn
+}
+
+func Assert(ctx eocontext.EoContext) (IStreamContext, error) {
+	var streamContext IStreamContext
+	err := ctx.Assert(&streamContext)
+	return streamContext, err
+}
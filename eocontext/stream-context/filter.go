@@ -0,0 +1,33 @@
+package stream_context
+
+import (
+	"github.com/eolinker/eosc/eocontext"
+	"github.com/eolinker/eosc/utils/config"
+)
+
+var (
+	FilterSkillName = config.TypeNameOf((*StreamFilter)(nil))
+)
+
+type StreamFilter interface {
+	DoStreamFilter(ctx IStreamContext, next eocontext.IChain) (err error)
+}
+
+func DoStreamFilter(streamFilter StreamFilter, ctx eocontext.EoContext, next eocontext.IChain) (err error) {
+	streamContext, err := Assert(ctx)
+	if err == nil {
+		return streamFilter.DoStreamFilter(streamContext, next)
+	}
+	if next != nil {
+		return next.DoChain(ctx)
+	}
+	return err
+}
+
+// DataFilter 处理转发中的字节流，返回的数据写往对端，返回空数据时本次不写；返回错误时断开连接
+type DataFilter interface {
+	// OnRequest 客户端发往上游的数据
+	OnRequest(p []byte) ([]byte, error)
+	// OnResponse 上游返回给客户端的数据
+	OnResponse(p []byte) ([]byte, error)
+}
@@ -0,0 +1,35 @@
+package stream_context
+
+import (
+	"github.com/eolinker/eosc/eocontext"
+	"github.com/eolinker/eosc/log"
+)
+
+// Handler 处理一个四层连接，返回后连接关闭
+type Handler interface {
+	ServeStream(ctx IStreamContext)
+}
+
+type HandlerFunc func(ctx IStreamContext)
+
+func (f HandlerFunc) ServeStream(ctx IStreamContext) {
+	f(ctx)
+}
+
+// ChainHandler 依次执行 filter 链与 CompleteHandler，与 http 请求使用相同的 filter 模型
+func ChainHandler(chain eocontext.IChain) Handler {
+	return HandlerFunc(func(ctx IStreamContext) {
+		err := chain.DoChain(ctx)
+		if err == nil {
+			if complete := ctx.GetComplete(); complete != nil {
+				err = complete.Complete(ctx)
+			}
+		}
+		if err != nil {
+			log.Debug("stream ", ctx.RequestId(), " from ", ctx.RemoteAddr(), ": ", err)
+		}
+		if finish := ctx.GetFinish(); finish != nil {
+			finish.Finish(ctx)
+		}
+	})
+}
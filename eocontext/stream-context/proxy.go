@@ -0,0 +1,90 @@
+package stream_context
+
+import (
+	"io"
+	"net"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+const pipeBufferSize = 32 * 1024
+
+// ProxyComplete 默认的 CompleteHandler：建立上游连接（未建立时），双向转发数据直到两个方向都结束
+var ProxyComplete eocontext.CompleteHandler = proxyComplete{}
+
+type proxyComplete struct{}
+
+func (proxyComplete) Complete(ctx eocontext.EoContext) error {
+	streamContext, err := Assert(ctx)
+	if err != nil {
+		return err
+	}
+	upstream := streamContext.Upstream()
+	if upstream == nil {
+		upstream, _, err = streamContext.DialUpstream(0)
+		if err != nil {
+			return err
+		}
+	}
+	return Pipe(streamContext.Conn(), upstream, streamContext.DataFilters())
+}
+
+type closeWriter interface {
+	CloseWrite() error
+}
+
+// Pipe 在 client 与 upstream 之间双向转发，数据经过 filters 处理；一个方向结束时关闭对端的写，返回第一个错误
+func Pipe(client, upstream net.Conn, filters []DataFilter) error {
+	errs := make(chan error, 2)
+	go func() {
+		errs <- copyStream(upstream, client, filters, DataFilter.OnRequest)
+	}()
+	go func() {
+		errs <- copyStream(client, upstream, filters, DataFilter.OnResponse)
+	}()
+	var err error
+	for i := 0; i < 2; i++ {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+			// 出错时不再等待另一个方向
+			client.Close()
+			upstream.Close()
+		}
+	}
+	return err
+}
+
+func copyStream(dst, src net.Conn, filters []DataFilter, handle func(DataFilter, []byte) ([]byte, error)) error {
+	buf := make([]byte, pipeBufferSize)
+	defer func() {
+		if cw, ok := dst.(closeWriter); ok {
+			cw.CloseWrite()
+		} else {
+			dst.Close()
+		}
+	}()
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			data := buf[:n]
+			for _, f := range filters {
+				var e error
+				data, e = handle(f, data)
+				if e != nil {
+					return e
+				}
+			}
+			if len(data) > 0 {
+				if _, e := dst.Write(data); e != nil {
+					return e
+				}
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package stream_context
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+	"github.com/eolinker/eosc/utils/config"
+	"github.com/google/uuid"
+)
+
+const (
+	SchemeTCP = "tcp"
+	SchemeTLS = "tls"
+)
+
+var (
+	ErrorNoApp     = errors.New("no app")
+	ErrorNoBalance = errors.New("no balance handler")
+)
+
+var _ IStreamContext = (*Context)(nil)
+
+// handshakeTimeout TLS 握手的超时时间，避免客户端不发送数据时一直占用连接
+var handshakeTimeout = time.Second * 10
+
+// Context IStreamContext 的实现
+type Context struct {
+	conn       net.Conn
+	tlsState   *tls.ConnectionState
+	requestId  string
+	acceptTime time.Time
+	ctx        context.Context
+	cancel     context.CancelFunc
+	labels     map[string]string
+
+	complete     eocontext.CompleteHandler
+	finish       eocontext.FinishHandler
+	app          eocontext.EoApp
+	balance      eocontext.BalanceHandler
+	upstreamHost eocontext.UpstreamHostHandler
+
+	dataFilters []DataFilter
+	upstream    net.Conn
+}
+
+// NewContext 为客户端连接创建上下文，TLS 连接会先在 handshakeTimeout 内完成握手；默认的 CompleteHandler 把连接转发到上游
+func NewContext(conn net.Conn) (*Context, error) {
+	c := &Context{
+		conn:       conn,
+		requestId:  uuid.NewString(),
+		acceptTime: time.Now(),
+		labels:     make(map[string]string),
+		complete:   ProxyComplete,
+	}
+	if tc, ok := conn.(*tls.Conn); ok {
+		tc.SetDeadline(time.Now().Add(handshakeTimeout))
+		if err := tc.Handshake(); err != nil {
+			return nil, err
+		}
+		tc.SetDeadline(time.Time{})
+		state := tc.ConnectionState()
+		c.tlsState = &state
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	return c, nil
+}
+
+// Close 关闭客户端与上游连接
+func (c *Context) Close() error {
+	c.cancel()
+	if c.upstream != nil {
+		c.upstream.Close()
+	}
+	return c.conn.Close()
+}
+
+func (c *Context) Conn() net.Conn {
+	return c.conn
+}
+
+func (c *Context) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+func (c *Context) TLS() *tls.ConnectionState {
+	return c.tlsState
+}
+
+func (c *Context) ServerName() string {
+	if c.tlsState == nil {
+		return ""
+	}
+	return c.tlsState.ServerName
+}
+
+func (c *Context) NegotiatedProtocol() string {
+	if c.tlsState == nil {
+		return ""
+	}
+	return c.tlsState.NegotiatedProtocol
+}
+
+func (c *Context) AddDataFilter(filter DataFilter) {
+	c.dataFilters = append(c.dataFilters, filter)
+}
+
+func (c *Context) DataFilters() []DataFilter {
+	return c.dataFilters
+}
+
+func (c *Context) Upstream() net.Conn {
+	return c.upstream
+}
+
+func (c *Context) DialUpstream(timeout time.Duration) (net.Conn, eocontext.INode, error) {
+	if c.app == nil {
+		return nil, nil, ErrorNoApp
+	}
+	if c.balance == nil {
+		return nil, nil, ErrorNoBalance
+	}
+	node, err := c.balance.Select(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	if timeout <= 0 {
+		timeout = c.app.TimeOut()
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	if c.app.Scheme() == SchemeTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", node.Addr(), &tls.Config{ServerName: c.upstreamServerName(node)})
+	} else {
+		conn, err = dialer.DialContext(c.ctx, "tcp", node.Addr())
+	}
+	if err != nil {
+		return nil, node, fmt.Errorf("dial %s:%w", node.Addr(), err)
+	}
+	if c.upstream != nil {
+		c.upstream.Close()
+	}
+	c.upstream = conn
+	return conn, node, nil
+}
+
+// upstreamServerName 与 http 转发的 Host 规则一致：默认透传客户端的 SNI
+func (c *Context) upstreamServerName(node eocontext.INode) string {
+	if c.upstreamHost != nil {
+		switch mod, host := c.upstreamHost.PassHost(); mod {
+		case eocontext.NodeHost:
+			return node.IP()
+		case eocontext.ReWriteHost:
+			return host
+		}
+	}
+	if name := c.ServerName(); name != "" {
+		return name
+	}
+	return node.IP()
+}
+
+func (c *Context) RequestId() string {
+	return c.requestId
+}
+
+func (c *Context) AcceptTime() time.Time {
+	return c.acceptTime
+}
+
+func (c *Context) Context() context.Context {
+	return c.ctx
+}
+
+func (c *Context) Value(key interface{}) interface{} {
+	return c.ctx.Value(key)
+}
+
+func (c *Context) WithValue(key, val interface{}) {
+	c.ctx = context.WithValue(c.ctx, key, val)
+}
+
+func (c *Context) Scheme() string {
+	if c.tlsState != nil {
+		return SchemeTLS
+	}
+	return SchemeTCP
+}
+
+func (c *Context) Assert(i interface{}) error {
+	if v, ok := i.(*IStreamContext); ok {
+		*v = c
+		return nil
+	}
+	return fmt.Errorf("not suport:%s", config.TypeNameOf(i))
+}
+
+func (c *Context) SetLabel(name, value string) {
+	c.labels[name] = value
+}
+
+func (c *Context) GetLabel(name string) string {
+	return c.labels[name]
+}
+
+func (c *Context) Labels() map[string]string {
+	return c.labels
+}
+
+func (c *Context) GetComplete() eocontext.CompleteHandler {
+	return c.complete
+}
+
+func (c *Context) SetCompleteHandler(handler eocontext.CompleteHandler) {
+	c.complete = handler
+}
+
+func (c *Context) GetFinish() eocontext.FinishHandler {
+	return c.finish
+}
+
+func (c *Context) SetFinish(handler eocontext.FinishHandler) {
+	c.finish = handler
+}
+
+func (c *Context) GetApp() eocontext.EoApp {
+	return c.app
+}
+
+func (c *Context) SetApp(app eocontext.EoApp) {
+	c.app = app
+}
+
+func (c *Context) GetBalance() eocontext.BalanceHandler {
+	return c.balance
+}
+
+func (c *Context) SetBalance(handler eocontext.BalanceHandler) {
+	c.balance = handler
+}
+
+func (c *Context) GetUpstreamHostHandler() eocontext.UpstreamHostHandler {
+	return c.upstreamHost
+}
+
+func (c *Context) SetUpstreamHostHandler(handler eocontext.UpstreamHostHandler) {
+	c.upstreamHost = handler
+}
+
+func (c *Context) LocalIP() net.IP {
+	if addr, ok := c.conn.LocalAddr().(*net.TCPAddr); ok {
+		return addr.IP
+	}
+	return nil
+}
+
+func (c *Context) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *Context) LocalPort() int {
+	if addr, ok := c.conn.LocalAddr().(*net.TCPAddr); ok {
+		return addr.Port
+	}
+	return 0
+}
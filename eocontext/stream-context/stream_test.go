@@ -0,0 +1,112 @@
+package stream_context
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+	"github.com/eolinker/eosc/eocontext/eotest"
+)
+
+type testHost struct {
+	mod  eocontext.PassHostMod
+	host string
+}
+
+func (h testHost) PassHost() (eocontext.PassHostMod, string) { return h.mod, h.host }
+
+func TestHandshakeTimeout(t *testing.T) {
+	old := handshakeTimeout
+	handshakeTimeout = time.Millisecond * 100
+	defer func() { handshakeTimeout = old }()
+
+	server, client := net.Pipe()
+	defer client.Close()
+	done := make(chan error, 1)
+	go func() {
+		_, err := NewContext(tls.Server(server, &tls.Config{}))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("NewContext() should fail when client never handshakes")
+		}
+	case <-time.After(time.Second * 2):
+		t.Fatal("NewContext() blocked on handshake")
+	}
+}
+
+func TestNewContext(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	ctx, err := NewContext(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Close()
+	if ctx.Scheme() != SchemeTCP || ctx.TLS() != nil || ctx.ServerName() != "" {
+		t.Errorf("plain conn: scheme=%s tls=%v", ctx.Scheme(), ctx.TLS())
+	}
+	var sc IStreamContext
+	if err := ctx.Assert(&sc); err != nil || sc != ctx {
+		t.Errorf("Assert() = %v", err)
+	}
+	if ctx.GetComplete() != ProxyComplete {
+		t.Error("default complete handler should be ProxyComplete")
+	}
+}
+
+func TestDialUpstream(t *testing.T) {
+	tests := []struct {
+		name    string
+		app     eocontext.EoApp
+		balance eocontext.BalanceHandler
+		want    error
+	}{
+		{name: "no app", want: ErrorNoApp},
+		{name: "no balance", app: eotest.NewApp(SchemeTCP), want: ErrorNoBalance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := net.Pipe()
+			defer client.Close()
+			ctx, _ := NewContext(server)
+			defer ctx.Close()
+			ctx.SetApp(tt.app)
+			ctx.SetBalance(tt.balance)
+			if _, _, err := ctx.DialUpstream(0); err != tt.want {
+				t.Errorf("DialUpstream() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpstreamServerName(t *testing.T) {
+	node := eotest.NewNode("10.0.0.1:80", nil)
+	tests := []struct {
+		name string
+		sni  string
+		host eocontext.UpstreamHostHandler
+		want string
+	}{
+		{name: "no sni", want: "10.0.0.1"},
+		{name: "pass sni", sni: "example.com", want: "example.com"},
+		{name: "pass host", sni: "example.com", host: testHost{mod: eocontext.PassHost}, want: "example.com"},
+		{name: "node host", sni: "example.com", host: testHost{mod: eocontext.NodeHost}, want: "10.0.0.1"},
+		{name: "rewrite host", sni: "example.com", host: testHost{mod: eocontext.ReWriteHost, host: "rewrite.com"}, want: "rewrite.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &Context{upstreamHost: tt.host}
+			if tt.sni != "" {
+				ctx.tlsState = &tls.ConnectionState{ServerName: tt.sni}
+			}
+			if got := ctx.upstreamServerName(node); got != tt.want {
+				t.Errorf("upstreamServerName() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
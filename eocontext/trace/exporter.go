@@ -0,0 +1,114 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// flushInterval FileExporter 定时写入缓冲的间隔
+var flushInterval = time.Second
+
+var (
+	_ Exporter = (*FileExporter)(nil)
+	_ Exporter = (*MemoryExporter)(nil)
+)
+
+// FileExporter 以 JSON lines 格式把 span 追加写入文件，缓冲的 span 每隔 flushInterval 写入一次
+type FileExporter struct {
+	locker sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewFileExporter(path string) (*FileExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	e := &FileExporter{file: f, writer: bufio.NewWriter(f), done: make(chan struct{})}
+	go e.flushLoop(flushInterval)
+	return e, nil
+}
+
+func (e *FileExporter) flushLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.Flush()
+		case <-e.done:
+			return
+		}
+	}
+}
+
+func (e *FileExporter) Export(span *SpanData) error {
+	data, err := json.Marshal(span)
+	if err != nil {
+		return err
+	}
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	e.writer.Write(data)
+	return e.writer.WriteByte('\n')
+}
+
+// Flush 把缓冲的 span 写入文件
+func (e *FileExporter) Flush() error {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	return e.writer.Flush()
+}
+
+func (e *FileExporter) Close() error {
+	e.once.Do(func() { close(e.done) })
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	if err := e.writer.Flush(); err != nil {
+		e.file.Close()
+		return err
+	}
+	return e.file.Close()
+}
+
+// MemoryExporter 把 span 保存在内存中，用于测试
+type MemoryExporter struct {
+	locker sync.Mutex
+	spans  []*SpanData
+}
+
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
+
+func (e *MemoryExporter) Export(span *SpanData) error {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	e.spans = append(e.spans, span)
+	return nil
+}
+
+// Spans 按结束顺序返回已导出的 span
+func (e *MemoryExporter) Spans() []*SpanData {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	spans := make([]*SpanData, len(e.spans))
+	copy(spans, e.spans)
+	return spans
+}
+
+func (e *MemoryExporter) Reset() {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	e.spans = nil
+}
+
+func (e *MemoryExporter) Close() error {
+	return nil
+}
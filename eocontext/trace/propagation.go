@@ -0,0 +1,110 @@
+package trace
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+	http_context "github.com/eolinker/eosc/eocontext/http-context"
+)
+
+// TraceParentHeader W3C trace context 的 header
+const TraceParentHeader = "traceparent"
+
+var (
+	ErrorInvalidTraceParent = errors.New("invalid traceparent")
+)
+
+// FormatTraceParent 生成 traceparent，格式为 00-{trace id}-{span id}-01
+func FormatTraceParent(traceId TraceID, spanId SpanID) string {
+	return "00-" + traceId.String() + "-" + spanId.String() + "-01"
+}
+
+// ParseTraceParent 解析 traceparent，只支持 00 版本
+func ParseTraceParent(value string) (TraceID, SpanID, error) {
+	var traceId TraceID
+	var spanId SpanID
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return traceId, spanId, ErrorInvalidTraceParent
+	}
+	if _, err := hex.Decode(traceId[:], []byte(parts[1])); err != nil {
+		return TraceID{}, spanId, ErrorInvalidTraceParent
+	}
+	if _, err := hex.Decode(spanId[:], []byte(parts[2])); err != nil {
+		return TraceID{}, SpanID{}, ErrorInvalidTraceParent
+	}
+	if !traceId.IsValid() || !spanId.IsValid() {
+		return TraceID{}, SpanID{}, ErrorInvalidTraceParent
+	}
+	return traceId, spanId, nil
+}
+
+// httpContext Tracer.Begin 返回的 http 上下文，SendTo 时自动记录 send span 并通过 traceparent 把 trace 传给上游
+type httpContext struct {
+	http_context.IHttpContext
+}
+
+func (c *httpContext) SendTo(address string, timeout time.Duration) error {
+	return sendTo(c.IHttpContext, address, timeout)
+}
+
+// Assert 断言为 IHttpContext 时返回自身，避免绕过追踪
+func (c *httpContext) Assert(i interface{}) error {
+	if v, ok := i.(*http_context.IHttpContext); ok {
+		*v = c
+		return nil
+	}
+	return c.IHttpContext.Assert(i)
+}
+
+type websocketContext struct {
+	http_context.IWebsocketContext
+}
+
+func (c *websocketContext) SendTo(address string, timeout time.Duration) error {
+	return sendTo(c.IWebsocketContext, address, timeout)
+}
+
+func (c *websocketContext) Assert(i interface{}) error {
+	switch v := i.(type) {
+	case *http_context.IHttpContext:
+		*v = c
+		return nil
+	case *http_context.IWebsocketContext:
+		*v = c
+		return nil
+	}
+	return c.IWebsocketContext.Assert(i)
+}
+
+// withSendTrace 包装 http 上下文，其他上下文原样返回
+func withSendTrace(ctx eocontext.EoContext) eocontext.EoContext {
+	switch c := ctx.(type) {
+	case http_context.IWebsocketContext:
+		return &websocketContext{IWebsocketContext: c}
+	case http_context.IHttpContext:
+		return &httpContext{IHttpContext: c}
+	}
+	return ctx
+}
+
+func sendTo(ctx http_context.IHttpContext, address string, timeout time.Duration) error {
+	span := Start(ctx, "send")
+	if span == nil {
+		return ctx.SendTo(address, timeout)
+	}
+	defer span.End()
+	span.SetAttribute("address", address)
+	ctx.Proxy().Header().SetHeader(TraceParentHeader, FormatTraceParent(span.TraceID(), span.SpanID()))
+	err := ctx.SendTo(address, timeout)
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	span.SetAttribute("status", strconv.Itoa(ctx.Response().StatusCode()))
+	return nil
+}
@@ -0,0 +1,177 @@
+package trace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+)
+
+type TraceID [16]byte
+type SpanID [8]byte
+
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// spanKey spanSlot 在 EoContext 中的 key
+type spanKey struct{}
+
+// spanSlot 保存 ctx 的当前 span；每个请求只在 Begin 时写入一次 ctx，之后切换 span 只修改 slot，不会让 ctx 的 value 链增长
+type spanSlot struct {
+	locker  sync.Mutex
+	current *Span
+}
+
+func (s *spanSlot) get() *Span {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	return s.current
+}
+
+func (s *spanSlot) set(span *Span) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.current = span
+}
+
+// restore 当前 span 仍是 span 时恢复为其父 span
+func (s *spanSlot) restore(span *Span) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	if s.current == span {
+		s.current = span.parent
+	}
+}
+
+// SpanData 结束后交给 Exporter 的 span 数据
+type SpanData struct {
+	TraceID    string            `json:"trace_id"`
+	SpanID     string            `json:"span_id"`
+	ParentID   string            `json:"parent_id,omitempty"`
+	Name       string            `json:"name"`
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"`
+	Duration   time.Duration     `json:"duration"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// Span 一段耗时，方法对 nil 安全，未开启追踪时 Start 返回 nil
+type Span struct {
+	tracer  *Tracer
+	slot    *spanSlot
+	parent  *Span
+	traceId TraceID
+	spanId  SpanID
+
+	locker sync.Mutex
+	data   SpanData
+	ended  bool
+}
+
+func newSpan(tracer *Tracer, slot *spanSlot, parent *Span, traceId TraceID, parentId SpanID, name string) *Span {
+	s := &Span{tracer: tracer, slot: slot, parent: parent, traceId: traceId}
+	rand.Read(s.spanId[:])
+	s.data = SpanData{
+		TraceID: traceId.String(),
+		SpanID:  s.spanId.String(),
+		Name:    name,
+		Start:   time.Now(),
+	}
+	if parentId.IsValid() {
+		s.data.ParentID = parentId.String()
+	}
+	slot.set(s)
+	return s
+}
+
+// FromContext 当前的 span，未开启追踪时返回 nil
+func FromContext(ctx eocontext.EoContext) *Span {
+	if ctx == nil {
+		return nil
+	}
+	slot, _ := ctx.Value(spanKey{}).(*spanSlot)
+	if slot == nil {
+		return nil
+	}
+	return slot.get()
+}
+
+// Start 在当前 span 下开始子 span，并作为 ctx 的当前 span，直到 End；未开启追踪时返回 nil
+func Start(ctx eocontext.EoContext, name string) *Span {
+	parent := FromContext(ctx)
+	if parent == nil {
+		return nil
+	}
+	return newSpan(parent.tracer, parent.slot, parent, parent.traceId, parent.spanId, name)
+}
+
+func (s *Span) TraceID() TraceID {
+	if s == nil {
+		return TraceID{}
+	}
+	return s.traceId
+}
+
+func (s *Span) SpanID() SpanID {
+	if s == nil {
+		return SpanID{}
+	}
+	return s.spanId
+}
+
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]string)
+	}
+	s.data.Attributes[key] = value
+}
+
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.data.Error = err.Error()
+}
+
+// End 结束 span 并导出，ctx 的当前 span 恢复为父 span；重复调用无效
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.locker.Lock()
+	if s.ended {
+		s.locker.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	s.data.Duration = s.data.End.Sub(s.data.Start)
+	data := s.data
+	s.locker.Unlock()
+
+	s.slot.restore(s)
+	s.tracer.export(&data)
+}
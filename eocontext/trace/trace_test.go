@@ -0,0 +1,206 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	http_context "github.com/eolinker/eosc/eocontext/http-context"
+)
+
+type testHeader struct {
+	http_context.IHeaderWriter
+	values map[string]string
+}
+
+func (h *testHeader) GetHeader(name string) string { return h.values[name] }
+func (h *testHeader) SetHeader(key, value string)  { h.values[key] = value }
+
+type testRequest struct {
+	http_context.IRequest
+	header *testHeader
+}
+
+func (r *testRequest) Header() http_context.IHeaderWriter { return r.header }
+
+type testRequestReader struct {
+	http_context.IRequestReader
+	header *testHeader
+}
+
+func (r *testRequestReader) Header() http_context.IHeaderReader { return r.header }
+
+type testResponse struct {
+	http_context.IResponse
+}
+
+func (r *testResponse) StatusCode() int { return 200 }
+
+type testContext struct {
+	http_context.IHttpContext
+	values  map[interface{}]interface{}
+	sets    int
+	request *testHeader
+	proxy   *testHeader
+	sendErr error
+}
+
+func newContext(traceParent string) *testContext {
+	c := &testContext{
+		values:  make(map[interface{}]interface{}),
+		request: &testHeader{values: map[string]string{}},
+		proxy:   &testHeader{values: map[string]string{}},
+	}
+	if traceParent != "" {
+		c.request.values[TraceParentHeader] = traceParent
+	}
+	return c
+}
+
+func (c *testContext) Value(key interface{}) interface{} { return c.values[key] }
+func (c *testContext) WithValue(key, val interface{}) {
+	c.values[key] = val
+	c.sets++
+}
+func (c *testContext) Request() http_context.IRequestReader {
+	return &testRequestReader{header: c.request}
+}
+func (c *testContext) Proxy() http_context.IRequest     { return &testRequest{header: c.proxy} }
+func (c *testContext) Response() http_context.IResponse { return &testResponse{} }
+func (c *testContext) SendTo(address string, timeout time.Duration) error {
+	return c.sendErr
+}
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		value   string
+		trace   string
+		span    string
+		wantErr bool
+	}{
+		{value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", trace: "4bf92f3577b34da6a3ce929d0e0e4736", span: "00f067aa0ba902b7"},
+		{value: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+		{value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: true},
+		{value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902zz-01", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			traceId, spanId, err := ParseTraceParent(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTraceParent() error = %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if traceId.String() != tt.trace || spanId.String() != tt.span {
+				t.Errorf("ParseTraceParent() = %s %s", traceId, spanId)
+			}
+			if got := FormatTraceParent(traceId, spanId); got != tt.value {
+				t.Errorf("FormatTraceParent() = %s", got)
+			}
+		})
+	}
+}
+
+func TestSpans(t *testing.T) {
+	exporter := NewMemoryExporter()
+	tracer := NewTracer(exporter)
+	ctx := newContext("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	if Start(newContext(""), "untraced") != nil {
+		t.Fatal("span started without tracer")
+	}
+	traced, root := tracer.Begin(ctx, "request")
+	child := Start(traced, "filter")
+	child.SetAttribute("k", "v")
+	if err := traced.(http_context.IHttpContext).SendTo("http://127.0.0.1:80", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	// 断言出的 IHttpContext 同样记录 send span
+	hc, err := http_context.Assert(traced)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.sendErr = errors.New("refused")
+	hc.SendTo("http://127.0.0.1:81", time.Second)
+	child.End()
+	if FromContext(ctx) != root {
+		t.Fatal("current span not restored")
+	}
+	root.End()
+	root.End()
+	if ctx.sets != 1 {
+		t.Errorf("WithValue called %d times, want 1", ctx.sets)
+	}
+
+	spans := exporter.Spans()
+	if len(spans) != 4 {
+		t.Fatalf("exported %d spans", len(spans))
+	}
+	send, failed, filter, request := spans[0], spans[1], spans[2], spans[3]
+	if request.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || request.ParentID != "00f067aa0ba902b7" {
+		t.Errorf("request span not continued: %+v", request)
+	}
+	if filter.ParentID != request.SpanID || send.ParentID != filter.SpanID || filter.Attributes["k"] != "v" {
+		t.Errorf("filter span: %+v", filter)
+	}
+	if send.Attributes["status"] != "200" || failed.Error != "refused" {
+		t.Errorf("send spans: %+v %+v", send, failed)
+	}
+	// 最后一次转发的 traceparent 指向其 send span
+	if got, want := ctx.proxy.values[TraceParentHeader], "00-"+failed.TraceID+"-"+failed.SpanID+"-01"; got != want {
+		t.Errorf("traceparent = %s, want %s", got, want)
+	}
+}
+
+func readSpanNames(t *testing.T, path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		span := &SpanData{}
+		if err := json.Unmarshal(scanner.Bytes(), span); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, span.Name)
+	}
+	return names
+}
+
+func TestFileExporter(t *testing.T) {
+	old := flushInterval
+	flushInterval = 10 * time.Millisecond
+	defer func() { flushInterval = old }()
+
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	exporter, err := NewFileExporter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer := NewTracer(exporter)
+	ctx := newContext("")
+	_, root := tracer.Begin(ctx, "request")
+	Start(ctx, "filter").End()
+	root.End()
+
+	// 未关闭时也会定时写入文件
+	deadline := time.Now().Add(time.Second)
+	for len(readSpanNames(t, path)) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if names := readSpanNames(t, path); len(names) != 2 || names[0] != "filter" || names[1] != "request" {
+		t.Errorf("spans = %v", names)
+	}
+	if err := exporter.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,49 @@
+package trace
+
+import (
+	"crypto/rand"
+
+	"github.com/eolinker/eosc/eocontext"
+	http_context "github.com/eolinker/eosc/eocontext/http-context"
+	"github.com/eolinker/eosc/log"
+)
+
+// Exporter 导出结束的 span
+type Exporter interface {
+	Export(span *SpanData) error
+	Close() error
+}
+
+// Tracer 为请求开启追踪，结束的 span 交给 exporter
+type Tracer struct {
+	exporter Exporter
+}
+
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{exporter: exporter}
+}
+
+// Begin 为请求开始根 span；http 请求携带合法的 traceparent 时延续上游的 trace
+// 后续处理应使用返回的 ctx，其 SendTo 会自动记录 send span 并设置 traceparent
+func (t *Tracer) Begin(ctx eocontext.EoContext, name string) (eocontext.EoContext, *Span) {
+	var traceId TraceID
+	var parentId SpanID
+	if hc, ok := ctx.(http_context.IHttpContext); ok {
+		traceId, parentId, _ = ParseTraceParent(hc.Request().Header().GetHeader(TraceParentHeader))
+	}
+	if !traceId.IsValid() {
+		rand.Read(traceId[:])
+	}
+	slot := &spanSlot{}
+	ctx.WithValue(spanKey{}, slot)
+	return withSendTrace(ctx), newSpan(t, slot, nil, traceId, parentId, name)
+}
+
+func (t *Tracer) export(span *SpanData) {
+	if t.exporter == nil {
+		return
+	}
+	if err := t.exporter.Export(span); err != nil {
+		log.Warn("export span:", err)
+	}
+}
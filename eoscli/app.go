@@ -49,6 +49,9 @@ func (a *App) Default() {
 		Join(),
 		Stop(),
 		Info(),
+		Status(),
+		Upgrade(),
+		Version(),
 		Leave(),
 		Restart(),
 		//Env(),
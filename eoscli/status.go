@@ -0,0 +1,78 @@
+package eoscli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/env"
+	"github.com/eolinker/eosc/service"
+	"github.com/urfave/cli/v2"
+)
+
+var CmdStatus = "status"
+
+func Status() *cli.Command {
+	return &cli.Command{
+		Name:  CmdStatus,
+		Usage: "display the processes status of the node",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "stderr",
+				Usage: "print the last stderr output of exited processes",
+			},
+		},
+		Action: StatusFunc,
+	}
+}
+
+// StatusFunc 显示当前节点 master、admin、worker 进程的状态
+func StatusFunc(c *cli.Context) error {
+	pid, err := readPid(env.PidFileDir())
+	if err != nil {
+		return err
+	}
+	client, err := createCtlServiceClient(pid)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	response, err := client.Processes(context.Background(), &service.ProcessesRequest{})
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tPID\tSTATUS\tUPTIME\tRESTARTS\tLAST EXIT")
+	for _, p := range response.Processes {
+		name := p.Name
+		if p.Name == eosc.ProcessWorker {
+			name = fmt.Sprintf("%s-%d", p.Name, p.Index)
+		}
+		pidStr, uptime, lastExit := "-", "-", "-"
+		if p.Pid > 0 {
+			pidStr = fmt.Sprint(p.Pid)
+		}
+		if p.Uptime != "" {
+			uptime = p.Uptime
+		}
+		if p.LastExit != nil {
+			lastExit = fmt.Sprintf("%s (code %d)", p.LastExit.Reason, p.LastExit.Code)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\n", name, pidStr, p.Status, uptime, p.Restarts, lastExit)
+	}
+	tw.Flush()
+
+	if !c.Bool("stderr") {
+		return nil
+	}
+	for _, p := range response.Processes {
+		if p.LastExit == nil || p.LastExit.Stderr == "" {
+			continue
+		}
+		fmt.Printf("\n[%s %d] stderr:\n%s\n", p.Name, p.LastExit.Pid, p.LastExit.Stderr)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package eoscli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eolinker/eosc/env"
+	"github.com/eolinker/eosc/log"
+	"github.com/urfave/cli/v2"
+)
+
+var CmdUpgrade = "upgrade"
+
+var (
+	errUpgradeRollback = errors.New("new master failed to start, the old master resumed serving")
+	errUpgradeTimeout  = errors.New("wait new master timeout")
+)
+
+func Upgrade() *cli.Command {
+	return &cli.Command{
+		Name:  CmdUpgrade,
+		Usage: fmt.Sprintf("hot upgrade %s server without downtime", env.AppName()),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "binary",
+				Usage: "path of the new executable file, default is the executable of the running master",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "time to wait for the new master to be ready",
+				Value: time.Second * 90,
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "allow downgrade",
+			},
+		},
+		Action: UpgradeFunc,
+	}
+}
+
+// UpgradeFunc 热升级：检查新文件版本，替换可执行文件后通知 master fork，
+// 新 master 就绪后旧 master 退出；新 master 启动失败时旧 master 恢复服务，可执行文件也还原
+func UpgradeFunc(c *cli.Context) error {
+	pidDir := env.PidFileDir()
+	pid, err := readPid(pidDir)
+	if err != nil {
+		return err
+	}
+	if !processExists(pid) {
+		return fmt.Errorf("master %d not running", pid)
+	}
+	target := masterExecutable(pid)
+
+	var restore func()
+	if binary := c.String("binary"); binary != "" {
+		binary, err = filepath.Abs(binary)
+		if err != nil {
+			return err
+		}
+		v, err := checkBinaryVersion(binary, c.Bool("force"))
+		if err != nil {
+			return err
+		}
+		log.Infof("upgrade %s to eosc %s (%s)", target, v.Eosc, v.Go)
+		if binary != target {
+			restore, err = replaceExecutable(target, binary)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = restartProcess()
+	if err == nil {
+		err = waitUpgrade(pidDir, pid, c.Duration("timeout"))
+	}
+	if err != nil {
+		if restore != nil {
+			restore()
+		}
+		return err
+	}
+	newPid, _ := readPid(pidDir)
+	log.Infof("upgrade successful, master pid %d -> %d", pid, newPid)
+	return nil
+}
+
+// masterExecutable 运行中的 master 的可执行文件路径
+func masterExecutable(pid int) string {
+	p, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	if err == nil {
+		return strings.TrimSuffix(p, " (deleted)")
+	}
+	p, _ = os.Executable()
+	return p
+}
+
+func checkBinaryVersion(binary string, force bool) (*VersionInfo, error) {
+	out := &bytes.Buffer{}
+	cmd := exec.Command(binary, CmdVersion, "--json")
+	cmd.Stdout = out
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("check version of %s:%w", binary, err)
+	}
+	v := new(VersionInfo)
+	if err := json.Unmarshal(out.Bytes(), v); err != nil {
+		return nil, fmt.Errorf("read version of %s:%w", binary, err)
+	}
+	current := currentVersion()
+	if v.OS != current.OS || v.Arch != current.Arch {
+		return nil, fmt.Errorf("%s is built for %s/%s, need %s/%s", binary, v.OS, v.Arch, current.OS, current.Arch)
+	}
+	if compareVersion(v.Eosc, current.Eosc) < 0 && !force {
+		return nil, fmt.Errorf("eosc %s is older than %s, use --force to downgrade", v.Eosc, current.Eosc)
+	}
+	return v, nil
+}
+
+// replaceExecutable 用新文件替换 target，原文件备份为 .bak，返回还原函数
+func replaceExecutable(target, binary string) (func(), error) {
+	backup := target + ".bak"
+	tmp := target + ".new"
+	if err := copyFile(binary, tmp); err != nil {
+		return nil, err
+	}
+	if err := os.Link(target, backup); err != nil {
+		os.Remove(backup)
+		if err := os.Link(target, backup); err != nil {
+			os.Remove(tmp)
+			return nil, err
+		}
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	return func() {
+		if err := os.Rename(backup, target); err != nil {
+			log.Error("restore executable:", err)
+			return
+		}
+		log.Info("executable restored:", target)
+	}, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
+// waitUpgrade 通过 pid 文件判断升级结果：旧进程退出且 pid 文件指向运行中的新进程为成功；
+// pid 文件在 fork 之后又指回旧进程说明旧 master 已回滚
+func waitUpgrade(pidDir string, oldPid int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	forked := false
+	for time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 200)
+		pid, err := readPid(pidDir)
+		if !processExists(oldPid) {
+			if err == nil && pid != oldPid && processExists(pid) {
+				return nil
+			}
+			continue
+		}
+		if err != nil || pid != oldPid {
+			forked = true
+			continue
+		}
+		if forked {
+			return errUpgradeRollback
+		}
+	}
+	return errUpgradeTimeout
+}
@@ -0,0 +1,67 @@
+package eoscli
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+// deadPid 不存在的进程号
+const deadPid = 1 << 30
+
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"1.2.3", "v1.2.3", 0},
+		{"v1.2", "v1.2.0", 0},
+		{"v1.10.0", "v1.9.0", 1},
+		{"v1.2.3", "v1.3", -1},
+		{"v2", "v1.99.99", 1},
+	}
+	for _, tt := range tests {
+		got := compareVersion(tt.a, tt.b)
+		if (got > 0) != (tt.want > 0) || (got < 0) != (tt.want < 0) {
+			t.Errorf("compareVersion(%q, %q) = %d, want sign of %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func writePid(t *testing.T, dir string, pid int) {
+	if err := os.WriteFile(getPidFile(dir), []byte(fmt.Sprint(pid)), 0666); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitUpgrade(t *testing.T) {
+	self := os.Getpid()
+	tests := []struct {
+		name   string
+		oldPid int
+		pids   []int
+		want   error
+	}{
+		{name: "new master ready", oldPid: deadPid, pids: []int{self}, want: nil},
+		{name: "new master not running", oldPid: deadPid, pids: []int{deadPid}, want: errUpgradeTimeout},
+		{name: "old master still running", oldPid: self, pids: []int{self}, want: errUpgradeTimeout},
+		{name: "old master resumed", oldPid: self, pids: []int{deadPid, self}, want: errUpgradeRollback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writePid(t, dir, tt.pids[0])
+			if len(tt.pids) > 1 {
+				go func() {
+					time.Sleep(time.Millisecond * 500)
+					os.WriteFile(getPidFile(dir), []byte(fmt.Sprint(tt.pids[1])), 0666)
+				}()
+			}
+			if err := waitUpgrade(dir, tt.oldPid, time.Millisecond*1500); err != tt.want {
+				t.Errorf("waitUpgrade() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
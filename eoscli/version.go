@@ -0,0 +1,74 @@
+package eoscli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/eosc"
+	"github.com/urfave/cli/v2"
+)
+
+var CmdVersion = "version"
+
+// VersionInfo 可执行文件的版本信息，热升级前用于检查新文件是否兼容
+type VersionInfo struct {
+	Eosc string `json:"eosc"`
+	Go   string `json:"go"`
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+}
+
+func currentVersion() *VersionInfo {
+	return &VersionInfo{
+		Eosc: eosc.Version(),
+		Go:   runtime.Version(),
+		OS:   runtime.GOOS,
+		Arch: runtime.GOARCH,
+	}
+}
+
+func Version() *cli.Command {
+	return &cli.Command{
+		Name:  CmdVersion,
+		Usage: "display version information",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "output as json",
+			},
+		},
+		Action: VersionFunc,
+	}
+}
+
+func VersionFunc(c *cli.Context) error {
+	v := currentVersion()
+	if c.Bool("json") {
+		return json.NewEncoder(os.Stdout).Encode(v)
+	}
+	fmt.Printf("eosc:\t%s\ngo:\t%s\nos/arch:\t%s/%s\n", v.Eosc, v.Go, v.OS, v.Arch)
+	return nil
+}
+
+// compareVersion 按数字逐段比较版本号，a<b 返回负数
+func compareVersion(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
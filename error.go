@@ -16,6 +16,8 @@ var (
 	ErrorStoreReadOnly              = errors.New("store read only")
 	ErrorRequire                    = errors.New("require")
 	ErrorProfessionDependencies     = errors.New("profession dependencies not complete")
+	ErrorProfessionDependencyCycle  = errors.New("profession dependency cycle")
+	ErrorProfessionDependent        = errors.New("profession is depended on")
 	ErrorConfigIsNil                = errors.New("config is nil")
 	ErrorConfigFieldUnknown         = errors.New("unknown type")
 	ErrorConfigType                 = errors.New("error config type")
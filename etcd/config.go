@@ -174,8 +174,12 @@ func (s *_Server) resetCluster(InitialCluster string) {
 	etcdConfig.Set("cluster", InitialCluster)
 }
 func (s *_Server) updateCluster() {
+	client, err := s.getClient()
+	if err != nil {
+		return
+	}
 	ctx, _ := s.requestContext()
-	s.client.MemberList(ctx)
+	client.MemberList(ctx)
 }
 func (s *_Server) clearCluster() {
 	s.resetCluster("")
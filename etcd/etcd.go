@@ -8,12 +8,15 @@ import (
 type Versions version.Versions
 type Etcd interface {
 	IsLeader() (bool, []string)
+	Term() uint64
 	KV
 	Watch(prefix string, handler ServiceHandler)
 	HandlerLeader(h ...ILeaderStateHandler)
 	Join(target string) error
 	Leave() error
 	Close() error
+	Suspend() error
+	Resume() error
 	Info() *Node
 	Nodes() []*Node
 	Status() ClusterInfo
@@ -21,6 +21,7 @@ var (
 	ErrorAlreadyInCluster = errors.New("already in cluster")
 	ErrorNotInCluster     = errors.New("not in cluster")
 	ErrorMemberNotExist   = errors.New("member not exist")
+	ErrorServerSuspended  = errors.New("etcd server suspended")
 )
 
 func (s *_Server) initEtcdServer() error {
@@ -49,11 +50,25 @@ func (s *_Server) initEtcdServer() error {
 	s.client.Put(s.ctx, fmt.Sprintf("~/nodes/%s", s.server.ID()), string(data))
 
 	s.clusterData = NewClusters(s.ctx, s.client, s)
+	return nil
+}
 
-	for _, ch := range s.clientCh {
-		ch <- s.client
+// notifyClient 把新的 client 发给所有 watcher，需要在释放 s.mu 之后调用，避免 watcher 回调 server 时死锁
+func (s *_Server) notifyClient() {
+	s.mu.RLock()
+	client := s.client
+	chs := s.clientCh
+	s.mu.RUnlock()
+	if client == nil {
+		return
+	}
+	for _, ch := range chs {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- client
 	}
-	return nil
 }
 
 func (s *_Server) check(srv *etcdserver.EtcdServer) {
@@ -153,6 +168,15 @@ func (s *_Server) Join(target string) error {
 	if err != nil {
 		return err
 	}
+	err = s.joinCluster(target, urls, clientUrls)
+	if err != nil {
+		return err
+	}
+	s.notifyClient()
+	return nil
+}
+
+func (s *_Server) joinCluster(target string, urls, clientUrls types.URLs) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -184,7 +208,11 @@ func (s *_Server) Remove(name string) error {
 			if m.ID == s.server.Leader() {
 				return fmt.Errorf("cannot remove leader")
 			}
-			_, err := s.client.Delete(s.ctx, fmt.Sprintf("~/nodes/%s", m.ID))
+			client, err := s.currentClient()
+			if err != nil {
+				return err
+			}
+			_, err = client.Delete(s.ctx, fmt.Sprintf("~/nodes/%s", m.ID))
 			if err != nil {
 				return err
 			}
@@ -198,6 +226,15 @@ func (s *_Server) Remove(name string) error {
 }
 
 func (s *_Server) Leave() error {
+	err := s.leave()
+	if err != nil {
+		return err
+	}
+	s.notifyClient()
+	return nil
+}
+
+func (s *_Server) leave() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.server == nil {
@@ -222,7 +259,11 @@ func (s *_Server) Leave() error {
 
 	// leave相关操作
 	// 集群中删除自己
-	_, err = s.client.Delete(s.ctx, fmt.Sprintf("~/nodes/%s", s.server.ID()))
+	client, err := s.currentClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.Delete(s.ctx, fmt.Sprintf("~/nodes/%s", s.server.ID()))
 	if err != nil {
 		return err
 	}
@@ -251,6 +292,33 @@ func (s *_Server) Close() error {
 	s.cancel()
 	return s.close()
 }
+
+// Suspend 停止 etcd 服务并释放数据目录，热升级时交给新的 master 进程；新进程启动失败时通过 Resume 恢复
+func (s *_Server) Suspend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.close()
+}
+
+// Resume 恢复被 Suspend 停止的 etcd 服务
+func (s *_Server) Resume() error {
+	err := s.resume()
+	if err != nil {
+		return err
+	}
+	s.notifyClient()
+	return nil
+}
+
+func (s *_Server) resume() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.server != nil {
+		return nil
+	}
+	return s.initEtcdServer()
+}
+
 func (s *_Server) close() error {
 	emptyHandler := http.NotFoundHandler()
 	s.raftHandler.Swap(&emptyHandler)
@@ -299,7 +367,10 @@ func (s *_Server) closeServer() {
 }
 
 func (s *_Server) getAllData() (map[string][]byte, error) {
-	client := s.client
+	client, err := s.currentClient()
+	if err != nil {
+		return nil, err
+	}
 	resp, err := func() (*clientv3.GetResponse, error) {
 		ctx, cancel := s.requestContext()
 		defer cancel()
@@ -323,7 +394,11 @@ func (s *_Server) removeMember(id uint64) error {
 }
 
 func (s *_Server) resetAllData(data map[string][]byte) {
-	client := s.client
+	client, err := s.currentClient()
+	if err != nil {
+		log.Warn("reset all data error : %s", err.Error())
+		return
+	}
 
 	for key, bytes := range data {
 		_, err := client.Put(s.ctx, key, string(bytes))
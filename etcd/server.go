@@ -127,6 +127,17 @@ func (s *_Server) IsLeader() (bool, []string) {
 	defer s.mu.RUnlock()
 	return s.isLeader()
 }
+
+// Term 当前 raft 任期，server 未启动时为 0
+func (s *_Server) Term() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.server == nil {
+		return 0
+	}
+	return s.server.Term()
+}
+
 func (s *_Server) isLeader() (bool, []string) {
 	server := s.server
 	lead := server.Leader()
@@ -138,27 +149,59 @@ func (s *_Server) isLeader() (bool, []string) {
 }
 
 func (s *_Server) Put(key string, value []byte) error {
-
+	client, err := s.getClient()
+	if err != nil {
+		return err
+	}
 	ctx, _ := s.requestContext()
-	_, err := s.client.Put(ctx, key, string(value))
+	_, err = client.Put(ctx, key, string(value))
 
 	return err
 
 }
 
-func (s *_Server) Delete(key string) error {
+func (s *_Server) getClient() (*clientv3.Client, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.currentClient()
+}
 
+// currentClient 调用方需持有 s.mu，Suspend 之后 client 为空
+func (s *_Server) currentClient() (*clientv3.Client, error) {
+	if s.client == nil {
+		return nil, ErrorServerSuspended
+	}
+	return s.client, nil
+}
+
+func (s *_Server) nodeValue() []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.server == nil {
+		return nil
+	}
+	return []byte(fmt.Sprintf("{\"cluster_id\":\"%s\",\"node_id\":\"%s\"}", s.clusterData.cluster, s.server.ID().String()))
+}
+
+func (s *_Server) Delete(key string) error {
+	client, err := s.getClient()
+	if err != nil {
+		return err
+	}
 	ctx, _ := s.requestContext()
-	_, err := s.client.Delete(ctx, key)
+	_, err = client.Delete(ctx, key)
 
 	return err
 }
 
+// Watch 首次加载完成后返回，Suspend 期间会等到 Resume 之后再加载
 func (s *_Server) Watch(prefix string, handler ServiceHandler) {
 	clientCh := make(chan *clientv3.Client, 1)
 	s.mu.Lock()
 	s.clientCh = append(s.clientCh, clientCh)
-	clientCh <- s.client
+	if s.client != nil {
+		clientCh <- s.client
+	}
 	s.mu.Unlock()
 	wg := sync.WaitGroup{}
 	wg.Add(1)
@@ -177,7 +220,13 @@ func (s *_Server) Watch(prefix string, handler ServiceHandler) {
 					response, err := client.Get(ctx, prefix, clientv3.WithPrefix())
 					if err != nil {
 						log.Warn("watch ", prefix, " error:", err)
-						clientCh <- client
+						// client 已被 Suspend 关闭时等待 Resume 重新下发
+						if current, err := s.getClient(); err == nil && current == client {
+							select {
+							case clientCh <- client:
+							default:
+							}
+						}
 						continue
 					}
 					watch = client.Watch(s.ctx, prefix, clientv3.WithPrefix())
@@ -190,7 +239,7 @@ func (s *_Server) Watch(prefix string, handler ServiceHandler) {
 					}
 					init = append(init, &KValue{
 						Key:   []byte("/cluster/node"),
-						Value: []byte(fmt.Sprintf("{\"cluster_id\":\"%s\",\"node_id\":\"%s\"}", s.clusterData.cluster, s.server.ID().String())),
+						Value: s.nodeValue(),
 					})
 					handler.Reset(init)
 					once.Do(func() {
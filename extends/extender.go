@@ -16,6 +16,7 @@ import (
 	"github.com/eolinker/eosc"
 
 	"github.com/eolinker/eosc/env"
+	"github.com/eolinker/eosc/extends/remote"
 	"github.com/eolinker/eosc/log"
 )
 
@@ -73,6 +74,15 @@ func look(group, project, version string) ([]RegisterFunc, error) {
 		return nil, fmt.Errorf("%s-%s:%w", group, project, ErrorExtenderNotFindLocal)
 	}
 	if len(files) < 1 {
+		// 没有 go plugin 时尝试以进程外插件加载
+		if path, has := remote.Lookup(dir); has {
+			p, err := remote.Open(FormatProject(group, project), path)
+			if err != nil {
+				log.Errorf("error to open remote extender %s:%s", path, err.Error())
+				return nil, err
+			}
+			return []RegisterFunc{p.Register}, nil
+		}
 		log.Error(ErrorExtenderNotFindLocal)
 		return nil, fmt.Errorf("%s-%s:%w", group, project, ErrorExtenderNotFindLocal)
 	}
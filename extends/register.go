@@ -1,11 +1,16 @@
 package extends
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/metrics"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+var _ eosc.ICollectorRegister = (*ExtenderRegister)(nil)
+
 type IExtenderRegister interface {
 	eosc.IExtenderDriverManager
 	eosc.IExtenderDrivers
@@ -30,6 +35,16 @@ func (r *ExtenderRegister) RegisterExtenderDriver(name string, factory eosc.IExt
 	return nil
 }
 
+// RegisterCollector 插件的指标附带 extender 标签；同一插件被重复加载时忽略重复注册
+func (r *ExtenderRegister) RegisterCollector(collector prometheus.Collector) error {
+	err := metrics.With(prometheus.Labels{"extender": toId(r.group, r.project)}).Register(collector)
+	var already prometheus.AlreadyRegisteredError
+	if errors.As(err, &already) {
+		return nil
+	}
+	return err
+}
+
 func (r *ExtenderRegister) RegisterTo(register eosc.IExtenderDriverRegister) {
 	for n, f := range r.data {
 		id := FormatDriverId(r.group, r.project, n)
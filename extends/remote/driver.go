@@ -0,0 +1,281 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/log"
+	"github.com/eolinker/eosc/service"
+	"github.com/eolinker/eosc/utils/config"
+	"github.com/eolinker/eosc/variable"
+)
+
+var (
+	_ eosc.IExtenderDriverFactory  = (*factory)(nil)
+	_ eosc.IExtenderDriver         = (*driver)(nil)
+	_ eosc.IExtenderConfigChecker  = (*driver)(nil)
+	_ eosc.IExtenderConfigRequires = (*driver)(nil)
+	_ eosc.IWorker                 = (*worker)(nil)
+	_ eosc.IWorkerDestroy          = (*worker)(nil)
+
+	configType = reflect.TypeOf(Config{})
+)
+
+// Config 进程外插件的worker配置，变量替换后原样以json传递给插件进程
+type Config = variable.Object
+
+// Register 将插件进程提供的所有factory注册到当前进程
+func (p *Process) Register(register eosc.IExtenderDriverRegister) {
+	p.locker.RLock()
+	factories := p.factories
+	p.locker.RUnlock()
+	for _, f := range factories {
+		err := register.RegisterExtenderDriver(f.Name, &factory{process: p, name: f.Name, render: f.Render})
+		if err != nil {
+			log.Errorf("register remote factory %s of %s:%v", f.Name, p.id, err)
+		}
+	}
+}
+
+type factory struct {
+	process *Process
+	name    string
+	render  []byte
+}
+
+func (f *factory) Render() interface{} {
+	if len(f.render) == 0 {
+		return nil
+	}
+	return json.RawMessage(f.render)
+}
+
+func (f *factory) Create(profession string, name string, label string, desc string, params map[string]interface{}) (eosc.IExtenderDriver, error) {
+	paramsData, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	request := &service.RemoteDriverRequest{
+		Factory:    f.name,
+		Profession: profession,
+		Name:       name,
+		Label:      label,
+		Desc:       desc,
+		Params:     paramsData,
+	}
+	var handle string
+	var requires []config.RequireField
+	err = f.process.call(func(ctx context.Context, client service.RemoteExtenderClient) error {
+		response, err := client.CreateDriver(ctx, request)
+		if err != nil {
+			return err
+		}
+		handle = response.Driver
+		requires = toRequireFields(response.Requires)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	f.process.locker.Lock()
+	f.process.drivers[handle] = request
+	f.process.locker.Unlock()
+	return &driver{process: f.process, handle: handle, requires: requires}, nil
+}
+
+type driver struct {
+	process  *Process
+	handle   string
+	requires []config.RequireField
+}
+
+func (d *driver) ConfigType() reflect.Type {
+	return configType
+}
+
+// Requires 按插件进程返回的依赖字段解析依赖
+func (d *driver) Requires(v interface{}, workers eosc.IWorkers) (map[eosc.RequireId]eosc.IWorker, error) {
+	return config.CheckRequireFields(v, d.requires, workers)
+}
+
+// skills 配置中每个依赖需要的能力，随请求发送给插件进程
+func (d *driver) skills(v interface{}, workers map[eosc.RequireId]eosc.IWorker) []*service.RemoteSkillRequest {
+	r := &skillRecorder{workers: workers}
+	config.CheckRequireFields(v, d.requires, r)
+	return r.skills
+}
+
+func (d *driver) request(id, name string, v interface{}, workers map[eosc.RequireId]eosc.IWorker) (*service.RemoteWorkerRequest, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	requires := make([]string, 0, len(workers))
+	for rid := range workers {
+		requires = append(requires, string(rid))
+	}
+	sort.Strings(requires)
+	return &service.RemoteWorkerRequest{
+		Driver:   d.handle,
+		Id:       id,
+		Name:     name,
+		Config:   data,
+		Requires: requires,
+		Skills:   d.skills(v, workers),
+	}, nil
+}
+
+func (d *driver) Check(v interface{}, workers map[eosc.RequireId]eosc.IWorker) error {
+	request, err := d.request("", "", v, workers)
+	if err != nil {
+		return err
+	}
+	return d.process.call(func(ctx context.Context, client service.RemoteExtenderClient) error {
+		_, err := client.CheckConfig(ctx, request)
+		return err
+	})
+}
+
+func (d *driver) Create(id, name string, v interface{}, workers map[eosc.RequireId]eosc.IWorker) (eosc.IWorker, error) {
+	request, err := d.request(id, name, v, workers)
+	if err != nil {
+		return nil, err
+	}
+	err = d.process.call(func(ctx context.Context, client service.RemoteExtenderClient) error {
+		_, err := client.CreateWorker(ctx, request)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.process.locker.Lock()
+	d.process.workers[id] = &workerState{request: request}
+	d.process.locker.Unlock()
+	return &worker{process: d.process, driver: d, id: id, name: name}, nil
+}
+
+type worker struct {
+	process *Process
+	driver  *driver
+	id      string
+	name    string
+}
+
+func (w *worker) Id() string {
+	return w.id
+}
+
+func (w *worker) Start() error {
+	err := w.process.call(func(ctx context.Context, client service.RemoteExtenderClient) error {
+		_, err := client.StartWorker(ctx, &service.RemoteWorkerId{Id: w.id})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	w.process.locker.Lock()
+	if state, has := w.process.workers[w.id]; has {
+		state.running = true
+	}
+	w.process.locker.Unlock()
+	return nil
+}
+
+func (w *worker) Reset(conf interface{}, workers map[eosc.RequireId]eosc.IWorker) error {
+	request, err := w.driver.request(w.id, w.name, conf, workers)
+	if err != nil {
+		return err
+	}
+	err = w.process.call(func(ctx context.Context, client service.RemoteExtenderClient) error {
+		_, err := client.ResetWorker(ctx, request)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	w.process.locker.Lock()
+	if state, has := w.process.workers[w.id]; has {
+		state.request = request
+	}
+	w.process.locker.Unlock()
+	return nil
+}
+
+func (w *worker) Stop() error {
+	err := w.process.call(func(ctx context.Context, client service.RemoteExtenderClient) error {
+		_, err := client.StopWorker(ctx, &service.RemoteWorkerId{Id: w.id})
+		return err
+	})
+	// 停止后的worker不会再被启动，插件进程重启时无需恢复
+	w.process.locker.Lock()
+	delete(w.process.workers, w.id)
+	w.process.locker.Unlock()
+	return err
+}
+
+func (w *worker) CheckSkill(skill string) bool {
+	has := false
+	err := w.process.call(func(ctx context.Context, client service.RemoteExtenderClient) error {
+		response, err := client.CheckSkill(ctx, &service.RemoteSkillRequest{Id: w.id, Skill: skill})
+		if err != nil {
+			return err
+		}
+		has = response.Has
+		return nil
+	})
+	if err != nil {
+		log.Warnf("check skill %s of remote worker %s:%v", skill, w.id, err)
+	}
+	return has
+}
+
+func (w *worker) Destroy() error {
+	w.process.locker.Lock()
+	delete(w.process.workers, w.id)
+	w.process.locker.Unlock()
+	return w.process.call(func(ctx context.Context, client service.RemoteExtenderClient) error {
+		_, err := client.DestroyWorker(ctx, &service.RemoteWorkerId{Id: w.id})
+		return err
+	})
+}
+
+func toRequireFields(requires []*service.RemoteRequire) []config.RequireField {
+	fields := make([]config.RequireField, 0, len(requires))
+	for _, r := range requires {
+		fields = append(fields, config.RequireField{
+			Path:     r.Path,
+			Skill:    r.Skill,
+			Optional: r.Optional,
+			Multi:    r.Multi,
+		})
+	}
+	return fields
+}
+
+// skillRecorder 记录已解析的依赖被检查的能力，能力已在解析依赖时检查过
+type skillRecorder struct {
+	workers map[eosc.RequireId]eosc.IWorker
+	skills  []*service.RemoteSkillRequest
+}
+
+func (r *skillRecorder) Get(id string) (eosc.IWorker, bool) {
+	w, has := r.workers[eosc.RequireId(id)]
+	if !has {
+		return nil, false
+	}
+	return &skillWorker{IWorker: w, id: id, recorder: r}, true
+}
+
+type skillWorker struct {
+	eosc.IWorker
+	id       string
+	recorder *skillRecorder
+}
+
+func (w *skillWorker) CheckSkill(skill string) bool {
+	w.recorder.skills = append(w.recorder.skills, &service.RemoteSkillRequest{Id: w.id, Skill: skill})
+	return true
+}
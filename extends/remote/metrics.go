@@ -0,0 +1,34 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/eolinker/eosc/metrics"
+	"github.com/eolinker/eosc/service"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var _ prometheus.Gatherer = (*Process)(nil)
+
+// Gather 拉取插件进程内注册的指标
+func (p *Process) Gather() ([]*dto.MetricFamily, error) {
+	var data []byte
+	err := p.call(func(ctx context.Context, client service.RemoteExtenderClient) error {
+		response, err := client.Metrics(ctx, &service.EmptyRequest{})
+		if err != nil {
+			return err
+		}
+		data = response.Data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metrics.Decode(bytes.NewReader(data))
+}
+
+func (p *Process) gathererName() string {
+	return "extender:" + p.id
+}
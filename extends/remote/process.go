@@ -0,0 +1,249 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/eolinker/eosc/env"
+	grpc_unixsocket "github.com/eolinker/eosc/grpc-unixsocket"
+	"github.com/eolinker/eosc/log"
+	"github.com/eolinker/eosc/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	startTimeout   = time.Second * 10
+	requestTimeout = time.Second * 30
+	maxBackoff     = time.Second * 30
+	stableDuration = time.Minute
+)
+
+type workerState struct {
+	request *service.RemoteWorkerRequest
+	running bool
+}
+
+// Process 进程外插件的监管者，负责启动、连接以及在插件进程异常退出后重启并恢复driver与worker
+type Process struct {
+	id   string
+	path string
+	addr string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	locker    sync.RWMutex
+	cmd       *exec.Cmd
+	startAt   time.Time
+	exited    chan struct{}
+	conn      *grpc.ClientConn
+	client    service.RemoteExtenderClient
+	factories []*service.RemoteFactory
+	drivers   map[string]*service.RemoteDriverRequest
+	workers   map[string]*workerState
+	restarts  int
+}
+
+func newProcess(id string, path string) *Process {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Process{
+		id:      id,
+		path:    path,
+		addr:    env.SocketAddr(fmt.Sprint("extender-", strings.ReplaceAll(id, ":", "-")), os.Getpid()),
+		ctx:     ctx,
+		cancel:  cancel,
+		drivers: make(map[string]*service.RemoteDriverRequest),
+		workers: make(map[string]*workerState),
+	}
+}
+
+func (p *Process) start() error {
+	syscall.Unlink(p.addr)
+	cmd := exec.Command(p.path)
+	cmd.Env = append(os.Environ(), fmt.Sprint(EnvAddr, "=", p.addr))
+	// stdout 被 admin/worker 进程用于向 master 回写状态，插件输出统一写到 stderr
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	cmd.SysProcAttr = sysProcAttr()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start remote extender %s:%w", p.id, err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		err := cmd.Wait()
+		log.Warnf("remote extender %s[%d] exit:%v", p.id, cmd.Process.Pid, err)
+		close(exited)
+	}()
+
+	conn, err := grpc_unixsocket.Connect(p.addr)
+	if err != nil {
+		cmd.Process.Kill()
+		return err
+	}
+	client := service.NewRemoteExtenderClient(conn)
+	ctx, cancel := context.WithTimeout(p.ctx, startTimeout)
+	defer cancel()
+	response, err := client.Factories(ctx, &service.EmptyRequest{}, grpc.WaitForReady(true))
+	if err != nil {
+		conn.Close()
+		cmd.Process.Kill()
+		return fmt.Errorf("connect remote extender %s:%w", p.id, err)
+	}
+
+	p.locker.Lock()
+	p.cmd = cmd
+	p.startAt = time.Now()
+	p.exited = exited
+	p.conn = conn
+	p.client = client
+	p.factories = response.Factories
+	p.locker.Unlock()
+	log.Infof("remote extender %s[%d] started", p.id, cmd.Process.Pid)
+	return nil
+}
+
+// supervise 等待插件进程退出，按指数退避重启并恢复状态
+func (p *Process) supervise() {
+	backoff := time.Second
+	for {
+		p.locker.RLock()
+		exited := p.exited
+		startAt := p.startAt
+		p.locker.RUnlock()
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-exited:
+		}
+		p.disconnect()
+		if time.Since(startAt) > stableDuration {
+			backoff = time.Second
+		}
+		for {
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < maxBackoff {
+				backoff *= 2
+			}
+			if err := p.start(); err != nil {
+				log.Error("restart remote extender:", err)
+				continue
+			}
+			p.locker.Lock()
+			p.restarts++
+			p.locker.Unlock()
+			p.replay()
+			break
+		}
+	}
+}
+
+func (p *Process) disconnect() {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.conn = nil
+	p.client = nil
+}
+
+// replay 插件进程重启后重建已创建的driver与worker
+func (p *Process) replay() {
+	p.locker.RLock()
+	drivers := make([]*service.RemoteDriverRequest, 0, len(p.drivers))
+	for _, d := range p.drivers {
+		drivers = append(drivers, d)
+	}
+	workers := make([]*workerState, 0, len(p.workers))
+	for _, w := range p.workers {
+		workers = append(workers, &workerState{request: w.request, running: w.running})
+	}
+	p.locker.RUnlock()
+
+	for _, d := range drivers {
+		err := p.call(func(ctx context.Context, client service.RemoteExtenderClient) error {
+			_, err := client.CreateDriver(ctx, d)
+			return err
+		})
+		if err != nil {
+			log.Errorf("replay remote driver %s:%s of %s:%v", d.Profession, d.Name, p.id, err)
+		}
+	}
+	for _, w := range workers {
+		err := p.call(func(ctx context.Context, client service.RemoteExtenderClient) error {
+			if _, err := client.CreateWorker(ctx, w.request); err != nil {
+				return err
+			}
+			if w.running {
+				_, err := client.StartWorker(ctx, &service.RemoteWorkerId{Id: w.request.Id})
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			log.Errorf("replay remote worker %s of %s:%v", w.request.Id, p.id, err)
+		}
+	}
+}
+
+func (p *Process) call(f func(ctx context.Context, client service.RemoteExtenderClient) error) error {
+	p.locker.RLock()
+	client := p.client
+	p.locker.RUnlock()
+	if client == nil {
+		return fmt.Errorf("%s:%w", p.id, ErrorRemoteNotRunning)
+	}
+	ctx, cancel := context.WithTimeout(p.ctx, requestTimeout)
+	defer cancel()
+	err := f(ctx, client)
+	if err != nil {
+		if s, ok := status.FromError(err); ok {
+			return errors.New(s.Message())
+		}
+		return err
+	}
+	return nil
+}
+
+// Restarts 插件进程被重启的次数
+func (p *Process) Restarts() int {
+	p.locker.RLock()
+	defer p.locker.RUnlock()
+	return p.restarts
+}
+
+func (p *Process) Close() {
+	p.cancel()
+	p.locker.Lock()
+	cmd := p.cmd
+	exited := p.exited
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.conn = nil
+	p.client = nil
+	p.locker.Unlock()
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-exited:
+	case <-time.After(time.Second * 3):
+		cmd.Process.Kill()
+	}
+	syscall.Unlink(p.addr)
+}
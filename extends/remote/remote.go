@@ -0,0 +1,71 @@
+package remote
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/eolinker/eosc/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// ExecutableName 进程外插件在插件目录中的可执行文件名
+	ExecutableName = "extender"
+	// EnvAddr 启动进程外插件时传递 unix socket 地址的环境变量
+	EnvAddr = "EOSC_REMOTE_EXTENDER_ADDR"
+)
+
+var (
+	ErrorRemoteNotRunning = errors.New("remote extender not running")
+	ErrorRemoteNoAddr     = errors.New("remote extender address not set")
+	ErrorDriverNotExist   = errors.New("remote driver not exist")
+	ErrorFactoryNotExist  = errors.New("remote factory not exist")
+	ErrorWorkerNotExist   = errors.New("remote worker not exist")
+)
+
+var (
+	processLocker sync.Mutex
+	processes     = make(map[string]*Process)
+)
+
+// Lookup 判断插件目录中是否存在进程外插件
+func Lookup(dir string) (string, bool) {
+	path := filepath.Join(dir, ExecutableName)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+		return "", false
+	}
+	return path, true
+}
+
+// Open 启动进程外插件，同一路径的插件在当前进程内只会启动一次
+func Open(id string, path string) (*Process, error) {
+	processLocker.Lock()
+	defer processLocker.Unlock()
+	if p, has := processes[path]; has {
+		return p, nil
+	}
+	p := newProcess(id, path)
+	if err := p.start(); err != nil {
+		p.Close()
+		return nil, err
+	}
+	go p.supervise()
+	processes[path] = p
+	metrics.AddGatherer(p.gathererName(), metrics.WithLabels(p, prometheus.Labels{"extender": id}))
+	return p, nil
+}
+
+// CloseAll 关闭当前进程启动的所有进程外插件
+func CloseAll() {
+	processLocker.Lock()
+	ps := processes
+	processes = make(map[string]*Process)
+	processLocker.Unlock()
+	for _, p := range ps {
+		metrics.RemoveGatherer(p.gathererName())
+		p.Close()
+	}
+}
@@ -0,0 +1,280 @@
+package remote
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eolinker/eosc"
+	grpc_unixsocket "github.com/eolinker/eosc/grpc-unixsocket"
+	"github.com/eolinker/eosc/service"
+	"github.com/eolinker/eosc/utils/config"
+	"github.com/eolinker/eosc/variable"
+	"google.golang.org/grpc"
+)
+
+const testSkill = "test.skill"
+
+type testConfig struct {
+	Host   string         `json:"host"`
+	Target eosc.RequireId `json:"target" skill:"test.skill"`
+	Items  []struct {
+		Ref eosc.RequireId `json:"ref" skill:"test.skill" required:"false"`
+	} `json:"items"`
+}
+
+// testFactory 插件进程内的 factory，记录创建的 worker
+type testFactory struct {
+	workers map[string]*testWorker
+}
+
+func (f *testFactory) Render() interface{} {
+	return nil
+}
+
+func (f *testFactory) Create(profession string, name string, label string, desc string, params map[string]interface{}) (eosc.IExtenderDriver, error) {
+	return &testDriver{factory: f}, nil
+}
+
+type testDriver struct {
+	factory *testFactory
+}
+
+func (d *testDriver) ConfigType() reflect.Type {
+	return reflect.TypeOf((*testConfig)(nil))
+}
+
+func (d *testDriver) Create(id, name string, v interface{}, workers map[eosc.RequireId]eosc.IWorker) (eosc.IWorker, error) {
+	w := &testWorker{id: id, conf: v.(*testConfig), requires: workers}
+	d.factory.workers[id] = w
+	return w, nil
+}
+
+type testWorker struct {
+	id       string
+	conf     *testConfig
+	requires map[eosc.RequireId]eosc.IWorker
+	running  bool
+}
+
+func (w *testWorker) Id() string {
+	return w.id
+}
+
+func (w *testWorker) Start() error {
+	w.running = true
+	return nil
+}
+
+func (w *testWorker) Reset(conf interface{}, workers map[eosc.RequireId]eosc.IWorker) error {
+	w.conf, w.requires = conf.(*testConfig), workers
+	return nil
+}
+
+func (w *testWorker) Stop() error {
+	w.running = false
+	return nil
+}
+
+func (w *testWorker) CheckSkill(skill string) bool {
+	return skill == testSkill
+}
+
+type testBuilder struct {
+	factory *testFactory
+}
+
+func (b testBuilder) Register(register eosc.IExtenderDriverRegister) {
+	register.RegisterExtenderDriver("test", b.factory)
+}
+
+// hostWorkers admin/worker 进程内的 worker
+type hostWorkers map[string]eosc.IWorker
+
+func (h hostWorkers) Get(id string) (eosc.IWorker, bool) {
+	w, has := h[id]
+	return w, has
+}
+
+type hostRegister map[string]eosc.IExtenderDriverFactory
+
+func (h hostRegister) RegisterExtenderDriver(name string, factory eosc.IExtenderDriverFactory) error {
+	h[name] = factory
+	return nil
+}
+
+// newTestDriver 在当前进程内启动 Server，返回通过 grpc 连接的 driver
+func newTestDriver(t *testing.T) (*driver, *testFactory) {
+	t.Helper()
+	plugin := &testFactory{workers: make(map[string]*testWorker)}
+	addr := filepath.Join(t.TempDir(), "remote.sock")
+	l, err := grpc_unixsocket.Listener(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	service.RegisterRemoteExtenderServer(server, NewServer(testBuilder{factory: plugin}))
+	go server.Serve(l)
+	conn, err := grpc_unixsocket.Connect(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := newProcess("test:remote", "")
+	p.conn = conn
+	p.client = service.NewRemoteExtenderClient(conn)
+	t.Cleanup(func() {
+		p.cancel()
+		conn.Close()
+		server.Stop()
+	})
+	response, err := p.client.Factories(p.ctx, &service.EmptyRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.factories = response.Factories
+
+	register := make(hostRegister)
+	p.Register(register)
+	f, has := register["test"]
+	if !has {
+		t.Fatal("factory test not registered")
+	}
+	d, err := f.Create("router", "test", "", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d.(*driver), plugin
+}
+
+func parseConfig(t *testing.T, d *driver, data string) interface{} {
+	t.Helper()
+	variables := variable.NewVariables(map[string][]byte{"default": []byte(`{"host":"127.0.0.1"}`)})
+	conf, _, err := variable.NewParse(variables).Unmarshal([]byte(data), d.ConfigType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conf
+}
+
+func TestDriverRequires(t *testing.T) {
+	d, _ := newTestDriver(t)
+	workers := hostWorkers{
+		"up@service":   &testWorker{id: "up@service"},
+		"down@service": &testWorker{id: "down@service"},
+		"other@output": &otherWorker{testWorker{id: "other@output"}},
+	}
+	tests := []struct {
+		name     string
+		config   string
+		requires []string
+		err      error
+	}{
+		{
+			name:     "target",
+			config:   `{"target":"up@service"}`,
+			requires: []string{"up@service"},
+		},
+		{
+			name:     "nested",
+			config:   `{"target":"up@service","items":[{"ref":"down@service"},{"ref":""}]}`,
+			requires: []string{"down@service", "up@service"},
+		},
+		{
+			name:   "missing",
+			config: `{"host":"${host@default}"}`,
+			err:    eosc.ErrorRequire,
+		},
+		{
+			name:   "not exist",
+			config: `{"target":"none@service"}`,
+			err:    eosc.ErrorWorkerNotExits,
+		},
+		{
+			name:   "skill",
+			config: `{"target":"other@output"}`,
+			err:    eosc.ErrorTargetNotImplementSkill,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := parseConfig(t, d, tt.config)
+			requires, err := config.CheckDriverConfig(d, conf, workers)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("CheckDriverConfig() error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			got := make([]string, 0, len(requires))
+			for id := range requires {
+				got = append(got, string(id))
+			}
+			if ids := config.DriverRequires(d, conf); !reflect.DeepEqual(ids, tt.requires) {
+				t.Errorf("DriverRequires() = %v, want %v", ids, tt.requires)
+			}
+			if len(got) != len(tt.requires) {
+				t.Errorf("CheckDriverConfig() = %v, want %v", got, tt.requires)
+			}
+		})
+	}
+}
+
+func TestWorkerRoundTrip(t *testing.T) {
+	d, plugin := newTestDriver(t)
+	workers := hostWorkers{"up@service": &testWorker{id: "up@service"}}
+	conf := parseConfig(t, d, `{"host":"${host@default}","target":"up@service"}`)
+	requires, err := config.CheckDriverConfig(d, conf, workers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Check(conf, requires); err != nil {
+		t.Fatal("Check:", err)
+	}
+	w, err := d.Create("test@router", "test", conf, requires)
+	if err != nil {
+		t.Fatal("Create:", err)
+	}
+	remote, has := plugin.workers["test@router"]
+	if !has {
+		t.Fatal("worker not created in plugin")
+	}
+	if remote.conf.Host != "127.0.0.1" || remote.conf.Target != "up@service" {
+		t.Errorf("plugin config = %+v", remote.conf)
+	}
+	up, has := remote.requires["up@service"]
+	if !has {
+		t.Fatalf("plugin requires = %v", remote.requires)
+	}
+	if !up.CheckSkill(testSkill) || up.CheckSkill("other.skill") {
+		t.Error("foreign worker should only have the skills checked by the host")
+	}
+
+	if err := w.Start(); err != nil || !remote.running {
+		t.Fatal("Start:", err)
+	}
+	if !w.CheckSkill(testSkill) || w.CheckSkill("other.skill") {
+		t.Error("CheckSkill should ask the plugin worker")
+	}
+	conf = parseConfig(t, d, `{"host":"localhost","target":"up@service"}`)
+	if err := w.Reset(conf, requires); err != nil {
+		t.Fatal("Reset:", err)
+	}
+	if remote.conf.Host != "localhost" {
+		t.Errorf("plugin config after reset = %+v", remote.conf)
+	}
+	if err := w.Stop(); err != nil || remote.running {
+		t.Fatal("Stop:", err)
+	}
+	if w.CheckSkill(testSkill) {
+		t.Error("stopped worker should be removed from plugin")
+	}
+}
+
+type otherWorker struct {
+	testWorker
+}
+
+func (w *otherWorker) CheckSkill(skill string) bool {
+	return false
+}
@@ -0,0 +1,330 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/eolinker/eosc"
+	grpc_unixsocket "github.com/eolinker/eosc/grpc-unixsocket"
+	"github.com/eolinker/eosc/log"
+	"github.com/eolinker/eosc/metrics"
+	"github.com/eolinker/eosc/service"
+	"github.com/eolinker/eosc/utils/config"
+	"github.com/eolinker/eosc/variable"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+var _ service.RemoteExtenderServer = (*Server)(nil)
+var _ eosc.ICollectorRegister = (*Server)(nil)
+
+// Server 运行在进程外插件中，把插件注册的factory通过grpc提供给 admin/worker 进程
+type Server struct {
+	service.UnimplementedRemoteExtenderServer
+	locker    sync.RWMutex
+	factories map[string]eosc.IExtenderDriverFactory
+	names     []string
+	drivers   map[string]eosc.IExtenderDriver
+	workers   map[string]eosc.IWorker
+}
+
+func NewServer(builders ...eosc.ExtenderBuilder) *Server {
+	s := &Server{
+		factories: make(map[string]eosc.IExtenderDriverFactory),
+		drivers:   make(map[string]eosc.IExtenderDriver),
+		workers:   make(map[string]eosc.IWorker),
+	}
+	for _, b := range builders {
+		b.Register(s)
+	}
+	return s
+}
+
+// Serve 进程外插件的入口，在插件可执行文件的 main 中调用
+// 父进程退出或收到退出信号时返回
+func Serve(builders ...eosc.ExtenderBuilder) error {
+	addr := os.Getenv(EnvAddr)
+	if addr == "" {
+		return ErrorRemoteNoAddr
+	}
+	syscall.Unlink(addr)
+	l, err := grpc_unixsocket.Listener(addr)
+	if err != nil {
+		return err
+	}
+	log.Info("remote extender serve:", addr)
+	grpcServer := grpc.NewServer()
+	service.RegisterRemoteExtenderServer(grpcServer, NewServer(builders...))
+
+	go waitParent(grpcServer)
+	return grpcServer.Serve(l)
+}
+
+func waitParent(grpcServer *grpc.Server) {
+	ppid := os.Getppid()
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sigc:
+			grpcServer.GracefulStop()
+			return
+		case <-ticker.C:
+			if os.Getppid() != ppid {
+				grpcServer.Stop()
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) RegisterExtenderDriver(name string, factory eosc.IExtenderDriverFactory) error {
+	if _, has := s.factories[name]; has {
+		return fmt.Errorf("register remote factory %s:%w", name, eosc.ErrorRegisterConflict)
+	}
+	s.factories[name] = factory
+	s.names = append(s.names, name)
+	return nil
+}
+
+// RegisterCollector 注册到插件进程的指标中，admin/worker 进程通过 Metrics 拉取
+func (s *Server) RegisterCollector(collector prometheus.Collector) error {
+	return metrics.Register(collector)
+}
+
+func (s *Server) Metrics(ctx context.Context, request *service.EmptyRequest) (*service.RemoteMetricsResponse, error) {
+	mfs, err := metrics.Gatherer().Gather()
+	if err != nil {
+		log.Warn("gather remote extender metrics:", err)
+	}
+	data, err := metrics.Encode(mfs)
+	if err != nil {
+		return nil, err
+	}
+	return &service.RemoteMetricsResponse{Data: data}, nil
+}
+
+func (s *Server) Factories(ctx context.Context, request *service.EmptyRequest) (*service.RemoteFactoriesResponse, error) {
+	response := &service.RemoteFactoriesResponse{Factories: make([]*service.RemoteFactory, 0, len(s.names))}
+	for _, name := range s.names {
+		render, _ := json.Marshal(s.factories[name].Render())
+		response.Factories = append(response.Factories, &service.RemoteFactory{
+			Name:   name,
+			Render: render,
+		})
+	}
+	return response, nil
+}
+
+func (s *Server) CreateDriver(ctx context.Context, request *service.RemoteDriverRequest) (*service.RemoteDriverResponse, error) {
+	f, has := s.factories[request.Factory]
+	if !has {
+		return nil, fmt.Errorf("%s:%w", request.Factory, ErrorFactoryNotExist)
+	}
+	var params map[string]interface{}
+	if len(request.Params) > 0 {
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, err
+		}
+	}
+	d, err := f.Create(request.Profession, request.Name, request.Label, request.Desc, params)
+	if err != nil {
+		return nil, err
+	}
+	handle := fmt.Sprint(request.Factory, ":", request.Profession, ":", request.Name)
+	s.locker.Lock()
+	s.drivers[handle] = d
+	s.locker.Unlock()
+	return &service.RemoteDriverResponse{Driver: handle, Requires: toRemoteRequires(config.RequireFields(d.ConfigType()))}, nil
+}
+
+func toRemoteRequires(fields []config.RequireField) []*service.RemoteRequire {
+	requires := make([]*service.RemoteRequire, 0, len(fields))
+	for _, f := range fields {
+		requires = append(requires, &service.RemoteRequire{
+			Path:     f.Path,
+			Skill:    f.Skill,
+			Optional: f.Optional,
+			Multi:    f.Multi,
+		})
+	}
+	return requires
+}
+
+// decode 按driver的配置类型解析配置，并在插件进程内解析依赖
+func (s *Server) decode(request *service.RemoteWorkerRequest) (eosc.IExtenderDriver, interface{}, map[eosc.RequireId]eosc.IWorker, error) {
+	s.locker.RLock()
+	d, has := s.drivers[request.Driver]
+	s.locker.RUnlock()
+	if !has {
+		return nil, nil, nil, fmt.Errorf("%s:%w", request.Driver, ErrorDriverNotExist)
+	}
+	// 变量已在调用方完成替换
+	conf, _, err := variable.NewParse(variable.NewVariables(nil)).Unmarshal(request.Config, d.ConfigType())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	workers := newRequireWorkers(s, request.Skills)
+	requires, err := config.CheckConfig(conf, workers)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, id := range request.Requires {
+		if _, has := requires[eosc.RequireId(id)]; !has {
+			requires[eosc.RequireId(id)], _ = workers.Get(id)
+		}
+	}
+	return d, conf, requires, nil
+}
+
+func (s *Server) CheckConfig(ctx context.Context, request *service.RemoteWorkerRequest) (*service.EmptyRequest, error) {
+	d, conf, requires, err := s.decode(request)
+	if err != nil {
+		return nil, err
+	}
+	if dc, ok := d.(eosc.IExtenderConfigChecker); ok {
+		if err := dc.Check(conf, requires); err != nil {
+			return nil, err
+		}
+	}
+	return &service.EmptyRequest{}, nil
+}
+
+func (s *Server) CreateWorker(ctx context.Context, request *service.RemoteWorkerRequest) (*service.EmptyRequest, error) {
+	d, conf, requires, err := s.decode(request)
+	if err != nil {
+		return nil, err
+	}
+	w, err := d.Create(request.Id, request.Name, conf, requires)
+	if err != nil {
+		return nil, err
+	}
+	s.locker.Lock()
+	s.workers[request.Id] = w
+	s.locker.Unlock()
+	return &service.EmptyRequest{}, nil
+}
+
+func (s *Server) ResetWorker(ctx context.Context, request *service.RemoteWorkerRequest) (*service.EmptyRequest, error) {
+	w, err := s.worker(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	_, conf, requires, err := s.decode(request)
+	if err != nil {
+		return nil, err
+	}
+	return &service.EmptyRequest{}, w.Reset(conf, requires)
+}
+
+func (s *Server) StartWorker(ctx context.Context, request *service.RemoteWorkerId) (*service.EmptyRequest, error) {
+	w, err := s.worker(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &service.EmptyRequest{}, w.Start()
+}
+
+func (s *Server) StopWorker(ctx context.Context, request *service.RemoteWorkerId) (*service.EmptyRequest, error) {
+	s.locker.Lock()
+	w, has := s.workers[request.Id]
+	delete(s.workers, request.Id)
+	s.locker.Unlock()
+	if !has {
+		return &service.EmptyRequest{}, nil
+	}
+	return &service.EmptyRequest{}, w.Stop()
+}
+
+func (s *Server) DestroyWorker(ctx context.Context, request *service.RemoteWorkerId) (*service.EmptyRequest, error) {
+	s.locker.Lock()
+	w, has := s.workers[request.Id]
+	delete(s.workers, request.Id)
+	s.locker.Unlock()
+	if !has {
+		return &service.EmptyRequest{}, nil
+	}
+	if destroy, ok := w.(eosc.IWorkerDestroy); ok {
+		return &service.EmptyRequest{}, destroy.Destroy()
+	}
+	return &service.EmptyRequest{}, nil
+}
+
+func (s *Server) CheckSkill(ctx context.Context, request *service.RemoteSkillRequest) (*service.RemoteSkillResponse, error) {
+	w, err := s.worker(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &service.RemoteSkillResponse{Has: w.CheckSkill(request.Skill)}, nil
+}
+
+func (s *Server) worker(id string) (eosc.IWorker, error) {
+	s.locker.RLock()
+	defer s.locker.RUnlock()
+	w, has := s.workers[id]
+	if !has {
+		return nil, fmt.Errorf("%s:%w", id, ErrorWorkerNotExist)
+	}
+	return w, nil
+}
+
+// requireWorkers 插件进程内解析依赖时使用，其他插件的worker以 foreignWorker 代替
+type requireWorkers struct {
+	server *Server
+	skills map[string]map[string]bool
+}
+
+func newRequireWorkers(server *Server, skills []*service.RemoteSkillRequest) *requireWorkers {
+	r := &requireWorkers{server: server, skills: make(map[string]map[string]bool)}
+	for _, skill := range skills {
+		if r.skills[skill.Id] == nil {
+			r.skills[skill.Id] = make(map[string]bool)
+		}
+		r.skills[skill.Id][skill.Skill] = true
+	}
+	return r
+}
+
+func (r *requireWorkers) Get(id string) (eosc.IWorker, bool) {
+	r.server.locker.RLock()
+	w, has := r.server.workers[id]
+	r.server.locker.RUnlock()
+	if has {
+		return w, true
+	}
+	return &foreignWorker{id: id, skills: r.skills[id]}, true
+}
+
+// foreignWorker 不在当前插件进程内的worker，能力以调用方检查过的为准
+type foreignWorker struct {
+	id     string
+	skills map[string]bool
+}
+
+func (f *foreignWorker) Id() string {
+	return f.id
+}
+
+func (f *foreignWorker) Start() error {
+	return nil
+}
+
+func (f *foreignWorker) Reset(conf interface{}, workers map[eosc.RequireId]eosc.IWorker) error {
+	return nil
+}
+
+func (f *foreignWorker) Stop() error {
+	return nil
+}
+
+func (f *foreignWorker) CheckSkill(skill string) bool {
+	return f.skills[skill]
+}
@@ -0,0 +1,8 @@
+package remote
+
+import "syscall"
+
+// sysProcAttr 父进程退出时插件进程随之退出
+func sysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package remote
+
+import "syscall"
+
+func sysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{}
+}
@@ -0,0 +1,105 @@
+package health
+
+import (
+	"net/http"
+	"sync"
+)
+
+const (
+	PathLive  = "/system/health/live"
+	PathReady = "/system/health/ready"
+
+	StatusPass = "pass"
+	StatusFail = "fail"
+)
+
+// Check 单项检查的结果
+type Check struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Report 健康检查结果，任一检查失败时整体为 fail
+type Report struct {
+	Status string   `json:"status"`
+	Checks []*Check `json:"checks,omitempty"`
+}
+
+func (r *Report) Pass() bool {
+	return r.Status == StatusPass
+}
+
+// StatusCode 检查通过为 200，否则为 503
+func (r *Report) StatusCode() int {
+	if r.Pass() {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
+
+// Checker 返回 error 表示检查失败，message 为附带说明
+type Checker func() (message string, err error)
+
+// Registry 按注册顺序执行的检查项
+type Registry struct {
+	locker   sync.RWMutex
+	names    []string
+	checkers map[string]Checker
+}
+
+func NewRegistry() *Registry {
+	return &Registry{checkers: make(map[string]Checker)}
+}
+
+// Register 注册检查项，同名时替换
+func (r *Registry) Register(name string, checker Checker) {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	if _, has := r.checkers[name]; !has {
+		r.names = append(r.names, name)
+	}
+	r.checkers[name] = checker
+}
+
+func (r *Registry) Run() *Report {
+	r.locker.RLock()
+	names := make([]string, len(r.names))
+	copy(names, r.names)
+	checkers := make([]Checker, 0, len(names))
+	for _, name := range names {
+		checkers = append(checkers, r.checkers[name])
+	}
+	r.locker.RUnlock()
+
+	report := &Report{Status: StatusPass, Checks: make([]*Check, 0, len(names))}
+	for i, checker := range checkers {
+		c := &Check{Name: names[i], Status: StatusPass}
+		msg, err := checker()
+		c.Message = msg
+		if err != nil {
+			c.Status = StatusFail
+			c.Message = err.Error()
+			report.Status = StatusFail
+		}
+		report.Checks = append(report.Checks, c)
+	}
+	return report
+}
+
+// Live 进程能够响应即为存活
+func Live() *Report {
+	return &Report{Status: StatusPass}
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register 注册当前进程的就绪检查
+func Register(name string, checker Checker) {
+	defaultRegistry.Register(name, checker)
+}
+
+// Ready 执行当前进程注册的所有就绪检查
+func Ready() *Report {
+	return defaultRegistry.Run()
+}
@@ -0,0 +1,26 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.Register("a", func() (string, error) { return "ok", nil })
+	r.Register("b", func() (string, error) { return "", errors.New("down") })
+	report := r.Run()
+	if report.Pass() || report.StatusCode() != http.StatusServiceUnavailable {
+		t.Fatalf("want fail, got %s", report.Status)
+	}
+	if len(report.Checks) != 2 || report.Checks[0].Name != "a" || report.Checks[1].Message != "down" {
+		t.Fatalf("unexpected checks: %+v", report.Checks)
+	}
+
+	r.Register("b", func() (string, error) { return "", nil })
+	report = r.Run()
+	if !report.Pass() || len(report.Checks) != 2 {
+		t.Fatalf("want pass with 2 checks, got %s %d", report.Status, len(report.Checks))
+	}
+}
@@ -51,7 +51,9 @@ func (c *Complex) Transport(entry *Entry) error {
 
 	for _, t := range ts {
 		if t.Level() >= entry.Level {
-			t.Transport(entry)
+			if err := t.Transport(entry); err != nil {
+				Drop()
+			}
 		}
 	}
 	return nil
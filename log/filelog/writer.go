@@ -109,6 +109,7 @@ func (w *FileWriterByPeriod) Write(p []byte) (n int, err error) {
 
 	l := len(p)
 	if !w.enable {
+		log.Drop()
 		return l, nil
 	}
 
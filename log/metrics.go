@@ -0,0 +1,22 @@
+package log
+
+import (
+	"github.com/eolinker/eosc/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var droppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "log",
+	Name:      "dropped_total",
+	Help:      "Log entries dropped by transporters.",
+})
+
+func init() {
+	metrics.MustRegister(droppedTotal)
+}
+
+// Drop 记录一条未能输出的日志
+func Drop() {
+	droppedTotal.Inc()
+}
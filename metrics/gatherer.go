@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+const unixTimeout = time.Second * 3
+
+// WithLabels 为 g 返回的每个指标追加标签，用于区分不同进程的同名指标；指标已有同名标签时保留原值
+func WithLabels(g prometheus.Gatherer, labels prometheus.Labels) prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		mfs, err := g.Gather()
+		for _, mf := range mfs {
+			for _, m := range mf.Metric {
+				m.Label = appendLabels(m.Label, labels)
+			}
+		}
+		return mfs, err
+	})
+}
+
+func appendLabels(pairs []*dto.LabelPair, labels prometheus.Labels) []*dto.LabelPair {
+	has := make(map[string]bool, len(pairs))
+	for _, p := range pairs {
+		has[p.GetName()] = true
+	}
+	for name, value := range labels {
+		if has[name] {
+			continue
+		}
+		pairs = append(pairs, &dto.LabelPair{Name: proto.String(name), Value: proto.String(value)})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].GetName() < pairs[j].GetName()
+	})
+	return pairs
+}
+
+// Unix 通过 unix socket 拉取其他进程在 Path 上输出的指标
+func Unix(addr string) prometheus.Gatherer {
+	client := &http.Client{
+		Timeout: unixTimeout,
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return net.DialTimeout("unix", addr, unixTimeout)
+			},
+		},
+	}
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		resp, err := client.Get("http://unix" + Path)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("gather %s: status %d", addr, resp.StatusCode)
+		}
+		return Decode(resp.Body)
+	})
+}
+
+// Encode 将指标编码为 prometheus 文本格式
+func Encode(mfs []*dto.MetricFamily) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	for _, mf := range mfs {
+		if _, err := expfmt.MetricFamilyToText(buf, mf); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode 解析 prometheus 文本格式的指标，结果按名称排序
+func Decode(r io.Reader) ([]*dto.MetricFamily, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, err
+	}
+	mfs := make([]*dto.MetricFamily, 0, len(families))
+	for _, mf := range families {
+		mfs = append(mfs, mf)
+	}
+	sort.Slice(mfs, func(i, j int) bool {
+		return mfs[i].GetName() < mfs[j].GetName()
+	})
+	return mfs, nil
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// Path prometheus 指标的访问路径
+	Path = "/system/metrics"
+	// Namespace eosc 内置指标的前缀
+	Namespace = "eosc"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	gathererLocker sync.RWMutex
+	gatherers      = make(map[string]prometheus.Gatherer)
+)
+
+func init() {
+	registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+}
+
+// Registerer 当前进程的指标注册器
+func Registerer() prometheus.Registerer {
+	return registry
+}
+
+// With 返回为所有指标附加固定标签的注册器
+func With(labels prometheus.Labels) prometheus.Registerer {
+	return prometheus.WrapRegistererWith(labels, registry)
+}
+
+func Register(c prometheus.Collector) error {
+	return registry.Register(c)
+}
+
+func MustRegister(cs ...prometheus.Collector) {
+	registry.MustRegister(cs...)
+}
+
+func Unregister(c prometheus.Collector) bool {
+	return registry.Unregister(c)
+}
+
+// AddGatherer 添加额外的指标来源，例如进程外插件，同名时替换
+func AddGatherer(name string, g prometheus.Gatherer) {
+	gathererLocker.Lock()
+	defer gathererLocker.Unlock()
+	gatherers[name] = g
+}
+
+func RemoveGatherer(name string) {
+	gathererLocker.Lock()
+	defer gathererLocker.Unlock()
+	delete(gatherers, name)
+}
+
+// Gatherer 当前进程的全部指标，包括通过 AddGatherer 添加的来源
+func Gatherer() prometheus.Gatherer {
+	gathererLocker.RLock()
+	defer gathererLocker.RUnlock()
+	names := make([]string, 0, len(gatherers))
+	for name := range gatherers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	gs := prometheus.Gatherers{registry}
+	for _, name := range names {
+		gs = append(gs, gatherers[name])
+	}
+	return gs
+}
+
+// Handler 以 prometheus 文本格式输出 g 的指标，部分来源失败时仍输出其余指标
+func Handler(g prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
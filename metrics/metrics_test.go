@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestWithLabels(t *testing.T) {
+	r := prometheus.NewRegistry()
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_total", Help: "test"}, []string{"process"})
+	r.MustRegister(c)
+	c.WithLabelValues("admin").Add(2)
+
+	mfs, err := WithLabels(r, prometheus.Labels{"process": "master", "index": "0"}).Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Encode(mfs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || len(decoded[0].Metric) != 1 {
+		t.Fatalf("unexpected families: %s", data)
+	}
+	m := decoded[0].Metric[0]
+	labels := make(map[string]string)
+	for _, l := range m.Label {
+		labels[l.GetName()] = l.GetValue()
+	}
+	// 已有的标签保留原值
+	if labels["process"] != "admin" || labels["index"] != "0" || m.Counter.GetValue() != 2 {
+		t.Fatalf("unexpected metric: %s", data)
+	}
+}
@@ -6,6 +6,8 @@ const (
 	NamespaceExtender   = "extender"
 	NamespaceVariable   = "variable"
 	NamespaceCluster    = "cluster"
+	// NamespaceDefault 已自动创建过的单例默认 worker，删除后不再自动创建
+	NamespaceDefault = "default-worker"
 )
 
 var Namespaces = []string{
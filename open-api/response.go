@@ -6,6 +6,9 @@ import (
 	"net/http"
 )
 
+// PathProvisionDefault admin 进程创建缺失的单例默认 worker，master 在 admin 启动后调用
+const PathProvisionDefault = "/system/defaults"
+
 type Response struct {
 	StatusCode int              `json:"status"`
 	Header     http.Header      `json:"header"`
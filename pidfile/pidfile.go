@@ -76,7 +76,11 @@ func (p *PidFile) UnFork() error {
 	}
 	path := getPath()
 	if exist(path) {
-		return os.ErrExist
+		if processExistsByFile(path) {
+			return os.ErrExist
+		}
+		// 新进程启动失败遗留的 pid 文件
+		os.Remove(path)
 	}
 
 	e := os.Rename(old, path)
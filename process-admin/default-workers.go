@@ -0,0 +1,132 @@
+package process_admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/log"
+	open_api "github.com/eolinker/eosc/open-api"
+	"github.com/eolinker/eosc/professions"
+	"github.com/julienschmidt/httprouter"
+)
+
+var (
+	ErrorNotSingleton = errors.New("not singleton profession")
+	ErrorNoDefault    = errors.New("no default config")
+)
+
+// InitDefaults 加载已创建过的默认 worker，这些 worker 被删除后不再自动创建
+func (oe *Workers) InitDefaults(data map[string][]byte) {
+	oe.defaultLocker.Lock()
+	defer oe.defaultLocker.Unlock()
+	for id := range data {
+		oe.defaults[id] = true
+	}
+}
+
+// ProvisionDefault 为单例 profession 创建缺失的默认 worker，已创建过或 driver 不可用的跳过
+// 返回需要写入 raft 的 worker 及默认 worker 记录
+func (oe *Workers) ProvisionDefault(profession string) []*open_api.EventResponse {
+	p, has := oe.professions.Get(profession)
+	if !has || p.Mod != eosc.ProfessionConfig_Singleton {
+		return nil
+	}
+	oe.defaultLocker.Lock()
+	defer oe.defaultLocker.Unlock()
+	events := make([]*open_api.EventResponse, 0, len(p.Drivers))
+	for _, d := range p.Drivers {
+		if _, has := professions.DefaultBody(p.ProfessionConfig, d); !has {
+			continue
+		}
+		id, ok := eosc.ToWorkerId(d.Name, profession)
+		if !ok || oe.defaults[id] {
+			continue
+		}
+		if _, err := oe.GetEmployee(profession, d.Name); err == nil {
+			// 已存在的 worker 视为已创建，之后被删除时不再补全
+			oe.defaults[id] = true
+			events = append(events, defaultEvent(id))
+			continue
+		}
+		if _, has := p.GetDriver(d.Name); !has {
+			log.Warnf("provision default %s:driver %s not work", id, d.Id)
+			continue
+		}
+		w, err := oe.ResetDefault(profession, d.Name)
+		if err != nil {
+			log.Errorf("provision default %s:%v", id, err)
+			continue
+		}
+		oe.defaults[id] = true
+		events = append(events, workerSetEvents([]*WorkerInfo{w})...)
+		events = append(events, defaultEvent(id))
+	}
+	return events
+}
+
+// ProvisionAll 为所有单例 profession 创建缺失的默认 worker，admin 加载新的插件后由 master 调用
+func (oe *Workers) ProvisionAll() []*open_api.EventResponse {
+	events := make([]*open_api.EventResponse, 0)
+	for _, p := range oe.professions.List() {
+		events = append(events, oe.ProvisionDefault(p.Name)...)
+	}
+	return events
+}
+
+func defaultEvent(id string) *open_api.EventResponse {
+	return &open_api.EventResponse{
+		Event:     eosc.EventSet,
+		Namespace: eosc.NamespaceDefault,
+		Key:       id,
+		Data:      []byte(eosc.Now()),
+	}
+}
+
+// ResetDefault 将单例 worker 恢复为默认配置
+func (oe *Workers) ResetDefault(profession, name string) (*WorkerInfo, error) {
+	p, has := oe.professions.Get(profession)
+	if !has {
+		return nil, fmt.Errorf("%s:%w", profession, eosc.ErrorProfessionNotExist)
+	}
+	if p.Mod != eosc.ProfessionConfig_Singleton {
+		return nil, fmt.Errorf("%s:%w", profession, ErrorNotSingleton)
+	}
+	d, has := p.DriverConfig(name)
+	if !has {
+		return nil, fmt.Errorf("%s,%w", name, eosc.ErrorDriverNotExist)
+	}
+	body, has := professions.DefaultBody(p.ProfessionConfig, d)
+	if !has {
+		return nil, fmt.Errorf("%s@%s:%w", name, profession, ErrorNoDefault)
+	}
+	return oe.Update(profession, name, name, "", JsonData(body))
+}
+
+// Provision 创建缺失的默认 worker
+func (oe *WorkerApi) Provision(r *http.Request, params httprouter.Params) (status int, header http.Header, events []*open_api.EventResponse, body interface{}) {
+	events = oe.workers.ProvisionAll()
+	keys := make([]string, 0, len(events))
+	for _, e := range events {
+		if e.Namespace == eosc.NamespaceWorker {
+			keys = append(keys, e.Key)
+		}
+	}
+	return http.StatusOK, nil, events, keys
+}
+
+func workerSetEvents(ws []*WorkerInfo) []*open_api.EventResponse {
+	events := make([]*open_api.EventResponse, 0, len(ws))
+	for _, w := range ws {
+		data, _ := json.Marshal(w.config)
+		events = append(events, &open_api.EventResponse{
+			Event:     eosc.EventSet,
+			Namespace: eosc.NamespaceWorker,
+			Key:       w.config.Id,
+			Data:      data,
+		})
+	}
+	return events
+}
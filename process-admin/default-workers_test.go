@@ -0,0 +1,60 @@
+package process_admin
+
+import (
+	"testing"
+
+	"github.com/eolinker/eosc"
+	open_api "github.com/eolinker/eosc/open-api"
+	"github.com/eolinker/eosc/professions"
+	"github.com/eolinker/eosc/variable"
+)
+
+func eventKeys(events []*open_api.EventResponse) map[string]string {
+	keys := make(map[string]string)
+	for _, e := range events {
+		keys[e.Namespace+"/"+e.Key] = e.Event
+	}
+	return keys
+}
+
+func TestProvisionDefault(t *testing.T) {
+	ps := professions.NewProfessions(&testDriver{})
+	if err := ps.Reset([]*eosc.ProfessionConfig{{
+		Name:    "output",
+		Mod:     eosc.ProfessionConfig_Singleton,
+		Default: `{}`,
+		Drivers: []*eosc.DriverConfig{
+			{Id: "test:file", Name: "file"},
+			{Id: "test:http", Name: "http"},
+			{Id: "test:kafka", Name: "kafka"},
+		},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	ws := NewWorkers()
+	ws.InitDefaults(map[string][]byte{"kafka@output": []byte("")})
+	ws.Init(ps, NewWorkerDatas(nil), variable.NewVariables(nil))
+
+	keys := eventKeys(ws.ProvisionAll())
+	for _, key := range []string{"worker/file@output", "worker/http@output", "default-worker/file@output", "default-worker/http@output"} {
+		if keys[key] != eosc.EventSet {
+			t.Errorf("ProvisionAll() missing %s: %v", key, keys)
+		}
+	}
+	if _, has := keys["worker/kafka@output"]; has {
+		t.Error("worker provisioned before should not be created again")
+	}
+	if len(keys) != 4 {
+		t.Errorf("ProvisionAll() = %v", keys)
+	}
+
+	if _, err := ws.Delete("file@output"); err != nil {
+		t.Fatal(err)
+	}
+	if events := ws.ProvisionAll(); len(events) != 0 {
+		t.Errorf("deleted default worker should not be provisioned again: %v", eventKeys(events))
+	}
+	if _, err := ws.ResetDefault("output", "file"); err != nil {
+		t.Error("ResetDefault():", err)
+	}
+}
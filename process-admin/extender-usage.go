@@ -0,0 +1,65 @@
+package process_admin
+
+import (
+	"sort"
+
+	"github.com/eolinker/eosc/extends"
+	"github.com/eolinker/eosc/professions"
+)
+
+type ExtenderUsageDriver struct {
+	Profession string `json:"profession"`
+	Driver     string `json:"driver"`
+	Id         string `json:"id"`
+}
+
+type ExtenderUsage struct {
+	Id          string                 `json:"id"`
+	Professions []string               `json:"professions"`
+	Drivers     []*ExtenderUsageDriver `json:"drivers"`
+	Workers     []string               `json:"workers"`
+}
+
+// InUse 是否还有由该插件driver创建的worker
+func (u *ExtenderUsage) InUse() bool {
+	return len(u.Workers) > 0
+}
+
+// extenderUsage 统计依赖指定插件的 profession、driver 及 worker
+func extenderUsage(group, project string, ps professions.IProfessions, workers *WorkerDatas) *ExtenderUsage {
+	usage := &ExtenderUsage{
+		Id:          toProject(group, project),
+		Professions: make([]string, 0),
+		Drivers:     make([]*ExtenderUsageDriver, 0),
+		Workers:     make([]string, 0),
+	}
+
+	drivers := make(map[string]map[string]bool)
+	for _, p := range ps.List() {
+		for _, d := range p.Drivers {
+			g, pj, _, err := extends.DecodeExtenderId(d.Id)
+			if err != nil || g != group || pj != project {
+				continue
+			}
+			if _, has := drivers[p.Name]; !has {
+				drivers[p.Name] = make(map[string]bool)
+				usage.Professions = append(usage.Professions, p.Name)
+			}
+			drivers[p.Name][d.Name] = true
+			usage.Drivers = append(usage.Drivers, &ExtenderUsageDriver{
+				Profession: p.Name,
+				Driver:     d.Name,
+				Id:         d.Id,
+			})
+		}
+	}
+
+	for id, w := range workers.All() {
+		if drivers[w.config.Profession][w.config.Driver] {
+			usage.Workers = append(usage.Workers, id)
+		}
+	}
+	sort.Strings(usage.Professions)
+	sort.Strings(usage.Workers)
+	return usage
+}
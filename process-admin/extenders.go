@@ -21,6 +21,7 @@ var (
 	ErrorDuplicatePath           = errors.New("path duplicate")
 	ErrorNotMatch                = errors.New("not match profession")
 	ErrorExtenderVersionIsChange = errors.New("the version of extender has changed")
+	ErrorExtenderInUse           = errors.New("in use")
 )
 
 type ExtenderProject struct {
@@ -0,0 +1,70 @@
+package process_admin
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eolinker/eosc/metrics"
+	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const routeNotFound = "not_found"
+
+var apiDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "admin",
+	Name:      "request_duration_seconds",
+	Help:      "Admin open api latency by route and status code.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "route", "code"})
+
+func init() {
+	metrics.MustRegister(apiDuration)
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// observe 按路由模板记录请求耗时，避免以实际路径作为标签
+func observe(router *httprouter.Router, w http.ResponseWriter, r *http.Request, serve func(w http.ResponseWriter)) {
+	start := time.Now()
+	sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+	serve(sw)
+	apiDuration.WithLabelValues(r.Method, route(router, r), strconv.Itoa(sw.status)).Observe(time.Since(start).Seconds())
+}
+
+// route 由匹配到的参数还原路由模板，例如 /api/router/demo 还原为 /api/:profession/:name
+func route(router *httprouter.Router, r *http.Request) string {
+	path := r.URL.Path
+	handle, params, _ := router.Lookup(r.Method, path)
+	if handle == nil {
+		return routeNotFound
+	}
+	segments := strings.Split(path, "/")
+	next := 0
+	for _, p := range params {
+		if strings.HasPrefix(p.Value, "/") {
+			// 通配参数匹配剩余的全部路径
+			n := len(strings.Split(strings.TrimSuffix(path, p.Value), "/"))
+			return strings.Join(segments[:n], "/") + "/*" + p.Key
+		}
+		for i := next; i < len(segments); i++ {
+			if segments[i] == p.Value {
+				segments[i] = ":" + p.Key
+				next = i + 1
+				break
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
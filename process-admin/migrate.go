@@ -0,0 +1,92 @@
+package process_admin
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/eolinker/eosc"
+)
+
+// WorkerMigration worker 配置的版本迁移记录
+type WorkerMigration struct {
+	Id         string `json:"id"`
+	Profession string `json:"profession"`
+	Name       string `json:"name"`
+	Driver     string `json:"driver"`
+	From       int32  `json:"from"`
+	To         int32  `json:"to"`
+	Changed    bool   `json:"changed"`
+	Error      string `json:"error,omitempty"`
+	// body 升级后的配置
+	body []byte
+}
+
+// migrate 将保存的配置升级为 driver 当前版本，无需升级时 migration 为 nil
+func (oe *Workers) migrate(wc *eosc.WorkerConfig) ([]byte, *WorkerMigration) {
+	p, has := oe.professions.Get(wc.Profession)
+	if !has {
+		return wc.Body, nil
+	}
+	driverName := wc.Driver
+	if p.Mod == eosc.ProfessionConfig_Singleton {
+		driverName = wc.Name
+	}
+	driver, has := p.GetDriver(driverName)
+	if !has {
+		return wc.Body, nil
+	}
+	to := int32(eosc.SchemaVersion(driver))
+	if wc.Schema >= to {
+		return wc.Body, nil
+	}
+	migration := &WorkerMigration{
+		Id:         wc.Id,
+		Profession: wc.Profession,
+		Name:       wc.Name,
+		Driver:     wc.Driver,
+		From:       wc.Schema,
+		To:         to,
+	}
+	body, _, err := eosc.MigrateConfig(driver, int(wc.Schema), wc.Body)
+	if err != nil {
+		migration.Error = err.Error()
+		return wc.Body, migration
+	}
+	migration.Changed = !jsonEqual(wc.Body, body)
+	migration.body = body
+	return body, migration
+}
+
+// checkMigrations 检查所有 worker 保存的配置版本，启动之后 profession 切换 driver 或新增的旧版本配置也会被发现
+func (oe *Workers) checkMigrations() {
+	for _, info := range oe.data.List() {
+		if _, has := oe.migrations[info.config.Id]; has {
+			continue
+		}
+		if _, migration := oe.migrate(info.config); migration != nil {
+			oe.migrations[info.config.Id] = migration
+		}
+	}
+}
+
+// Migrations 返回需要升级、但尚未写回的 worker
+func (oe *Workers) Migrations() []*WorkerMigration {
+	oe.checkMigrations()
+	rs := make([]*WorkerMigration, 0, len(oe.migrations))
+	for _, m := range oe.migrations {
+		rs = append(rs, m)
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].Id < rs[j].Id
+	})
+	return rs
+}
+
+func jsonEqual(a, b []byte) bool {
+	var av, bv interface{}
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return string(a) == string(b)
+	}
+	return reflect.DeepEqual(av, bv)
+}
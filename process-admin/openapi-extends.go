@@ -6,18 +6,22 @@ import (
 	"github.com/eolinker/eosc"
 	"github.com/eolinker/eosc/log"
 	open_api "github.com/eolinker/eosc/open-api"
+	"github.com/eolinker/eosc/professions"
 	"github.com/julienschmidt/httprouter"
 	"io/ioutil"
 	"mime"
 	"net/http"
+	"strings"
 )
 
 type ExtenderOpenApi struct {
-	extenders *ExtenderData
+	extenders   *ExtenderData
+	professions professions.IProfessions
+	workers     *WorkerDatas
 }
 
-func NewExtenderOpenApi(extenders *ExtenderData) *ExtenderOpenApi {
-	return &ExtenderOpenApi{extenders: extenders}
+func NewExtenderOpenApi(extenders *ExtenderData, professions professions.IProfessions, workers *WorkerDatas) *ExtenderOpenApi {
+	return &ExtenderOpenApi{extenders: extenders, professions: professions, workers: workers}
 }
 func (oe *ExtenderOpenApi) Register(router *httprouter.Router) {
 
@@ -30,11 +34,27 @@ func (oe *ExtenderOpenApi) Register(router *httprouter.Router) {
 
 }
 
+// RegisterPrior httprouter 不允许 /extender/:id/usage 与 /extender/:id/:name 同时注册，固定路由注册到先匹配的 router
+func (oe *ExtenderOpenApi) RegisterPrior(router *httprouter.Router) {
+	router.Handle(http.MethodGet, "/extender/:id/usage", open_api.CreateHandleFunc(oe.Usage))
+}
+
 func (oe *ExtenderOpenApi) Delete(r *http.Request, params httprouter.Params) (status int, header http.Header, events []*open_api.EventResponse, body interface{}) {
 	id := params.ByName("id")
 	group, project := readProject(id)
 	version := r.URL.Query().Get("v")
 
+	usage := extenderUsage(group, project, oe.professions, oe.workers)
+	if r.URL.Query().Get("force") == "plan" {
+		return http.StatusOK, nil, nil, &ExtenderDeletePlan{
+			ExtenderUsage: usage,
+			Deletable:     !usage.InUse(),
+		}
+	}
+	if usage.InUse() {
+		return http.StatusConflict, nil, nil, fmt.Sprintf("extender{%s} %v by workers: %s", id, ErrorExtenderInUse, strings.Join(usage.Workers, ","))
+	}
+
 	projectInfo, err := oe.extenders.Delete(group, project, version)
 	if err != nil {
 		return 0, nil, nil, err.Error()
@@ -48,6 +68,21 @@ func (oe *ExtenderOpenApi) Delete(r *http.Request, params httprouter.Params) (st
 	}}, projectInfo.toInfo()
 
 }
+
+type ExtenderDeletePlan struct {
+	*ExtenderUsage
+	Deletable bool `json:"deletable"`
+}
+
+func (oe *ExtenderOpenApi) Usage(r *http.Request, params httprouter.Params) (status int, header http.Header, events []*open_api.EventResponse, body interface{}) {
+	id := params.ByName("id")
+	group, project := readProject(id)
+	if _, has := oe.extenders.getVersion(group, project); !has {
+		return http.StatusNotFound, nil, nil, fmt.Sprintf("extender{%s} not install", id)
+	}
+	return http.StatusOK, nil, nil, extenderUsage(group, project, oe.professions, oe.workers)
+}
+
 func (oe *ExtenderOpenApi) SET(r *http.Request, params httprouter.Params) (status int, header http.Header, events []*open_api.EventResponse, body interface{}) {
 	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
 	log.Debug("Content-Type:", r.Header.Get("Content-Type"))
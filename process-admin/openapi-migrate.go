@@ -0,0 +1,63 @@
+package process_admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/eolinker/eosc"
+	open_api "github.com/eolinker/eosc/open-api"
+	"github.com/julienschmidt/httprouter"
+)
+
+type MigrateApi struct {
+	workers *Workers
+}
+
+func NewMigrateApi(workers *Workers) *MigrateApi {
+	return &MigrateApi{workers: workers}
+}
+
+func (ma *MigrateApi) Register(router *httprouter.Router) {
+	router.GET("/migrate", open_api.CreateHandleFunc(ma.Report))
+	router.POST("/migrate", open_api.CreateHandleFunc(ma.Apply))
+}
+
+// Report 插件升级后需要迁移配置的 worker
+func (ma *MigrateApi) Report(r *http.Request, params httprouter.Params) (status int, header http.Header, events []*open_api.EventResponse, body interface{}) {
+	return http.StatusOK, nil, nil, ma.workers.Migrations()
+}
+
+// Apply 将升级后的配置写回，dry=true 时只返回将会改变的 worker
+func (ma *MigrateApi) Apply(r *http.Request, params httprouter.Params) (status int, header http.Header, events []*open_api.EventResponse, body interface{}) {
+	migrations := ma.workers.Migrations()
+	if r.URL.Query().Get("dry") == "true" {
+		return http.StatusOK, nil, nil, migrations
+	}
+	applied := make([]*WorkerMigration, 0, len(migrations))
+	for _, m := range migrations {
+		if m.Error != "" {
+			continue
+		}
+		info, has := ma.workers.data.GetInfo(m.Id)
+		if !has {
+			delete(ma.workers.migrations, m.Id)
+			continue
+		}
+		// 重新应用升级后的配置，同时把版本更新为 driver 当前版本
+		info, err := ma.workers.set(m.Id, info.config.Profession, info.config.Name, info.config.Driver, info.config.Description, m.body)
+		if err != nil {
+			m.Error = err.Error()
+			continue
+		}
+		data, _ := json.Marshal(info.config)
+		events = append(events, &open_api.EventResponse{
+			Event:     eosc.EventSet,
+			Namespace: eosc.NamespaceWorker,
+			Key:       m.Id,
+			Data:      data,
+		})
+		applied = append(applied, m)
+		delete(ma.workers.migrations, m.Id)
+	}
+	return http.StatusOK, nil, events, applied
+}
@@ -0,0 +1,107 @@
+package process_admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/professions"
+	"github.com/eolinker/eosc/variable"
+)
+
+// migrateDriver 版本 1 起把配置中的 to 改名为 target，版本号可以在运行中修改
+type migrateDriver struct {
+	testExtenderDriver
+	version *int
+}
+
+func (d migrateDriver) SchemaVersion() int {
+	return *d.version
+}
+
+func (d migrateDriver) Migrate(fromVersion int, body []byte) ([]byte, error) {
+	if bytes.Contains(body, []byte("bad")) {
+		return nil, errors.New("bad config")
+	}
+	return bytes.ReplaceAll(body, []byte(`"to"`), []byte(`"target"`)), nil
+}
+
+type migrateFactory struct {
+	*testDriver
+	version *int
+}
+
+func (f migrateFactory) Create(profession string, name string, label string, desc string, params map[string]interface{}) (eosc.IExtenderDriver, error) {
+	return migrateDriver{testExtenderDriver: testExtenderDriver{f.testDriver}, version: f.version}, nil
+}
+
+func (f migrateFactory) GetDriver(name string) (eosc.IExtenderDriverFactory, bool) {
+	return f, true
+}
+
+func migrationIds(v interface{}) map[string]string {
+	ids := make(map[string]string)
+	for _, m := range v.([]*WorkerMigration) {
+		ids[m.Id] = m.Error
+	}
+	return ids
+}
+
+func TestMigrateApi(t *testing.T) {
+	version := 1
+	ps := professions.NewProfessions(migrateFactory{testDriver: &testDriver{}, version: &version})
+	if err := ps.Reset([]*eosc.ProfessionConfig{{
+		Name:    "service",
+		Drivers: []*eosc.DriverConfig{{Id: "test:test", Name: "test"}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	initData := make(map[string][]byte)
+	for _, wc := range []*eosc.WorkerConfig{
+		{Name: "a", Schema: 0, Body: []byte(`{"to":"c@service"}`)},
+		{Name: "b", Schema: 0, Body: []byte(`{"to":"bad"}`)},
+		{Name: "c", Schema: 1, Body: []byte(`{}`)},
+	} {
+		wc.Id, wc.Profession, wc.Driver = wc.Name+"@service", "service", "test"
+		initData[wc.Id], _ = json.Marshal(wc)
+	}
+	ws := NewWorkers()
+	ws.Init(ps, NewWorkerDatas(initData), variable.NewVariables(nil))
+	api := NewMigrateApi(ws)
+
+	_, _, _, body := api.Report(httptest.NewRequest("GET", "/migrate", nil), nil)
+	if got := migrationIds(body); len(got) != 2 || got["a@service"] != "" || got["b@service"] == "" {
+		t.Fatalf("Report() = %v", got)
+	}
+	_, _, events, _ := api.Apply(httptest.NewRequest("POST", "/migrate?dry=true", nil), nil)
+	if len(events) != 0 {
+		t.Errorf("dry Apply() events = %v", events)
+	}
+
+	_, _, events, body = api.Apply(httptest.NewRequest("POST", "/migrate", nil), nil)
+	if got := migrationIds(body); !reflect.DeepEqual(got, map[string]string{"a@service": ""}) {
+		t.Errorf("Apply() = %v", got)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Apply() events = %v", events)
+	}
+	saved := new(eosc.WorkerConfig)
+	json.Unmarshal(events[0].Data, saved)
+	if saved.Schema != 1 || string(saved.Body) != `{"target":"c@service"}` {
+		t.Errorf("saved %s schema %d", saved.Body, saved.Schema)
+	}
+	if requires := ws.requireManager.Requires("a@service"); len(requires) != 1 {
+		t.Errorf("requires of a@service = %v", requires)
+	}
+
+	// 启动之后 driver 升级，已保存的配置也需要迁移
+	version = 2
+	_, _, _, body = api.Report(httptest.NewRequest("GET", "/migrate", nil), nil)
+	if got := migrationIds(body); len(got) != 3 || got["a@service"] != "" || got["c@service"] != "" {
+		t.Errorf("Report() after driver upgrade = %v", got)
+	}
+}
@@ -2,6 +2,7 @@ package process_admin
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -17,10 +18,11 @@ import (
 type ProfessionApi struct {
 	data       professions.IProfessions
 	workerData *WorkerDatas
+	workers    *Workers
 }
 
-func NewProfessionApi(data professions.IProfessions, ws *WorkerDatas) *ProfessionApi {
-	return &ProfessionApi{data: data, workerData: ws}
+func NewProfessionApi(data professions.IProfessions, ws *WorkerDatas, workers *Workers) *ProfessionApi {
+	return &ProfessionApi{data: data, workerData: ws, workers: workers}
 }
 
 func (pi *ProfessionApi) Register(router *httprouter.Router) {
@@ -38,6 +40,11 @@ func (pi *ProfessionApi) Register(router *httprouter.Router) {
 
 }
 
+// RegisterPrior /profession/graph 与 /profession/:profession 冲突，注册到先匹配的 router
+func (pi *ProfessionApi) RegisterPrior(router *httprouter.Router) {
+	router.GET("/profession/graph", open_api.CreateHandleFunc(pi.Graph))
+}
+
 type ProfessionInfo struct {
 	Name   string   `json:"name,omitempty"`
 	Label  string   `json:"label,omitempty"`
@@ -185,15 +192,17 @@ func (pi *ProfessionApi) SetDriver(r *http.Request, params httprouter.Params) (s
 
 	err = pi.data.Set(name, pConfig)
 	if err != nil {
-		return http.StatusInternalServerError, nil, nil, err
+		return professionErrorStatus(err), nil, nil, err
 	}
 	data, _ := json.Marshal(pConfig)
-	return http.StatusOK, nil, []*open_api.EventResponse{{
+	events = []*open_api.EventResponse{{
 		Event:     eosc.EventSet,
 		Namespace: eosc.NamespaceProfession,
 		Key:       name,
 		Data:      data,
-	}}, data
+	}}
+	events = append(events, pi.workers.ProvisionDefault(name)...)
+	return http.StatusOK, nil, events, data
 
 }
 func (pi *ProfessionApi) AddDriver(r *http.Request, params httprouter.Params) (status int, header http.Header, events []*open_api.EventResponse, body interface{}) {
@@ -221,15 +230,17 @@ func (pi *ProfessionApi) AddDriver(r *http.Request, params httprouter.Params) (s
 
 	err = pi.data.Set(name, pConfig)
 	if err != nil {
-		return http.StatusInternalServerError, nil, nil, err
+		return professionErrorStatus(err), nil, nil, err
 	}
 	data, _ := json.Marshal(pConfig)
-	return http.StatusOK, nil, []*open_api.EventResponse{{
+	events = []*open_api.EventResponse{{
 		Event:     eosc.EventSet,
 		Namespace: eosc.NamespaceProfession,
 		Key:       name,
 		Data:      data,
-	}}, data
+	}}
+	events = append(events, pi.workers.ProvisionDefault(name)...)
+	return http.StatusOK, nil, events, data
 
 }
 func (pi *ProfessionApi) ResetDrivers(r *http.Request, params httprouter.Params) (status int, header http.Header, events []*open_api.EventResponse, body interface{}) {
@@ -252,15 +263,17 @@ func (pi *ProfessionApi) ResetDrivers(r *http.Request, params httprouter.Params)
 	pConfig.Drivers = driverConfigs
 	err = pi.data.Set(name, pConfig)
 	if err != nil {
-		return http.StatusInternalServerError, nil, nil, err
+		return professionErrorStatus(err), nil, nil, err
 	}
 	data, _ := json.Marshal(pConfig)
-	return http.StatusOK, nil, []*open_api.EventResponse{{
+	events = []*open_api.EventResponse{{
 		Event:     eosc.EventSet,
 		Namespace: eosc.NamespaceProfession,
 		Key:       name,
 		Data:      data,
-	}}, data
+	}}
+	events = append(events, pi.workers.ProvisionDefault(name)...)
+	return http.StatusOK, nil, events, data
 
 }
 func (pi *ProfessionApi) Delete(r *http.Request, params httprouter.Params) (status int, header http.Header, events []*open_api.EventResponse, body interface{}) {
@@ -288,7 +301,7 @@ func (pi *ProfessionApi) Delete(r *http.Request, params httprouter.Params) (stat
 	}
 	err := pi.data.Set(name, pConfig)
 	if err != nil {
-		return http.StatusInternalServerError, nil, nil, err
+		return professionErrorStatus(err), nil, nil, err
 	}
 	data, _ := json.Marshal(pConfig)
 	return http.StatusOK, nil, []*open_api.EventResponse{{
@@ -298,3 +311,63 @@ func (pi *ProfessionApi) Delete(r *http.Request, params httprouter.Params) (stat
 		Data:      data,
 	}}, data
 }
+
+type ProfessionGraphNode struct {
+	Name         string   `json:"name"`
+	Mod          string   `json:"mod"`
+	Dependencies []string `json:"dependencies"`
+	Dependents   []string `json:"dependents"`
+	// Error 依赖无法解析时 profession 不可用的原因
+	Error string `json:"error,omitempty"`
+}
+
+type ProfessionGraph struct {
+	Order []string               `json:"order"`
+	Nodes []*ProfessionGraphNode `json:"nodes"`
+	Error string                 `json:"error,omitempty"`
+}
+
+// Graph 返回 profession 的依赖关系及初始化顺序
+func (pi *ProfessionApi) Graph(r *http.Request, params httprouter.Params) (status int, header http.Header, events []*open_api.EventResponse, body interface{}) {
+	list := pi.data.List()
+	configs := make([]*eosc.ProfessionConfig, 0, len(list))
+	for _, p := range list {
+		configs = append(configs, p.ProfessionConfig)
+	}
+	graph := &ProfessionGraph{
+		Order: make([]string, 0, len(configs)),
+		Nodes: make([]*ProfessionGraphNode, 0, len(configs)),
+	}
+	sorted, err := professions.Resolve(configs)
+	if err != nil {
+		graph.Error = err.Error()
+		sorted = configs
+	}
+	for _, c := range sorted {
+		if err == nil {
+			graph.Order = append(graph.Order, c.Name)
+		}
+		dependencies := c.Dependencies
+		if dependencies == nil {
+			dependencies = make([]string, 0)
+		}
+		node := &ProfessionGraphNode{
+			Name:         c.Name,
+			Mod:          c.Mod.String(),
+			Dependencies: dependencies,
+			Dependents:   professions.Dependents(c.Name, configs),
+		}
+		if e := pi.data.Unusable(c.Name); e != nil {
+			node.Error = e.Error()
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	return http.StatusOK, nil, nil, graph
+}
+
+func professionErrorStatus(err error) int {
+	if errors.Is(err, eosc.ErrorProfessionDependencies) || errors.Is(err, eosc.ErrorProfessionDependencyCycle) || errors.Is(err, eosc.ErrorProfessionDependent) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -2,6 +2,7 @@ package process_admin
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/eolinker/eosc"
 	"github.com/eolinker/eosc/log"
@@ -182,3 +183,20 @@ func (oe *WorkerApi) Delete(r *http.Request, params httprouter.Params) (status i
 		Data:      nil,
 	}}, wInfo.Detail()
 }
+
+// ResetDefault 将单例 worker 恢复为 profession 中声明的默认配置
+func (oe *WorkerApi) ResetDefault(r *http.Request, params httprouter.Params) (status int, header http.Header, events []*open_api.EventResponse, body interface{}) {
+	profession := params.ByName("profession")
+	name := params.ByName("name")
+	obj, err := oe.workers.ResetDefault(profession, name)
+	if err != nil {
+		if errors.Is(err, ErrorNoDefault) || errors.Is(err, ErrorNotSingleton) {
+			return http.StatusBadRequest, nil, nil, err
+		}
+		if errors.Is(err, eosc.ErrorProfessionNotExist) || errors.Is(err, eosc.ErrorDriverNotExist) {
+			return http.StatusNotFound, nil, nil, err
+		}
+		return http.StatusInternalServerError, nil, nil, err
+	}
+	return http.StatusOK, nil, workerSetEvents([]*WorkerInfo{obj}), obj.Detail()
+}
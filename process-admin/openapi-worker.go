@@ -20,6 +20,8 @@ func (oe *WorkerApi) Register(router *httprouter.Router) {
 	router.POST("/api/:profession/:name", open_api.CreateHandleFunc(oe.Save))
 	router.DELETE("/api/:profession/:name", open_api.CreateHandleFunc(oe.Delete))
 	router.PATCH("/api/:profession/:name", open_api.CreateHandleFunc(oe.Patch))
+	router.POST("/api/:profession/:name/reset-default", open_api.CreateHandleFunc(oe.ResetDefault))
+	router.POST(open_api.PathProvisionDefault, open_api.CreateHandleFunc(oe.Provision))
 
 }
 
@@ -13,6 +13,7 @@ import (
 	"encoding/json"
 	"github.com/eolinker/eosc/config"
 	grpc_unixsocket "github.com/eolinker/eosc/grpc-unixsocket"
+	"github.com/eolinker/eosc/metrics"
 	open_api "github.com/eolinker/eosc/open-api"
 	"github.com/eolinker/eosc/professions"
 	"github.com/eolinker/eosc/require"
@@ -34,6 +35,7 @@ import (
 	"github.com/eolinker/eosc/process"
 
 	"github.com/eolinker/eosc/extends"
+	"github.com/eolinker/eosc/extends/remote"
 
 	"github.com/eolinker/eosc/common/bean"
 
@@ -72,15 +74,28 @@ type ProcessAdmin struct {
 	once   sync.Once
 	reg    eosc.IExtenderDriverRegister
 	router *httprouter.Router
+	// priorRouter 与 router 中参数路由冲突的固定路由，先于 router 匹配
+	priorRouter *httprouter.Router
 
 	apiLocker sync.Mutex
 	server    *http.Server
+	metrics   http.Handler
 }
 
 func (pa *ProcessAdmin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == metrics.Path {
+		pa.metrics.ServeHTTP(w, r)
+		return
+	}
 	pa.apiLocker.Lock()
 	defer pa.apiLocker.Unlock()
-	pa.router.ServeHTTP(w, r)
+	router := pa.router
+	if handle, _, _ := pa.priorRouter.Lookup(r.Method, r.URL.Path); handle != nil {
+		router = pa.priorRouter
+	}
+	observe(router, w, r, func(w http.ResponseWriter) {
+		router.ServeHTTP(w, r)
+	})
 }
 
 func (pa *ProcessAdmin) writeOutput(status int, msg string) {
@@ -137,18 +152,22 @@ func NewProcessAdmin(parent context.Context, arg map[string]map[string][]byte) (
 
 	p := &ProcessAdmin{
 
-		router: httprouter.New(),
-		server: &http.Server{},
+		router:      httprouter.New(),
+		priorRouter: httprouter.New(),
+		server:      &http.Server{},
+		metrics:     metrics.Handler(metrics.Gatherer()),
 	}
 	p.server.Handler = p
 	extenderRequire := require.NewRequireManager()
 	extenderData := NewExtenderData(arg[eosc.NamespaceExtender], extenderRequire)
-	NewExtenderOpenApi(extenderData).Register(p.router)
 
 	ps := professions.NewProfessions(register)
 
 	ps = NewProfessionsRequire(ps, extenderRequire)
-	ps.Reset(professionConfig(arg[eosc.NamespaceProfession]))
+	if err := ps.Reset(professionConfig(arg[eosc.NamespaceProfession])); err != nil {
+		// 依赖无法解析的 profession 标记为不可用，其余 profession 正常加载，避免 admin 反复重启
+		log.Error("reset profession:", err)
+	}
 
 	vd := variable.NewVariables(arg[eosc.NamespaceVariable])
 	wd := NewWorkerDatas(filerSetting(arg[eosc.NamespaceWorker], Setting, false))
@@ -160,13 +179,20 @@ func NewProcessAdmin(parent context.Context, arg map[string]map[string][]byte) (
 	bean.Check()
 	settingApi := NewSettingApi(filerSetting(arg[eosc.NamespaceWorker], Setting, true), ws, vd)
 
+	ws.InitDefaults(arg[eosc.NamespaceDefault])
 	ws.Init(ps, wd, vd)
 
 	// openAPI handler register
-	NewProfessionApi(ps, wd).Register(p.router)
+	extenderApi := NewExtenderOpenApi(extenderData, ps, wd)
+	extenderApi.Register(p.router)
+	extenderApi.RegisterPrior(p.priorRouter)
+	professionApi := NewProfessionApi(ps, wd, ws)
+	professionApi.Register(p.router)
+	professionApi.RegisterPrior(p.priorRouter)
 	NewWorkerApi(ws, settingApi.request).Register(p.router)
 	settingApi.RegisterSetting(p.router)
 	NewExportApi(extenderData, ps, ws).Register(p.router)
+	NewMigrateApi(ws).Register(p.router)
 	NewVariableApi(extenderData, ws, vd, setting.GetSettings()).Register(p.router)
 
 	p.router.NotFound = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -193,6 +219,7 @@ func (pa *ProcessAdmin) close() {
 
 		timeout, _ := context.WithTimeout(context.Background(), time.Second*3)
 		pa.server.Shutdown(timeout)
+		remote.CloseAll()
 	})
 }
 func initExtender(config map[string][]byte) extends.IExtenderRegister {
@@ -31,8 +31,8 @@ func (p *ProfessionsRequire) Set(name string, profession *eosc.ProfessionConfig)
 	return nil
 }
 
-func (p *ProfessionsRequire) Reset(configs []*eosc.ProfessionConfig) {
-	p.IProfessions.Reset(configs)
+func (p *ProfessionsRequire) Reset(configs []*eosc.ProfessionConfig) error {
+	err := p.IProfessions.Reset(configs)
 	for _, c := range configs {
 		drivers := make([]string, 0, len(c.Drivers))
 		for _, d := range c.Drivers {
@@ -40,6 +40,7 @@ func (p *ProfessionsRequire) Reset(configs []*eosc.ProfessionConfig) {
 		}
 		p.requires.Set(c.Name, drivers)
 	}
+	return err
 }
 
 func NewProfessionsRequire(professions professions.IProfessions, requires eosc.IRequires) *ProfessionsRequire {
@@ -13,9 +13,11 @@ type WorkerInfo struct {
 	attr         map[string]interface{}
 	info         map[string]interface{}
 	configType   reflect.Type
+	// state 最后一次生效的配置与依赖
+	state *eosc.WorkerState
 }
 
-func NewWorkerInfo(worker eosc.IWorker, id string, profession string, name, driver, desc, create, update string, body []byte, configType reflect.Type) *WorkerInfo {
+func NewWorkerInfo(worker eosc.IWorker, id string, profession string, name, driver, desc, create, update string, body []byte, schema int32, configType reflect.Type) *WorkerInfo {
 
 	return &WorkerInfo{
 
@@ -29,17 +31,19 @@ func NewWorkerInfo(worker eosc.IWorker, id string, profession string, name, driv
 			Update:      update,
 			Description: desc,
 			Body:        body,
+			Schema:      schema,
 		},
 		configType: configType,
 		attr:       nil,
 	}
 }
 
-func (w *WorkerInfo) reset(driver, desc string, body []byte, worker eosc.IWorker, configType reflect.Type) {
+func (w *WorkerInfo) reset(driver, desc string, body []byte, schema int32, worker eosc.IWorker, configType reflect.Type) {
 	w.config.Update = eosc.Now()
 	w.config.Driver = driver
 	w.config.Description = desc
 	w.config.Body = body
+	w.config.Schema = schema
 	w.configType = configType
 	w.worker = worker
 	w.info = nil
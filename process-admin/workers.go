@@ -1,6 +1,7 @@
 package process_admin
 
 import (
+	"errors"
 	"fmt"
 	"github.com/eolinker/eosc"
 	"github.com/eolinker/eosc/log"
@@ -8,7 +9,10 @@ import (
 
 	"github.com/eolinker/eosc/professions"
 	"github.com/eolinker/eosc/utils/config"
+	"github.com/eolinker/eosc/utils/dag"
 	"reflect"
+	"sync"
+	"time"
 )
 
 type Workers struct {
@@ -16,11 +20,14 @@ type Workers struct {
 	data           *WorkerDatas
 	requireManager eosc.IRequires
 	variables      eosc.IVariable
+	migrations     map[string]*WorkerMigration
+	defaultLocker  sync.Mutex
+	defaults       map[string]bool
 }
 
 func NewWorkers() *Workers {
 
-	ws := &Workers{requireManager: require.NewRequireManager()}
+	ws := &Workers{requireManager: require.NewRequireManager(), migrations: make(map[string]*WorkerMigration), defaults: make(map[string]bool)}
 
 	return ws
 }
@@ -36,14 +43,47 @@ func (oe *Workers) Init(professions professions.IProfessions, data *WorkerDatas,
 		pm[wd.config.Profession] = append(pm[wd.config.Profession], wd)
 	}
 
+	start := time.Now()
+	nodes := make([]*dag.Node, 0, oe.data.Count())
+	parsed := make(map[string]*workerConfig)
+	migrations := make(map[string]*WorkerMigration)
 	for _, pw := range ps {
 		for _, v := range pm[pw.Name] {
-			_, err := oe.set(v.config.Id, v.config.Profession, v.config.Name, v.config.Driver, v.config.Description, JsonData(v.config.Body))
+			body, migration := oe.migrate(v.config)
+			if migration != nil && migration.Error != "" {
+				oe.migrations[v.config.Id] = migration
+				log.Errorf("init %s:%s", v.config.Id, migration.Error)
+				continue
+			}
+			c, err := oe.parse(v.config.Id, v.config.Profession, v.config.Name, v.config.Driver, v.config.Description, JsonData(body))
 			if err != nil {
 				log.Errorf("init %s:%s", v.config.Id, err.Error())
+				continue
+			}
+			if migration != nil {
+				migrations[v.config.Id] = migration
 			}
+			parsed[c.id] = c
+			nodes = append(nodes, &dag.Node{Id: c.id, Requires: config.DriverRequires(c.driver, c.conf)})
+		}
+	}
+	results := dag.Run(nodes, dag.DefaultConcurrency, func(id string) error {
+		_, err := oe.apply(parsed[id])
+		return err
+	})
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			log.Errorf("init %s in %s:%s", r.Id, r.Spend, r.Err.Error())
+			continue
+		}
+		log.Debug("init ", r.Id, " in ", r.Spend)
+		if migration, has := migrations[r.Id]; has {
+			oe.migrations[r.Id] = migration
 		}
 	}
+	log.Info("admin init ", len(nodes), " workers in ", time.Since(start), ", failed ", failed)
 }
 func (oe *Workers) ListEmployees(profession string) ([]interface{}, error) {
 	p, has := oe.professions.Get(profession)
@@ -81,6 +121,8 @@ func (oe *Workers) Update(profession, name, driver, desc string, data IData) (*W
 	if err != nil {
 		return nil, err
 	}
+	// 新的配置写入后无需再迁移
+	delete(oe.migrations, id)
 
 	return w, nil
 
@@ -88,7 +130,16 @@ func (oe *Workers) Update(profession, name, driver, desc string, data IData) (*W
 func (oe *Workers) rebuild(id string) error {
 	info, has := oe.data.GetInfo(id)
 	if has {
-		_, err := oe.set(id, info.config.Profession, info.config.Name, info.config.Driver, info.config.Description, info.config.Body)
+		// 保存的配置可能是旧版本，需要先升级
+		body, migration := oe.migrate(info.config)
+		if migration != nil && migration.Error != "" {
+			oe.migrations[id] = migration
+			return errors.New(migration.Error)
+		}
+		_, err := oe.set(id, info.config.Profession, info.config.Name, info.config.Driver, info.config.Description, body)
+		if err == nil && migration != nil {
+			oe.migrations[id] = migration
+		}
 		return err
 	}
 	return nil
@@ -121,6 +172,7 @@ func (oe *Workers) Delete(id string) (*WorkerInfo, error) {
 	}
 
 	oe.data.Del(id)
+	delete(oe.migrations, id)
 	destroy, ok := worker.worker.(eosc.IWorkerDestroy)
 	if ok {
 		destroy.Destroy()
@@ -143,13 +195,38 @@ func (oe *Workers) GetEmployee(profession, name string) (*WorkerInfo, error) {
 	return d, nil
 }
 
+// workerConfig 解析后待应用的 worker 配置
+type workerConfig struct {
+	id            string
+	profession    string
+	name          string
+	driverName    string
+	desc          string
+	body          []byte
+	schema        int32
+	driver        eosc.IExtenderDriver
+	conf          interface{}
+	usedVariables []string
+}
+
 func (oe *Workers) set(id, profession, name, driverName, desc string, body []byte) (*WorkerInfo, error) {
+	c, err := oe.parse(id, profession, name, driverName, desc, body)
+	if err != nil {
+		return nil, err
+	}
+	return oe.apply(c)
+}
+
+func (oe *Workers) parse(id, profession, name, driverName, desc string, body []byte) (*workerConfig, error) {
 
 	log.Debug("set:", id, ",", profession, ",", name, ",", driverName)
 	p, has := oe.professions.Get(profession)
 	if !has {
 		return nil, fmt.Errorf("%s:%w", profession, eosc.ErrorProfessionNotExist)
 	}
+	if err := oe.professions.Unusable(profession); err != nil {
+		return nil, fmt.Errorf("profession %s unusable:%w", profession, err)
+	}
 	if p.Mod == eosc.ProfessionConfig_Singleton {
 		driverName = name
 	}
@@ -158,49 +235,75 @@ func (oe *Workers) set(id, profession, name, driverName, desc string, body []byt
 		return nil, fmt.Errorf("%s,%w", driverName, eosc.ErrorDriverNotExist)
 	}
 
+	// 通过 set 写入的配置都是 driver 当前版本的配置
+	schema := int32(eosc.SchemaVersion(driver))
 	conf, usedVariables, err := oe.variables.Unmarshal(body, driver.ConfigType())
 	if err != nil {
 		return nil, err
 	}
+	return &workerConfig{
+		id:            id,
+		profession:    profession,
+		name:          name,
+		driverName:    driverName,
+		desc:          desc,
+		body:          body,
+		schema:        schema,
+		driver:        driver,
+		conf:          conf,
+		usedVariables: usedVariables,
+	}, nil
+}
 
-	requires, err := config.CheckConfig(conf, oe.data)
+// apply 创建或重置 worker，Init 时会被并发调用
+func (oe *Workers) apply(c *workerConfig) (*WorkerInfo, error) {
+	id, driver := c.id, c.driver
+	requires, err := config.CheckDriverConfig(driver, c.conf, oe.data)
 	if err != nil {
 		return nil, err
 	}
 	if dc, ok := driver.(eosc.IExtenderConfigChecker); ok {
-		if e := dc.Check(conf, requires); err != nil {
+		if e := dc.Check(c.conf, requires); err != nil {
 			return nil, e
 		}
 	}
 	wInfo, hasInfo := oe.data.GetInfo(id)
 	if hasInfo && wInfo.worker != nil {
-
-		e := wInfo.worker.Reset(conf, requires)
+		var prev *eosc.WorkerState
+		if wInfo.config.Driver == c.driverName {
+			prev = wInfo.state
+		}
+		state, reset, e := eosc.ResetWorker(wInfo.worker, prev, c.conf, requires)
 		if e != nil {
 			return nil, e
 		}
+		if !reset {
+			log.Debug("worker-data skip reset, nothing changed:", id)
+		}
 		oe.requireManager.Set(id, getIds(requires))
-		wInfo.reset(driverName, desc, body, wInfo.worker, driver.ConfigType())
-		oe.variables.SetVariablesById(id, usedVariables)
+		wInfo.reset(c.driverName, c.desc, c.body, c.schema, wInfo.worker, driver.ConfigType())
+		wInfo.state = state
+		oe.variables.SetVariablesById(id, c.usedVariables)
 		return wInfo, nil
 	}
 	// create
-	worker, err := driver.Create(id, name, conf, requires)
+	worker, err := driver.Create(id, c.name, c.conf, requires)
 	if err != nil {
 		log.Warn("worker-data set worker create:", err)
 		return nil, err
 	}
 
 	if !hasInfo {
-		wInfo = NewWorkerInfo(worker, id, profession, name, driverName, desc, eosc.Now(), eosc.Now(), body, driver.ConfigType())
+		wInfo = NewWorkerInfo(worker, id, c.profession, c.name, c.driverName, c.desc, eosc.Now(), eosc.Now(), c.body, c.schema, driver.ConfigType())
 	} else {
-		wInfo.reset(driverName, desc, body, worker, driver.ConfigType())
+		wInfo.reset(c.driverName, c.desc, c.body, c.schema, worker, driver.ConfigType())
 	}
+	wInfo.state = eosc.NewWorkerState(c.conf, requires)
 
 	// store
 	oe.data.Set(id, wInfo)
 	oe.requireManager.Set(id, getIds(requires))
-	oe.variables.SetVariablesById(id, usedVariables)
+	oe.variables.SetVariablesById(id, c.usedVariables)
 	log.Debug("worker-data set worker done:", id)
 
 	return wInfo, nil
@@ -0,0 +1,119 @@
+package process_admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/professions"
+	"github.com/eolinker/eosc/variable"
+)
+
+type testConfig struct {
+	Target eosc.RequireId `json:"target" skill:"test" required:"false"`
+}
+
+// testDriver 记录 worker 的创建顺序
+type testDriver struct {
+	locker  sync.Mutex
+	created []string
+}
+
+func (d *testDriver) Render() interface{} {
+	return nil
+}
+
+func (d *testDriver) Create(profession string, name string, label string, desc string, params map[string]interface{}) (eosc.IExtenderDriver, error) {
+	return testExtenderDriver{d}, nil
+}
+
+func (d *testDriver) GetDriver(name string) (eosc.IExtenderDriverFactory, bool) {
+	return d, true
+}
+
+type testExtenderDriver struct {
+	*testDriver
+}
+
+func (d testExtenderDriver) ConfigType() reflect.Type {
+	return reflect.TypeOf((*testConfig)(nil))
+}
+
+func (d testExtenderDriver) Create(id, name string, v interface{}, workers map[eosc.RequireId]eosc.IWorker) (eosc.IWorker, error) {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	d.created = append(d.created, id)
+	return &testWorker{id: id}, nil
+}
+
+type testWorker struct {
+	id string
+}
+
+func (w *testWorker) Id() string {
+	return w.id
+}
+
+func (w *testWorker) Start() error {
+	return nil
+}
+
+func (w *testWorker) Reset(conf interface{}, workers map[eosc.RequireId]eosc.IWorker) error {
+	return nil
+}
+
+func (w *testWorker) Stop() error {
+	return nil
+}
+
+func (w *testWorker) CheckSkill(skill string) bool {
+	return skill == "test"
+}
+
+func TestWorkersInit(t *testing.T) {
+	d := &testDriver{}
+	ps := professions.NewProfessions(d)
+	if err := ps.Reset([]*eosc.ProfessionConfig{{
+		Name:    "service",
+		Drivers: []*eosc.DriverConfig{{Id: "test:test", Name: "test"}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	bodies := map[string]string{
+		"a": `{"target":"b@service"}`,
+		"b": `{"target":"c@service"}`,
+		"c": `{}`,
+		"d": `{"target":`,
+	}
+	for i := 0; i < 20; i++ {
+		bodies[fmt.Sprint("n", i)] = `{"target":"c@service"}`
+	}
+	initData := make(map[string][]byte)
+	for name, body := range bodies {
+		id := fmt.Sprint(name, "@service")
+		initData[id], _ = json.Marshal(&eosc.WorkerConfig{Id: id, Profession: "service", Name: name, Driver: "test", Body: []byte(body)})
+	}
+
+	ws := NewWorkers()
+	ws.Init(ps, NewWorkerDatas(initData), variable.NewVariables(nil))
+
+	index := make(map[string]int)
+	for i, id := range d.created {
+		index[id] = i
+	}
+	if len(index) != len(bodies)-1 {
+		t.Fatalf("created %d workers, want %d", len(index), len(bodies)-1)
+	}
+	if _, has := index["d@service"]; has {
+		t.Error("worker with invalid config should not be created")
+	}
+	if index["b@service"] < index["c@service"] || index["a@service"] < index["b@service"] {
+		t.Errorf("workers created out of dependency order: %v", d.created)
+	}
+	if requires := ws.requireManager.Requires("a@service"); len(requires) != 1 || requires[0] != "b@service" {
+		t.Errorf("requires of a@service = %v", requires)
+	}
+}
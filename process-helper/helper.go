@@ -16,6 +16,7 @@ import (
 	"github.com/eolinker/eosc/service"
 
 	"github.com/eolinker/eosc/extends"
+	"github.com/eolinker/eosc/extends/remote"
 
 	"github.com/golang/protobuf/proto"
 
@@ -39,7 +40,10 @@ func Process() {
 		log.Error("data unmarshal error: ", err)
 		return
 	}
-	data, err := proto.Marshal(getExtenders(request))
+	response := getExtenders(request)
+	// 检查完成后无需保留进程外插件
+	remote.CloseAll()
+	data, err := proto.Marshal(response)
 	if err != nil {
 		log.Error("data marshal error: ", err)
 		return
@@ -22,6 +22,12 @@ type UnixClient struct {
 	addr    string
 	client  *http.Client
 	timeout time.Duration
+	onStart func()
+}
+
+// OnStart admin 进程启动或重启后在新的协程中调用 f
+func (uc *UnixClient) OnStart(f func()) {
+	uc.onStart = f
 }
 
 func (uc *UnixClient) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
@@ -37,6 +43,18 @@ func (uc *UnixClient) Update(process *exec.Cmd) {
 		return
 	}
 	uc.addr = service.ServerUnixAddr(process.Process.Pid, "admin")
+	if uc.onStart != nil {
+		go uc.onStart()
+	}
+}
+
+// Ping 检查 admin 进程的 unix socket 是否可以连接
+func (uc *UnixClient) Ping() error {
+	conn, err := uc.DialContext(context.Background(), "unix", uc.addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
 }
 
 func NewUnixClient() *UnixClient {
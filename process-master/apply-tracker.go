@@ -0,0 +1,229 @@
+package process_master
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/common/dispatcher"
+	open_api "github.com/eolinker/eosc/open-api"
+	"github.com/eolinker/eosc/service"
+)
+
+const (
+	ApplyUnknown = "unknown"
+	ApplyPending = "pending"
+	ApplyApplied = "applied"
+	ApplyFailed  = "failed"
+)
+
+type ApplyState struct {
+	Node    string    `json:"node"`
+	Id      string    `json:"id"`
+	Command string    `json:"command,omitempty"`
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+	Update  time.Time `json:"update,omitempty"`
+	hash    uint64
+}
+
+// ApplyTracker 记录发往 worker 进程的 worker 配置在数据面的应用结果
+// 同一节点可能有多个 worker 进程，按进程分别记录，对外汇总：任一进程失败即失败，全部生效才算生效
+type ApplyTracker struct {
+	locker    sync.Mutex
+	processes map[string]map[string]*ApplyState
+	// changed 在状态变化时关闭并替换，用于唤醒等待者
+	changed chan struct{}
+}
+
+func NewApplyTracker() *ApplyTracker {
+	return &ApplyTracker{
+		processes: make(map[string]map[string]*ApplyState),
+		changed:   make(chan struct{}),
+	}
+}
+
+// Sent 事件发送给 worker 进程后标记为 pending
+func (t *ApplyTracker) Sent(pid string, event dispatcher.IEvent) {
+	t.locker.Lock()
+	defer t.locker.Unlock()
+	now := time.Now()
+	switch event.Event() {
+	case eosc.EventInit, eosc.EventReset:
+		states := make(map[string]*ApplyState)
+		for id, data := range event.All()[eosc.NamespaceWorker] {
+			states[id] = &ApplyState{Id: id, Command: eosc.EventSet, Status: ApplyPending, Update: now, hash: service.ApplyHash(data)}
+		}
+		t.processes[pid] = states
+	case eosc.EventSet, eosc.EventDel:
+		if event.Namespace() != eosc.NamespaceWorker {
+			return
+		}
+		states, has := t.processes[pid]
+		if !has {
+			states = make(map[string]*ApplyState)
+			t.processes[pid] = states
+		}
+		states[event.Key()] = &ApplyState{Id: event.Key(), Command: event.Event(), Status: ApplyPending, Update: now, hash: service.ApplyHash(event.Data())}
+	default:
+		return
+	}
+	t.notify()
+}
+
+// Report 记录 worker 进程回报的结果，与当前配置摘要不一致的结果已过期，直接忽略
+func (t *ApplyTracker) Report(pid string, results []*service.ApplyResult) {
+	t.locker.Lock()
+	defer t.locker.Unlock()
+	states, has := t.processes[pid]
+	if !has {
+		return
+	}
+	now := time.Now()
+	for _, r := range results {
+		if r.Namespace != eosc.NamespaceWorker {
+			continue
+		}
+		s, has := states[r.Key]
+		if !has || s.hash != r.Hash || s.Command != r.Command {
+			continue
+		}
+		s.Update = now
+		if r.Success {
+			s.Status = ApplyApplied
+			s.Error = ""
+		} else {
+			s.Status = ApplyFailed
+			s.Error = r.Error
+		}
+	}
+	t.notify()
+}
+
+// Remove worker 进程断开后移除其记录
+func (t *ApplyTracker) Remove(pid string) {
+	t.locker.Lock()
+	defer t.locker.Unlock()
+	if _, has := t.processes[pid]; !has {
+		return
+	}
+	delete(t.processes, pid)
+	t.notify()
+}
+
+// Pending 返回已连接的 worker 进程数以及尚未回报结果的配置数
+func (t *ApplyTracker) Pending() (int, int) {
+	t.locker.Lock()
+	defer t.locker.Unlock()
+	pending := 0
+	for _, states := range t.processes {
+		for _, s := range states {
+			if s.Status == ApplyPending {
+				pending++
+			}
+		}
+	}
+	return len(t.processes), pending
+}
+
+func (t *ApplyTracker) notify() {
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+// state 汇总各进程的状态，hash 不为0时只有与之一致的记录才算生效
+func (t *ApplyTracker) state(id string, hash uint64, command string) (*ApplyState, <-chan struct{}) {
+	t.locker.Lock()
+	defer t.locker.Unlock()
+	var result *ApplyState
+	for _, states := range t.processes {
+		s, has := states[id]
+		if !has {
+			if hash != 0 {
+				return &ApplyState{Id: id, Command: command, Status: ApplyPending, hash: hash}, t.changed
+			}
+			continue
+		}
+		if hash != 0 && (s.hash != hash || s.Command != command) {
+			return &ApplyState{Id: id, Command: command, Status: ApplyPending, hash: hash}, t.changed
+		}
+		if result == nil {
+			c := *s
+			result = &c
+			continue
+		}
+		if s.Update.After(result.Update) {
+			result.Update = s.Update
+		}
+		if s.hash != result.hash || s.Command != result.Command {
+			result.Status = ApplyPending
+		}
+		switch {
+		case s.Status == ApplyFailed:
+			result.Status = ApplyFailed
+			result.Error = s.Error
+		case s.Status == ApplyPending && result.Status != ApplyFailed:
+			result.Status = ApplyPending
+		}
+	}
+	if result == nil {
+		if hash != 0 {
+			return &ApplyState{Id: id, Command: command, Status: ApplyPending, hash: hash}, t.changed
+		}
+		return &ApplyState{Id: id, Status: ApplyUnknown}, t.changed
+	}
+	return result, t.changed
+}
+
+func (t *ApplyTracker) Get(id string) *ApplyState {
+	s, _ := t.state(id, 0, "")
+	return s
+}
+
+// WaitState 等待 worker 的状态不再是 pending，超时返回当前状态
+func (t *ApplyTracker) WaitState(ctx context.Context, id string) *ApplyState {
+	for {
+		s, changed := t.state(id, 0, "")
+		if s.Status != ApplyPending {
+			return s
+		}
+		select {
+		case <-ctx.Done():
+			return s
+		case <-changed:
+		}
+	}
+}
+
+// WaitApplied 等待 worker 的 command 事件（配置摘要为 hash）在本节点所有 worker 进程生效，超时返回当前状态
+func (t *ApplyTracker) WaitApplied(ctx context.Context, id string, hash uint64, command string) *ApplyState {
+	for {
+		s, changed := t.state(id, hash, command)
+		if s.Status != ApplyPending {
+			return s
+		}
+		select {
+		case <-ctx.Done():
+			return s
+		case <-changed:
+		}
+	}
+}
+
+// Wait 等待 open api 产生的 worker 事件在本节点所有 worker 进程生效
+func (t *ApplyTracker) Wait(ctx context.Context, events []*open_api.EventResponse) (string, string) {
+	for _, e := range events {
+		if e.Namespace != eosc.NamespaceWorker {
+			continue
+		}
+		s := t.WaitApplied(ctx, e.Key, service.ApplyHash(e.Data), e.Event)
+		if s.Status == ApplyFailed {
+			return ApplyFailed, s.Error
+		}
+		if s.Status == ApplyPending {
+			return ApplyPending, ""
+		}
+	}
+	return ApplyApplied, ""
+}
@@ -0,0 +1,100 @@
+package process_master
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/common/dispatcher"
+	open_api "github.com/eolinker/eosc/open-api"
+	"github.com/eolinker/eosc/service"
+)
+
+type workerEvent struct {
+	dispatcher.InitEvent
+	command string
+	key     string
+	data    []byte
+}
+
+func (e workerEvent) Namespace() string { return eosc.NamespaceWorker }
+func (e workerEvent) Event() string     { return e.command }
+func (e workerEvent) Key() string       { return e.key }
+func (e workerEvent) Data() []byte      { return e.data }
+
+func applyResult(key string, data []byte, success bool) []*service.ApplyResult {
+	r := &service.ApplyResult{Namespace: eosc.NamespaceWorker, Key: key, Command: eosc.EventSet, Hash: service.ApplyHash(data), Success: success}
+	if !success {
+		r.Error = "create failed"
+	}
+	return []*service.ApplyResult{r}
+}
+
+func TestApplyTracker(t *testing.T) {
+	v1, v2 := []byte(`{"v":1}`), []byte(`{"v":2}`)
+	tracker := NewApplyTracker()
+	init := dispatcher.InitEvent{eosc.NamespaceWorker: {"a@service": v1}}
+	tracker.Sent("p1", init)
+	tracker.Sent("p2", init)
+
+	steps := []struct {
+		name   string
+		do     func()
+		status string
+	}{
+		{name: "sent", do: func() {}, status: ApplyPending},
+		{name: "one process applied", do: func() { tracker.Report("p1", applyResult("a@service", v1, true)) }, status: ApplyPending},
+		{name: "stale result", do: func() { tracker.Report("p2", applyResult("a@service", v2, true)) }, status: ApplyPending},
+		{name: "all processes applied", do: func() { tracker.Report("p2", applyResult("a@service", v1, true)) }, status: ApplyApplied},
+		{name: "updated", do: func() {
+			e := workerEvent{command: eosc.EventSet, key: "a@service", data: v2}
+			tracker.Sent("p1", e)
+			tracker.Sent("p2", e)
+		}, status: ApplyPending},
+		{name: "one process failed", do: func() { tracker.Report("p1", applyResult("a@service", v2, false)) }, status: ApplyFailed},
+		{name: "failed process removed", do: func() {
+			tracker.Remove("p1")
+			tracker.Report("p2", applyResult("a@service", v2, true))
+		}, status: ApplyApplied},
+	}
+	for _, s := range steps {
+		s.do()
+		if got := tracker.Get("a@service"); got.Status != s.status {
+			t.Fatalf("%s: status = %s, want %s", s.name, got.Status, s.status)
+		}
+	}
+	if processes, pending := tracker.Pending(); processes != 1 || pending != 0 {
+		t.Errorf("Pending() = %d, %d", processes, pending)
+	}
+	if got := tracker.Get("b@service"); got.Status != ApplyUnknown {
+		t.Errorf("unknown worker status = %s", got.Status)
+	}
+}
+
+func TestApplyTrackerWait(t *testing.T) {
+	v1 := []byte(`{"v":1}`)
+	events := []*open_api.EventResponse{{Event: eosc.EventSet, Namespace: eosc.NamespaceWorker, Key: "a@service", Data: v1}}
+	tracker := NewApplyTracker()
+	tracker.Sent("p1", workerEvent{command: eosc.EventSet, key: "a@service", data: v1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	status, _ := tracker.Wait(ctx, events)
+	cancel()
+	if status != ApplyPending {
+		t.Errorf("Wait() before report = %s", status)
+	}
+
+	go func() {
+		time.Sleep(time.Millisecond * 50)
+		tracker.Report("p1", applyResult("a@service", v1, false))
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if s := tracker.WaitState(ctx, "a@service"); s.Status != ApplyFailed {
+		t.Errorf("WaitState() = %s", s.Status)
+	}
+	if status, msg := tracker.Wait(ctx, events); status != ApplyFailed || msg != "create failed" {
+		t.Errorf("Wait() = %s, %s", status, msg)
+	}
+}
@@ -0,0 +1,123 @@
+package process_master
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/etcd"
+	open_api "github.com/eolinker/eosc/open-api"
+	"github.com/eolinker/eosc/service"
+)
+
+// waitClient 等待时长由 ctx 控制，不设置超时
+var waitClient = &http.Client{}
+
+type clusterNodes interface {
+	Info() *etcd.Node
+	Nodes() []*etcd.Node
+}
+
+// ClusterApplyWaiter 等待 open api 产生的 worker 事件在集群所有节点的 worker 进程生效
+// 本节点直接等待 ApplyTracker，其他节点通过 node=local 的 worker 状态接口等待
+type ClusterApplyWaiter struct {
+	tracker *ApplyTracker
+	cluster func() clusterNodes
+}
+
+func NewClusterApplyWaiter(tracker *ApplyTracker, cluster func() clusterNodes) *ClusterApplyWaiter {
+	return &ClusterApplyWaiter{tracker: tracker, cluster: cluster}
+}
+
+// Wait 任一节点失败即失败，所有节点生效才算生效，无法访问的节点视为 pending，原因中带上节点名称
+func (c *ClusterApplyWaiter) Wait(ctx context.Context, events []*open_api.EventResponse) (string, string) {
+	var self *etcd.Node
+	var nodes []*etcd.Node
+	if cluster := c.cluster(); cluster != nil {
+		self, nodes = cluster.Info(), cluster.Nodes()
+	}
+
+	results := make([]*ApplyState, len(nodes))
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		if self != nil && n.ID == self.ID {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, n *etcd.Node) {
+			defer wg.Done()
+			results[i] = c.remote(ctx, n, events)
+		}(i, n)
+	}
+	local := c.local(ctx, events)
+	wg.Wait()
+	if self != nil {
+		local.Node = self.Name
+	}
+
+	status := ApplyApplied
+	reasons := make([]string, 0)
+	for _, s := range append(results, local) {
+		if s == nil || s.Status == ApplyApplied {
+			continue
+		}
+		if s.Error != "" {
+			reasons = append(reasons, fmt.Sprintf("%s:%s", s.Node, s.Error))
+		}
+		if s.Status == ApplyFailed {
+			status = ApplyFailed
+		} else if status != ApplyFailed {
+			status = ApplyPending
+		}
+	}
+	return status, strings.Join(reasons, "; ")
+}
+
+func (c *ClusterApplyWaiter) local(ctx context.Context, events []*open_api.EventResponse) *ApplyState {
+	s := &ApplyState{Status: ApplyApplied}
+	for _, e := range events {
+		if e.Namespace != eosc.NamespaceWorker {
+			continue
+		}
+		s = c.tracker.WaitApplied(ctx, e.Key, service.ApplyHash(e.Data), e.Event)
+		if s.Status != ApplyApplied {
+			return s
+		}
+	}
+	return s
+}
+
+func (c *ClusterApplyWaiter) remote(ctx context.Context, n *etcd.Node, events []*open_api.EventResponse) *ApplyState {
+	s := &ApplyState{Status: ApplyApplied}
+	for _, e := range events {
+		if e.Namespace != eosc.NamespaceWorker {
+			continue
+		}
+		profession, name, ok := eosc.SplitWorkerId(e.Key)
+		if !ok {
+			continue
+		}
+		wait := time.Second
+		if deadline, has := ctx.Deadline(); has {
+			wait = time.Until(deadline)
+		}
+		uri := fmt.Sprintf("/api/%s/%s/status?node=%s&wait=%s&hash=%d&command=%s", url.PathEscape(profession), url.PathEscape(name), nodeLocal, wait, service.ApplyHash(e.Data), e.Event)
+		s = &ApplyState{}
+		if err := requestNodeContext(ctx, waitClient, n.Admin, uri, s); err != nil {
+			s = &ApplyState{Id: e.Key, Status: ApplyPending}
+			if ctx.Err() == nil {
+				s.Error = err.Error()
+			}
+		}
+		s.Node = n.Name
+		if s.Status != ApplyApplied {
+			return s
+		}
+	}
+	return s
+}
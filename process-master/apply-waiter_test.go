@@ -0,0 +1,75 @@
+package process_master
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/etcd"
+	open_api "github.com/eolinker/eosc/open-api"
+	"github.com/eolinker/eosc/service"
+)
+
+type testCluster struct {
+	self  *etcd.Node
+	nodes []*etcd.Node
+}
+
+func (c *testCluster) Info() *etcd.Node    { return c.self }
+func (c *testCluster) Nodes() []*etcd.Node { return c.nodes }
+
+func TestClusterApplyWaiter(t *testing.T) {
+	v1 := []byte(`{"v":1}`)
+	events := []*open_api.EventResponse{{Event: eosc.EventSet, Namespace: eosc.NamespaceWorker, Key: "a@service", Data: v1}}
+	tracker := NewApplyTracker()
+	tracker.Sent("p1", workerEvent{command: eosc.EventSet, key: "a@service", data: v1})
+	tracker.Report("p1", applyResult("a@service", v1, true))
+
+	remote := func(status string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			if r.URL.Path != "/api/service/a/status" || query.Get("node") != nodeLocal ||
+				query.Get("hash") != fmt.Sprint(service.ApplyHash(v1)) || query.Get("command") != eosc.EventSet {
+				writeJson(w, http.StatusBadRequest, nil)
+				return
+			}
+			s := &ApplyState{Id: "a@service", Status: status}
+			if status == ApplyFailed {
+				s.Error = "create failed"
+			}
+			writeJson(w, http.StatusOK, s)
+		}))
+	}
+	applied, failed := remote(ApplyApplied), remote(ApplyFailed)
+	defer applied.Close()
+	defer failed.Close()
+	self := &etcd.Node{ID: "1", Name: "n1"}
+
+	tests := []struct {
+		name       string
+		remote     []string
+		wantStatus string
+		wantReason string
+	}{
+		{name: "applied", remote: []string{applied.URL}, wantStatus: ApplyApplied},
+		{name: "failed", remote: []string{failed.URL}, wantStatus: ApplyFailed, wantReason: "n2:create failed"},
+		{name: "unreachable", remote: []string{"http://127.0.0.1:1"}, wantStatus: ApplyPending, wantReason: "n2:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &testCluster{self: self, nodes: []*etcd.Node{self, {ID: "2", Name: "n2", Admin: tt.remote}}}
+			waiter := NewClusterApplyWaiter(tracker, func() clusterNodes { return cluster })
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			status, reason := waiter.Wait(ctx, events)
+			if status != tt.wantStatus || !strings.HasPrefix(reason, tt.wantReason) {
+				t.Errorf("Wait() = %s, %s", status, reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/eolinker/eosc/process"
+	"github.com/eolinker/eosc/service"
+)
+
+// Processes 当前节点各进程的运行状态
+func (m *MasterCliServer) Processes(ctx context.Context, request *service.ProcessesRequest) (*service.ProcessesResponse, error) {
+	infos := m.processes()
+	response := &service.ProcessesResponse{Processes: make([]*service.ProcessInfo, 0, len(infos))}
+	for _, info := range infos {
+		response.Processes = append(response.Processes, toProcessInfo(info))
+	}
+	return response, nil
+}
+
+func toProcessInfo(info *process.ProcessInfo) *service.ProcessInfo {
+	p := &service.ProcessInfo{
+		Name:     info.Name,
+		Index:    int32(info.Index),
+		Pid:      int32(info.Pid),
+		Status:   info.Status,
+		Restarts: int32(info.Restarts),
+		Uptime:   info.Uptime,
+	}
+	if !info.Start.IsZero() {
+		p.Start = info.Start.Unix()
+	}
+	if e := info.LastExit; e != nil {
+		p.LastExit = &service.ProcessExit{
+			Pid:    int32(e.Pid),
+			Code:   int32(e.Code),
+			Reason: e.Reason,
+			Stderr: e.Stderr,
+			Time:   e.Time.Unix(),
+		}
+	}
+	return p
+}
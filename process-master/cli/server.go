@@ -2,6 +2,7 @@ package cli
 
 import (
 	"github.com/eolinker/eosc/etcd"
+	"github.com/eolinker/eosc/process"
 	"github.com/eolinker/eosc/service"
 )
 
@@ -10,8 +11,9 @@ var _ service.CtiServiceServer = (*MasterCliServer)(nil)
 type MasterCliServer struct {
 	service.UnimplementedCtiServiceServer
 	etcdServe etcd.Etcd
+	processes func() []*process.ProcessInfo
 }
 
-func NewMasterCliServer(etcdServe etcd.Etcd) *MasterCliServer {
-	return &MasterCliServer{etcdServe: etcdServe}
+func NewMasterCliServer(etcdServe etcd.Etcd, processes func() []*process.ProcessInfo) *MasterCliServer {
+	return &MasterCliServer{etcdServe: etcdServe, processes: processes}
 }
@@ -0,0 +1,41 @@
+package process_master
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/eolinker/eosc/log"
+	open_api "github.com/eolinker/eosc/open-api"
+	proxy "github.com/eolinker/eosc/process-master/open-api"
+)
+
+const provisionTimeout = time.Second * 30
+
+// provisionDefaults admin 启动并加载插件后，为单例 profession 创建缺失的默认 worker，结果经 raft 持久化
+func (m *Master) provisionDefaults() {
+	ctx, cancel := context.WithTimeout(m.ctx, provisionTimeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Millisecond * 100)
+	defer ticker.Stop()
+	for m.adminClient.Ping() != nil {
+		select {
+		case <-ctx.Done():
+			log.Warn("provision default workers: admin not ready")
+			return
+		case <-ticker.C:
+		}
+	}
+	if isLeader, _ := m.etcdServer.IsLeader(); !isLeader {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, open_api.PathProvisionDefault, nil)
+	if err != nil {
+		log.Error("provision default workers:", err)
+		return
+	}
+	req.RequestURI = open_api.PathProvisionDefault
+	w := proxy.NewTemplateWriter()
+	m.openApiProxy.ServeHTTP(w, req)
+	log.Debug("provision default workers done")
+}
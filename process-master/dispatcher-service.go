@@ -7,6 +7,7 @@ import (
 	"github.com/eolinker/eosc/log"
 	"github.com/eolinker/eosc/process-master/extender"
 	"sync"
+	"time"
 
 	"github.com/eolinker/eosc/common/dispatcher"
 	"github.com/eolinker/eosc/service"
@@ -17,6 +18,13 @@ type DispatcherServer struct {
 	datacenter    dispatcher.IDispatchCenter
 	ctxManager    *CtxManager
 	currentStatus bool
+	tracker       *ApplyTracker
+
+	// epoch 区分不同 master 进程分配的序号
+	epoch      uint64
+	sendLocker sync.Mutex
+	sequence   uint64
+	replay     *replayLog
 }
 
 func (d *DispatcherServer) Update(es []*extender.Status, success bool) {
@@ -27,7 +35,13 @@ func (d *DispatcherServer) Update(es []*extender.Status, success bool) {
 }
 
 func NewDispatcherServer() *DispatcherServer {
-	return &DispatcherServer{datacenter: dispatcher.NewDataDispatchCenter(), ctxManager: NewCtxManager()}
+	return &DispatcherServer{
+		datacenter: dispatcher.NewDataDispatchCenter(),
+		ctxManager: NewCtxManager(),
+		tracker:    NewApplyTracker(),
+		epoch:      uint64(time.Now().UnixNano()),
+		replay:     newReplayLog(replayLogSize),
+	}
 }
 
 type CtxWidthCancel struct {
@@ -82,31 +96,32 @@ func (c *CtxManager) Stop(namespace string) {
 	c.lock.Unlock()
 }
 
-func (d *DispatcherServer) Listen(request *service.EmptyRequest, server service.MasterDispatcher_ListenServer) error {
+// Listen 新 worker 进程收到全量配置；重连的 worker 只补发其最后应用的序号之后的事件，日志不能覆盖时才全量重置
+func (d *DispatcherServer) Listen(request *service.ListenRequest, server service.MasterDispatcher_ListenServer) error {
 	ctx := d.ctxManager.Get("")
-	log.Debug("worker listen start")
+	pid := service.ProcessFromContext(server.Context())
+	log.Debug("worker listen start:", pid, " sequence:", request.Sequence)
 	listener := d.datacenter.Listener()
 	defer listener.Leave()
+	defer d.tracker.Remove(pid)
 	for {
 		select {
 		case e, ok := <-listener.Event():
 			if !ok {
 				return nil
 			}
-			event := &service.Event{
-				Namespace: e.Namespace(),
-				Command:   e.Event(),
-				Key:       e.Key(),
-				Data:      e.Data(),
-			}
-			if e.Event() == eosc.EventReset || e.Event() == eosc.EventInit {
-				event.Data, _ = json.Marshal(e.All())
-			} else {
-				event.Data = e.Data()
+			if snapshot, is := e.(*dispatcher.SnapshotEvent); is {
+				if events, ok := d.resync(request, snapshot.Sequence()); ok {
+					log.Debug("replay ", len(events), " events to worker:", pid)
+					for _, re := range events {
+						if err := d.send(server, pid, re); err != nil {
+							return err
+						}
+					}
+					continue
+				}
 			}
-			err := server.Send(event)
-			log.Debug("send listen to worker:", event.String())
-			if err != nil {
+			if err := d.send(server, pid, e); err != nil {
 				return err
 			}
 		case <-ctx.Done():
@@ -115,8 +130,63 @@ func (d *DispatcherServer) Listen(request *service.EmptyRequest, server service.
 	}
 }
 
+// resync 判断能否通过补发事件让 worker 追上快照
+func (d *DispatcherServer) resync(request *service.ListenRequest, snapshot uint64) ([]*sequenceEvent, bool) {
+	if request.Epoch != d.epoch || request.Sequence == 0 {
+		return nil, false
+	}
+	events, ok := d.replay.since(request.Sequence, snapshot)
+	if ok {
+		resyncTotal.WithLabelValues(resyncReplay).Inc()
+	} else {
+		resyncTotal.WithLabelValues(resyncFull).Inc()
+	}
+	return events, ok
+}
+
+func (d *DispatcherServer) send(server service.MasterDispatcher_ListenServer, pid string, e dispatcher.IEvent) error {
+	event := &service.Event{
+		Namespace: e.Namespace(),
+		Command:   e.Event(),
+		Key:       e.Key(),
+		Epoch:     d.epoch,
+	}
+	if s, is := e.(dispatcher.ISequence); is {
+		event.Sequence = s.Sequence()
+	}
+	if e.Event() == eosc.EventReset || e.Event() == eosc.EventInit {
+		event.Data, _ = json.Marshal(e.All())
+	} else {
+		event.Data = e.Data()
+	}
+	err := server.Send(event)
+	log.Debug("send listen to worker:", event.String())
+	if err != nil {
+		return err
+	}
+	d.tracker.Sent(pid, e)
+	return nil
+}
+
+// Report 接收 worker 进程回报的配置应用结果
+func (d *DispatcherServer) Report(ctx context.Context, report *service.ApplyReport) (*service.EmptyRequest, error) {
+	d.tracker.Report(service.ProcessFromContext(ctx), report.Results)
+	return &service.EmptyRequest{}, nil
+}
+
+func (d *DispatcherServer) Tracker() *ApplyTracker {
+	return d.tracker
+}
+
+// Dispatch 为事件分配序号并写入补发日志，序号与发送顺序一致
 func (d *DispatcherServer) Dispatch(event dispatcher.IEvent) {
-	d.datacenter.Send(event)
+	dispatchedTotal.WithLabelValues(event.Namespace(), event.Event()).Inc()
+	d.sendLocker.Lock()
+	defer d.sendLocker.Unlock()
+	d.sequence++
+	e := &sequenceEvent{IEvent: event, sequence: d.sequence}
+	d.replay.append(e)
+	d.datacenter.Send(e)
 }
 
 func (d *DispatcherServer) Close() error {
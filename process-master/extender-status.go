@@ -0,0 +1,128 @@
+package process_master
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/eolinker/eosc/log"
+	"github.com/eolinker/eosc/process-master/extender"
+	"github.com/julienschmidt/httprouter"
+)
+
+const nodeLocal = "local"
+
+var nodeClient = &http.Client{Timeout: time.Second * 5}
+
+var (
+	ErrorNodeStatus = errors.New("node response not ok")
+)
+
+type ExtenderNodeStatus struct {
+	Node       string    `json:"node"`
+	Id         string    `json:"id,omitempty"`
+	Group      string    `json:"group,omitempty"`
+	Project    string    `json:"project,omitempty"`
+	Version    string    `json:"version,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	RetryCount int       `json:"retry_count"`
+	NextTime   time.Time `json:"next_time,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// ExtenderStatusHandler 返回插件在集群各节点的下载/检查状态
+// 带上 node=local 时只返回当前节点的状态，供其他节点汇总使用
+func (m *Master) ExtenderStatusHandler(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	id := params.ByName("id")
+	if r.URL.Query().Get("node") == nodeLocal {
+		writeJson(w, http.StatusOK, m.localExtenderStatus(id))
+		return
+	}
+
+	self := m.etcdServer.Info()
+	nodes := m.etcdServer.Nodes()
+	statuses := make([]*ExtenderNodeStatus, 0, len(nodes))
+	for _, n := range nodes {
+		if self != nil && n.ID == self.ID {
+			s := m.localExtenderStatus(id)
+			s.Node = n.Name
+			statuses = append(statuses, s)
+			continue
+		}
+		statuses = append(statuses, remoteExtenderStatus(n.Name, n.Admin, id))
+	}
+	writeJson(w, http.StatusOK, statuses)
+}
+
+func (m *Master) localExtenderStatus(id string) *ExtenderNodeStatus {
+	s := &ExtenderNodeStatus{Id: id}
+	if info := m.etcdServer.Info(); info != nil {
+		s.Node = info.Name
+	}
+	item, has := m.extenderManager.Snapshot(id)
+	if !has {
+		s.Error = extender.ErrNotExist.Error()
+		return s
+	}
+	s.Group = item.Group
+	s.Project = item.Project
+	s.Version = item.Version
+	s.Status = extender.StatusName(item.Status)
+	s.RetryCount = item.RetryCount
+	if item.Status == extender.StatusDownloadFault || item.Status == extender.StatusCheckFault {
+		s.NextTime = item.NextTime
+	}
+	return s
+}
+
+func remoteExtenderStatus(node string, addrs []string, id string) *ExtenderNodeStatus {
+	s := new(ExtenderNodeStatus)
+	err := requestNode(addrs, fmt.Sprintf("/extender/%s/status?node=%s", id, nodeLocal), s)
+	if err != nil {
+		log.Warnf("get extender %s status from %s:%v", id, node, err)
+		return &ExtenderNodeStatus{Node: node, Id: id, Error: err.Error()}
+	}
+	s.Node = node
+	return s
+}
+
+// requestNode 依次尝试节点的 admin 地址获取 json 数据
+func requestNode(addrs []string, uri string, v interface{}) error {
+	return requestNodeContext(context.Background(), nodeClient, addrs, uri, v)
+}
+
+func requestNodeContext(ctx context.Context, client *http.Client, addrs []string, uri string, v interface{}) error {
+	err := errors.New("node has no admin address")
+	for _, addr := range addrs {
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprint(addr, uri), nil)
+		if err != nil {
+			continue
+		}
+		var resp *http.Response
+		resp, err = client.Do(req)
+		if err != nil {
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("%w: %s", ErrorNodeStatus, resp.Status)
+			resp.Body.Close()
+			continue
+		}
+		err = json.NewDecoder(resp.Body).Decode(v)
+		resp.Body.Close()
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func writeJson(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,40 @@
+package process_master
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestNode(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, &ExtenderNodeStatus{Node: "ok"})
+	}))
+	defer ok.Close()
+	// 其他节点的 admin 未注册该路由时返回 404，body 仍是合法的 json
+	notFound := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusNotFound, &ExtenderNodeStatus{})
+	}))
+	defer notFound.Close()
+
+	tests := []struct {
+		name    string
+		addrs   []string
+		want    string
+		wantErr error
+	}{
+		{name: "ok", addrs: []string{ok.URL}, want: "ok"},
+		{name: "fallback", addrs: []string{notFound.URL, ok.URL}, want: "ok"},
+		{name: "not ok", addrs: []string{notFound.URL}, wantErr: ErrorNodeStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(ExtenderNodeStatus)
+			err := requestNode(tt.addrs, "/extender/a:b/status?node=local", s)
+			if !errors.Is(err, tt.wantErr) || s.Node != tt.want {
+				t.Errorf("requestNode() = %+v, %v", s, err)
+			}
+		})
+	}
+}
@@ -35,6 +35,18 @@ func (e *Check) Get(name string) (*Item, bool) {
 	return nil, false
 }
 
+// Snapshot 返回插件检查项的快照，避免调用方直接读写检查中的数据
+func (e *Check) Snapshot(name string) (*Item, bool) {
+	e.locker.RLock()
+	defer e.locker.RUnlock()
+	v, ok := e.items[name]
+	if !ok {
+		return nil, false
+	}
+	item := *v
+	return &item, true
+}
+
 func (e *Check) Reset(data map[string][]byte) {
 	item := make(map[string]*Item)
 	e.locker.Lock()
@@ -161,6 +173,13 @@ func (e *Check) doLoop() {
 	}
 }
 
+// Statuses 所有插件当前的检查状态
+func (e *Check) Statuses() []*Status {
+	e.locker.RLock()
+	defer e.locker.RUnlock()
+	return e.getStatus()
+}
+
 func (e *Check) getStatus() []*Status {
 	statuses := make([]*Status, 0, len(e.items))
 	for _, item := range e.items {
@@ -3,5 +3,5 @@ package extender
 import "errors"
 
 var (
-	errNotExist = errors.New("the extender is not exist")
+	ErrNotExist = errors.New("the extender is not exist")
 )
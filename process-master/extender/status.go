@@ -19,3 +19,17 @@ type Status struct {
 func (s *Status) Name() string {
 	return fmt.Sprint(s.Group, ":", s.Project)
 }
+
+func StatusName(status int) string {
+	switch status {
+	case StatusSuccess:
+		return "success"
+	case StatusInit:
+		return "init"
+	case StatusDownloadFault:
+		return "download fault"
+	case StatusCheckFault:
+		return "check fault"
+	}
+	return "unknown"
+}
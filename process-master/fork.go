@@ -10,10 +10,10 @@ package process_master
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"errors"
 	"os"
+	"os/exec"
 	"syscall"
 	"time"
 
@@ -30,7 +30,11 @@ import (
 
 var runningMasterForked = new(ForkStatus)
 
+// forkTimeout 新 master 进程需要在该时间内启动完成并通知旧进程退出，否则旧进程恢复服务
+const forkTimeout = time.Second * 60
+
 // Fork Master fork 子进程，入参为子进程需要的内容
+// etcd 数据目录同一时间只能由一个进程使用，fork 前暂停本进程的 etcd，新进程失败时再恢复
 func (m *Master) Fork(pFile *pidfile.PidFile) error {
 	if !runningMasterForked.Start() {
 		return errors.New("Another process already forked. Ignoring this one.")
@@ -38,6 +42,7 @@ func (m *Master) Fork(pFile *pidfile.PidFile) error {
 
 	err := pFile.TryFork()
 	if err != nil {
+		runningMasterForked.Stop()
 		return err
 	}
 
@@ -45,6 +50,7 @@ func (m *Master) Fork(pFile *pidfile.PidFile) error {
 
 	dataMasterTraffic, err := json.Marshal(&traffic.PbTraffics{Traffic: tfMaster})
 	if err != nil {
+		m.forkFailed(pFile, false)
 		return err
 	}
 
@@ -54,6 +60,7 @@ func (m *Master) Fork(pFile *pidfile.PidFile) error {
 	dataWorkerTraffic, err := json.Marshal(&traffic.PbTraffics{Traffic: tfWorker})
 
 	if err != nil {
+		m.forkFailed(pFile, false)
 		return err
 	}
 	dataWorkerTraffic = utils.EncodeFrame(dataWorkerTraffic)
@@ -64,6 +71,7 @@ func (m *Master) Fork(pFile *pidfile.PidFile) error {
 
 	cmd, err := process.Cmd(eosc.ProcessMaster, os.Args[1:])
 	if err != nil {
+		m.forkFailed(pFile, false)
 		return err
 	}
 	cmd.Stdout = os.Stdout
@@ -76,32 +84,59 @@ func (m *Master) Fork(pFile *pidfile.PidFile) error {
 	// 子进程的环境变量加入MASTER_CONTINUE字段，用于新的Master启动后给父Master传送中断信号
 	cmd.Env = append(os.Environ(), env.GenEnv("MASTER_CONTINUE", "1"))
 
+	if err := m.etcdServer.Suspend(); err != nil {
+		m.forkFailed(pFile, true)
+		return err
+	}
 	err = cmd.Start()
 	if err != nil {
-		log.Fatalf("Restart: Failed to launch, error: %v", err)
+		log.Errorf("Restart: Failed to launch, error: %v", err)
+		m.forkFailed(pFile, true)
 		return err
 	}
 	log.Debug("fork new process: ", cmd.Process.Pid, ":", cmd.String())
 	// check cmd
-	go waitFork(m.ctx, pFile, cmd.Process.Pid)
+	go m.waitFork(pFile, cmd)
 
 	return nil
 }
 
-func waitFork(ctx context.Context, pFile *pidfile.PidFile, pid int) {
-	t := time.NewTicker(time.Millisecond * 100)
-	defer t.Stop()
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-t.C:
-			if !pidfile.ProcessExists(pid) {
-				pFile.UnFork()
-				runningMasterForked.Stop()
-				return
-			}
-		}
+// waitFork 新进程就绪后会向本进程发送 SIGQUIT，本进程随之关闭；
+// 新进程提前退出或超时未就绪时结束新进程并恢复本进程的服务
+func (m *Master) waitFork(pFile *pidfile.PidFile, cmd *exec.Cmd) {
+	pid := cmd.Process.Pid
+	exited := make(chan struct{})
+	go func() {
+		err := cmd.Wait()
+		log.Info("forked master ", pid, " exit:", err)
+		close(exited)
+	}()
+
+	timer := time.NewTimer(forkTimeout)
+	defer timer.Stop()
+	select {
+	case <-m.ctx.Done():
+		return
+	case <-exited:
+		log.Error("forked master ", pid, " exit before ready, rollback")
+	case <-timer.C:
+		log.Error("forked master ", pid, " not ready in ", forkTimeout, ", rollback")
+		cmd.Process.Kill()
+		<-exited
 	}
+	m.forkFailed(pFile, true)
+}
 
+func (m *Master) forkFailed(pFile *pidfile.PidFile, resume bool) {
+	if err := pFile.UnFork(); err != nil {
+		log.Warn("restore pid file:", err)
+	}
+	if resume {
+		if err := m.etcdServer.Resume(); err != nil {
+			log.Error("resume etcd server:", err)
+		} else {
+			log.Info("master resume serving")
+		}
+	}
+	runningMasterForked.Stop()
 }
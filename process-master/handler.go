@@ -3,13 +3,25 @@ package process_master
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/eolinker/eosc/etcd"
+	"github.com/eolinker/eosc/process"
 )
 
+// SystemInfo 集群信息以及当前节点的 worker 进程状态
+type SystemInfo struct {
+	etcd.ClusterInfo
+	Processes []*process.ProcessInfo `json:"processes"`
+}
+
 func (m *Master) EtcdNodesHandler(w http.ResponseWriter, r *http.Request) {
 	nodes := m.etcdServer.Nodes()
 
 	json.NewEncoder(w).Encode(nodes)
 }
 func (m *Master) EtcdInfoHandler(w http.ResponseWriter, r *http.Request) {
-	json.NewEncoder(w).Encode(m.etcdServer.Status())
+	json.NewEncoder(w).Encode(&SystemInfo{
+		ClusterInfo: m.etcdServer.Status(),
+		Processes:   m.workerController.Processes(),
+	})
 }
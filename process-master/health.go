@@ -0,0 +1,86 @@
+package process_master
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/eolinker/eosc/health"
+	"github.com/eolinker/eosc/process"
+	"github.com/eolinker/eosc/process-master/extender"
+)
+
+var (
+	ErrorNoLeader = errors.New("raft has no leader")
+)
+
+// initHealth 注册 master 节点的就绪检查：raft 有 leader、admin 进程可连接、worker 进程已应用最新配置、插件全部就绪
+func (m *Master) initHealth() {
+	m.health = health.NewRegistry()
+	m.health.Register("raft", m.checkRaft)
+	m.health.Register("admin", m.checkAdmin)
+	m.health.Register("worker", m.checkWorker)
+	m.health.Register("extender", m.checkExtender)
+}
+
+func (m *Master) HealthLiveHandler(w http.ResponseWriter, r *http.Request) {
+	report := health.Live()
+	writeJson(w, report.StatusCode(), report)
+}
+
+func (m *Master) HealthReadyHandler(w http.ResponseWriter, r *http.Request) {
+	report := m.health.Run()
+	writeJson(w, report.StatusCode(), report)
+}
+
+func (m *Master) checkRaft() (string, error) {
+	for _, n := range m.etcdServer.Nodes() {
+		if n.IsLeader {
+			return fmt.Sprint("leader ", n.Name), nil
+		}
+	}
+	return "", ErrorNoLeader
+}
+
+func (m *Master) checkAdmin() (string, error) {
+	msg, err := m.adminController.Health()
+	if err != nil || msg == "not leader" {
+		return msg, err
+	}
+	if err := m.adminClient.Ping(); err != nil {
+		return "", fmt.Errorf("connect admin process:%w", err)
+	}
+	return msg, nil
+}
+
+func (m *Master) checkWorker() (string, error) {
+	running := process.StatusName(process.StatusRunning)
+	for _, p := range m.workerController.Processes() {
+		if p.Status != running {
+			return "", fmt.Errorf("worker-%d %s", p.Index, p.Status)
+		}
+	}
+	connected, pending := m.dispatcherServe.Tracker().Pending()
+	if connected < m.config.Workers {
+		return "", fmt.Errorf("%d/%d worker processes connected", connected, m.config.Workers)
+	}
+	if pending > 0 {
+		return "", fmt.Errorf("%d workers pending apply", pending)
+	}
+	return fmt.Sprint(connected, " worker processes"), nil
+}
+
+func (m *Master) checkExtender() (string, error) {
+	statuses := m.extenderManager.Statuses()
+	faults := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		if s.Status != extender.StatusSuccess {
+			faults = append(faults, fmt.Sprint(s.Name(), ":", s.Version, " ", extender.StatusName(s.Status)))
+		}
+	}
+	if len(faults) > 0 {
+		return "", errors.New(strings.Join(faults, ","))
+	}
+	return fmt.Sprint(len(statuses), " extenders"), nil
+}
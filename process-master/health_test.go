@@ -0,0 +1,72 @@
+package process_master
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/common/dispatcher"
+	"github.com/eolinker/eosc/config"
+	"github.com/eolinker/eosc/process"
+)
+
+func TestCheckWorker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	v1 := []byte(`{"v":1}`)
+	init := dispatcher.InitEvent{eosc.NamespaceWorker: {"a@service": v1}}
+	tracker := NewApplyTracker()
+	m := &Master{
+		config:           config.NConfig{Workers: 2},
+		workerController: &WorkerController{},
+		dispatcherServe:  &DispatcherServer{tracker: tracker},
+	}
+
+	steps := []struct {
+		name string
+		do   func()
+		pass bool
+	}{
+		{name: "not connected", do: func() {}},
+		{name: "one connected", do: func() { tracker.Sent("p1", init) }},
+		{name: "pending", do: func() { tracker.Sent("p2", init) }},
+		{name: "applied", do: func() {
+			tracker.Report("p1", applyResult("a@service", v1, true))
+			tracker.Report("p2", applyResult("a@service", v1, true))
+		}, pass: true},
+		{name: "process not running", do: func() {
+			m.workerController.workerProcess = []*process.ProcessController{process.NewProcessController(ctx, eosc.ProcessWorker, io.Discard)}
+		}},
+	}
+	for _, s := range steps {
+		s.do()
+		if msg, err := m.checkWorker(); (err == nil) != s.pass {
+			t.Errorf("%s: checkWorker() = %s, %v", s.name, msg, err)
+		}
+	}
+}
+
+func TestCheckAdmin(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tests := []struct {
+		name     string
+		isLeader bool
+		pass     bool
+	}{
+		{name: "not leader", isLeader: false, pass: true},
+		{name: "admin not running", isLeader: true, pass: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Master{
+				adminController: &AdminController{adminProcess: process.NewProcessController(ctx, eosc.ProcessAdmin, io.Discard), isLeader: tt.isLeader},
+				adminClient:     NewUnixClient(),
+			}
+			if msg, err := m.checkAdmin(); (err == nil) != tt.pass {
+				t.Errorf("checkAdmin() = %s, %v", msg, err)
+			}
+		})
+	}
+}
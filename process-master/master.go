@@ -12,11 +12,15 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"github.com/eolinker/eosc/etcd"
+	"github.com/eolinker/eosc/health"
+	"github.com/eolinker/eosc/metrics"
 	"github.com/eolinker/eosc/process"
 	"github.com/eolinker/eosc/process-master/extender"
 	open_api "github.com/eolinker/eosc/process-master/open-api"
 	raft_service "github.com/eolinker/eosc/process-master/raft-service"
+	"github.com/eolinker/eosc/professions"
 	"github.com/eolinker/eosc/traffic/mixl"
 	"github.com/eolinker/eosc/utils"
 	"io"
@@ -38,6 +42,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 func Process() {
@@ -85,6 +90,10 @@ type Master struct {
 	adminController  *AdminController
 	dispatcherServe  *DispatcherServer
 	adminClient      *UnixClient
+	openApiProxy     *open_api.OpenApiProxy
+	extenderManager  *extender.Manager
+	startAt          time.Time
+	health           *health.Registry
 }
 
 type MasterHandler struct {
@@ -111,6 +120,15 @@ func (m *Master) start(handler *MasterHandler, etcdServer etcd.Etcd) error {
 
 	handler.initHandler()
 
+	var initProfessions []*eosc.ProfessionConfig
+	if handler.InitProfession != nil {
+		initProfessions = handler.InitProfession()
+		// 初始 profession 的依赖异常时拒绝启动
+		if _, err := professions.Resolve(initProfessions); err != nil {
+			return fmt.Errorf("init profession:%w", err)
+		}
+	}
+
 	raftService := raft_service.NewService(func(config map[string]map[string][]byte) map[string]map[string][]byte {
 		if config == nil {
 			config = make(map[string]map[string][]byte)
@@ -119,8 +137,7 @@ func (m *Master) start(handler *MasterHandler, etcdServer etcd.Etcd) error {
 
 			if ps, has := config[eosc.NamespaceProfession]; !has || len(ps) == 0 {
 				ps = make(map[string][]byte)
-				pl := handler.InitProfession()
-				for _, p := range pl {
+				for _, p := range initProfessions {
 					data, _ := json.Marshal(p)
 					ps[p.Name] = data
 				}
@@ -130,12 +147,25 @@ func (m *Master) start(handler *MasterHandler, etcdServer etcd.Etcd) error {
 		return config
 	})
 
-	m.adminController = NewAdminConfig(raftService, process.NewProcessController(m.ctx, eosc.ProcessAdmin, m.logWriter, m.adminClient))
-	m.workerController = NewWorkerController(m.workerTraffic, m.config.Gateway, process.NewProcessController(m.ctx, eosc.ProcessWorker, m.logWriter))
+	restartPolicy := process.DefaultRestartPolicy
+	restartPolicy.MaxRestarts = *m.config.Restart.Max
+	restartPolicy.Window = time.Duration(m.config.Restart.Window) * time.Second
+
+	adminProcess := process.NewProcessController(m.ctx, eosc.ProcessAdmin, m.logWriter, m.adminClient)
+	adminProcess.SetRestartPolicy(restartPolicy)
+	m.adminController = NewAdminConfig(raftService, adminProcess)
+	workerProcess := make([]*process.ProcessController, 0, m.config.Workers)
+	for i := 0; i < m.config.Workers; i++ {
+		p := process.NewProcessController(m.ctx, eosc.ProcessWorker, m.logWriter)
+		p.SetRestartPolicy(restartPolicy)
+		p.SetDrainTimeout(time.Duration(m.config.Grace) * time.Second)
+		workerProcess = append(workerProcess, p)
+	}
+	m.workerController = NewWorkerController(m.workerTraffic, m.config.Gateway, workerProcess...)
 
 	m.dispatcherServe = NewDispatcherServer()
-	extenderManager := extender.NewManager(m.ctx, extender.GenCallbackList(m.dispatcherServe, m.workerController))
-	m.dataController = NewDataController(raftService, extenderManager, m.dispatcherServe)
+	m.extenderManager = extender.NewManager(m.ctx, extender.GenCallbackList(m.dispatcherServe, m.workerController))
+	m.dataController = NewDataController(raftService, m.extenderManager, m.dispatcherServe)
 
 	etcdServer.Watch("/", raftService)
 	etcdServer.HandlerLeader(m.adminController)
@@ -165,15 +195,32 @@ func (m *Master) Start(handler *MasterHandler) error {
 	}
 	m.adminClient = NewUnixClient()
 	m.etcdServer = etcdServer
+	// admin 启动后通过 openApiProxy 写入默认 worker，需要在 admin 启动前创建
+	openApiProxy := open_api.NewOpenApiProxy(NewEtcdSender(m.etcdServer), m.adminClient)
+	m.openApiProxy = openApiProxy
+	m.adminClient.OnStart(m.provisionDefaults)
 	err = m.start(handler, etcdServer)
 	if err != nil {
 		return err
 	}
-	openApiProxy := open_api.NewOpenApiProxy(NewEtcdSender(m.etcdServer), m.adminClient)
+	openApiProxy.ExcludeHandle(http.MethodGet, "/extender/:id/status", m.ExtenderStatusHandler)
+	openApiProxy.ExcludeHandle(http.MethodGet, "/api/:profession/:name/status", m.WorkerStatusHandler)
+	openApiProxy.SetApplyWaiter(NewClusterApplyWaiter(m.dispatcherServe.Tracker(), func() clusterNodes {
+		if m.etcdServer == nil {
+			return nil
+		}
+		return m.etcdServer
+	}))
 
 	openApiMux.Handle("/system/version", handler.VersionHandler(etcdServer))
 	openApiMux.HandleFunc("/system/info", m.EtcdInfoHandler)
 	openApiMux.HandleFunc("/system/nodes", m.EtcdNodesHandler)
+	openApiMux.HandleFunc("/system/processes", m.ProcessesHandler)
+	m.initHealth()
+	openApiMux.HandleFunc(health.PathLive, m.HealthLiveHandler)
+	openApiMux.HandleFunc(health.PathReady, m.HealthReadyHandler)
+	m.initMetrics()
+	openApiMux.Handle(metrics.Path, m.MetricsHandler())
 	openApiMux.Handle("/", openApiProxy)
 	etcdMux.Handle("/", openApiProxy) // 转发到leader 需要具体节点，所以peer上也要绑定 open api
 
@@ -265,10 +312,6 @@ func (m *Master) Wait(pFile *pidfile.PidFile) error {
 			}
 		case syscall.SIGUSR1:
 			{
-				if m.etcdServer != nil {
-					m.etcdServer.Close()
-					m.etcdServer = nil
-				}
 				log.Info("try fork new")
 				err := m.Fork(pFile) //传子进程需要的内容
 				if err != nil {
@@ -321,6 +364,7 @@ func NewMasterHandle(logWriter io.Writer, cfg config.NConfig) (*Master, error) {
 		ctx:        cancel,
 		logWriter:  logWriter,
 		config:     cfg,
+		startAt:    time.Now(),
 	}
 	var input io.Reader
 	if _, has := env.GetEnv("MASTER_CONTINUE"); has {
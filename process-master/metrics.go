@@ -0,0 +1,113 @@
+package process_master
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/log"
+	"github.com/eolinker/eosc/metrics"
+	"github.com/eolinker/eosc/process"
+	"github.com/eolinker/eosc/process-master/extender"
+	"github.com/eolinker/eosc/service"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	resyncReplay = "replay"
+	resyncFull   = "full"
+)
+
+var (
+	dispatchedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "dispatcher",
+		Name:      "events_total",
+		Help:      "Events dispatched to worker processes.",
+	}, []string{"namespace", "command"})
+
+	resyncTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "dispatcher",
+		Name:      "resync_total",
+		Help:      "Reconnected worker processes resynced by replaying events or by a full reset.",
+	}, []string{"mode"})
+
+	extenderStatusDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "extender", "status"),
+		"Extender load status, 1 for the current status of each extender.",
+		[]string{"extender", "version", "status"}, nil,
+	)
+)
+
+// initMetrics 注册只在 master 进程内有意义的指标
+func (m *Master) initMetrics() {
+	metrics.MustRegister(
+		dispatchedTotal,
+		resyncTotal,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "raft",
+			Name:      "leader",
+			Help:      "Whether this node is the raft leader.",
+		}, m.raftLeader),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "raft",
+			Name:      "term",
+			Help:      "Current raft term.",
+		}, func() float64 {
+			return float64(m.etcdServer.Term())
+		}),
+		&extenderCollector{manager: m.extenderManager},
+	)
+}
+
+func (m *Master) raftLeader() float64 {
+	if n := m.etcdServer.Info(); n != nil && n.IsLeader {
+		return 1
+	}
+	return 0
+}
+
+// MetricsHandler 汇总 master、admin 及运行中 worker 进程的指标，以 process 标签区分
+func (m *Master) MetricsHandler() http.Handler {
+	return metrics.Handler(prometheus.GathererFunc(m.gather))
+}
+
+func (m *Master) gather() ([]*dto.MetricFamily, error) {
+	gs := prometheus.Gatherers{
+		metrics.WithLabels(metrics.Gatherer(), prometheus.Labels{"process": eosc.ProcessMaster}),
+	}
+	running := process.StatusName(process.StatusRunning)
+	for _, p := range m.Processes() {
+		if p.Name == eosc.ProcessMaster || p.Pid == 0 || p.Status != running {
+			continue
+		}
+		labels := prometheus.Labels{"process": p.Name}
+		if p.Name == eosc.ProcessWorker {
+			labels["index"] = fmt.Sprint(p.Index)
+		}
+		gs = append(gs, metrics.WithLabels(metrics.Unix(service.ServerUnixAddr(p.Pid, p.Name)), labels))
+	}
+	mfs, err := gs.Gather()
+	if err != nil {
+		log.Warn("gather metrics:", err)
+	}
+	return mfs, nil
+}
+
+type extenderCollector struct {
+	manager *extender.Manager
+}
+
+func (c *extenderCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- extenderStatusDesc
+}
+
+func (c *extenderCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c.manager.Statuses() {
+		ch <- prometheus.MustNewConstMetric(extenderStatusDesc, prometheus.GaugeValue, 1, s.Name(), s.Version, extender.StatusName(s.Status))
+	}
+}
@@ -1,6 +1,7 @@
 package open_api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/eolinker/eosc/log"
@@ -10,6 +11,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 )
 
 var (
@@ -21,13 +23,30 @@ type IRaftSender interface {
 	IsLeader() (bool, []string)
 }
 
+// IApplyWaiter 等待事件在集群各节点的数据面生效，返回生效状态及失败原因
+type IApplyWaiter interface {
+	Wait(ctx context.Context, events []*open_api.EventResponse) (status string, reason string)
+}
+
+const (
+	HeaderApplyStatus = "X-Apply-Status"
+	HeaderApplyError  = "X-Apply-Error"
+	maxApplyWait      = time.Minute
+)
+
 type OpenApiProxy struct {
 	excludeRouter *httprouter.Router
 	leaderHandler http.Handler
 	raftSender    IRaftSender
+	applyWaiter   IApplyWaiter
 	pool          sync.Pool
 }
 
+// SetApplyWaiter 设置后，请求带上 wait=5s 时会等待事件在集群各节点的数据面生效，结果通过响应头返回
+func (p *OpenApiProxy) SetApplyWaiter(waiter IApplyWaiter) {
+	p.applyWaiter = waiter
+}
+
 func NewOpenApiProxy(sender IRaftSender, leaderHandler http.Handler) *OpenApiProxy {
 	p := &OpenApiProxy{
 		excludeRouter: httprouter.New(),
@@ -139,12 +158,33 @@ func (p *OpenApiProxy) doProxy(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set(k, res.Header.Get(k))
 		}
 	}
+	p.waitApply(w, r, res.Event)
 
 	w.WriteHeader(res.StatusCode)
 	w.Write(res.Data)
 
 }
 
+func (p *OpenApiProxy) waitApply(w http.ResponseWriter, r *http.Request, events []*open_api.EventResponse) {
+	if p.applyWaiter == nil || len(events) == 0 {
+		return
+	}
+	wait, err := time.ParseDuration(r.URL.Query().Get("wait"))
+	if err != nil || wait <= 0 {
+		return
+	}
+	if wait > maxApplyWait {
+		wait = maxApplyWait
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), wait)
+	defer cancel()
+	status, reason := p.applyWaiter.Wait(ctx, events)
+	w.Header().Set(HeaderApplyStatus, status)
+	if reason != "" {
+		w.Header().Set(HeaderApplyError, strings.ReplaceAll(reason, "\n", " "))
+	}
+}
+
 func (p *OpenApiProxy) doProxyToLeader(w http.ResponseWriter, org *http.Request, leaders []string) {
 	var err error
 	var response *http.Response
@@ -2,10 +2,12 @@ package process_master
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/eolinker/eosc"
 	"github.com/eolinker/eosc/common/dispatcher"
 	"github.com/eolinker/eosc/log"
 	"github.com/eolinker/eosc/process"
+	"github.com/eolinker/eosc/professions"
 	"strings"
 	"sync"
 )
@@ -43,18 +45,47 @@ func (ac *AdminController) checkExtender() {
 		return
 	}
 	extendersData, _ := ac.data.GetNamespace(eosc.NamespaceExtender)
-	newExtenders := ac.toExtends(extendersData)
-
-	for id, v := range newExtenders {
-		if ov, has := ac.lastExtenderConfig[id]; has {
-			if !strings.EqualFold(v, ov) {
-				// 存在相同插件切版本不一致，reload admin
-				ac.restart()
-				return
+	professionData, _ := ac.data.GetNamespace(eosc.NamespaceProfession)
+	if extenderNeedRestart(ac.lastExtenderConfig, ac.toExtends(extendersData), professionData) {
+		ac.restart()
+	}
+}
+
+// extenderNeedRestart 插件版本变化，或新安装的插件提供了单例 profession 默认 worker 的 driver 时需要重启 admin，
+// admin 重启后会加载插件并创建默认 worker；其他新安装的插件不需要重启
+func extenderNeedRestart(last, current map[string]string, professionData map[string][]byte) bool {
+	added := make([]string, 0)
+	for id, v := range current {
+		ov, has := last[id]
+		if !has {
+			added = append(added, id)
+			continue
+		}
+		if !strings.EqualFold(v, ov) {
+			return true
+		}
+	}
+	if len(added) == 0 {
+		return false
+	}
+	for name, data := range professionData {
+		pc := new(eosc.ProfessionConfig)
+		if err := json.Unmarshal(data, pc); err != nil {
+			log.Warnf("read profession %s:%v", name, err)
+			continue
+		}
+		for _, d := range pc.Drivers {
+			if _, has := professions.DefaultBody(pc, d); !has {
+				continue
+			}
+			for _, id := range added {
+				if strings.HasPrefix(d.Id, id+":") {
+					return true
+				}
 			}
 		}
 	}
-	return
+	return false
 }
 func (ac *AdminController) toExtends(org map[string][]byte) map[string]string {
 	tmp := make(map[string]string)
@@ -112,3 +143,23 @@ func NewAdminConfig(raftData dispatcher.IDispatchCenter, adminProcess *process.P
 	wc.registerChannel = raftData.Register(wc.doEvent)
 	return wc
 }
+
+// Process admin 进程的状态，非 leader 节点上为 stopped
+func (ac *AdminController) Process() *process.ProcessInfo {
+	return ac.adminProcess.Info()
+}
+
+// Health admin 进程只运行在 leader 节点上，非 leader 节点直接通过
+func (ac *AdminController) Health() (string, error) {
+	ac.locker.RLock()
+	isLeader := ac.isLeader
+	ac.locker.RUnlock()
+	if !isLeader {
+		return "not leader", nil
+	}
+	info := ac.adminProcess.Info()
+	if info.Status != process.StatusName(process.StatusRunning) {
+		return "", fmt.Errorf("admin process %s", info.Status)
+	}
+	return fmt.Sprint("pid ", info.Pid), nil
+}
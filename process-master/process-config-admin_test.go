@@ -0,0 +1,40 @@
+package process_master
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eolinker/eosc"
+)
+
+func TestExtenderNeedRestart(t *testing.T) {
+	output, _ := json.Marshal(&eosc.ProfessionConfig{
+		Name: "output",
+		Mod:  eosc.ProfessionConfig_Singleton,
+		Drivers: []*eosc.DriverConfig{
+			{Id: "eolinker.com:output:file", Name: "file", Default: `{"path":"/var/log"}`},
+			{Id: "eolinker.com:kafka:kafka", Name: "kafka"},
+		},
+	})
+	professionData := map[string][]byte{"output": output}
+	last := map[string]string{"eolinker.com:apinto": "v1.0.0"}
+
+	tests := []struct {
+		name    string
+		current map[string]string
+		want    bool
+	}{
+		{name: "unchanged", current: map[string]string{"eolinker.com:apinto": "v1.0.0"}, want: false},
+		{name: "version", current: map[string]string{"eolinker.com:apinto": "v1.1.0"}, want: true},
+		{name: "new without default", current: map[string]string{"eolinker.com:apinto": "v1.0.0", "eolinker.com:kafka": "v1.0.0"}, want: false},
+		{name: "new with default", current: map[string]string{"eolinker.com:apinto": "v1.0.0", "eolinker.com:output": "v1.0.0"}, want: true},
+		{name: "removed", current: map[string]string{}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extenderNeedRestart(last, tt.current, professionData); got != tt.want {
+				t.Errorf("extenderNeedRestart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,8 +12,9 @@ import (
 	"github.com/eolinker/eosc/config"
 )
 
+// WorkerController 管理当前节点的 worker 进程，多个 worker 进程共享同一组端口文件，各自独立监管
 type WorkerController struct {
-	workerProcess *process.ProcessController
+	workerProcess []*process.ProcessController
 	extends       map[string]string
 	locker        sync.Mutex
 	traffics      []*traffic.PbTraffic
@@ -24,7 +25,9 @@ type WorkerController struct {
 }
 
 func (wc *WorkerController) Stop() {
-	wc.workerProcess.Stop()
+	for _, p := range wc.workerProcess {
+		p.Stop()
+	}
 }
 func (wc *WorkerController) Update(status []*extender.Status, success bool) {
 	if success {
@@ -43,15 +46,30 @@ func (wc *WorkerController) Update(status []*extender.Status, success bool) {
 		}
 		data, _ := json.Marshal(args)
 		if wc.isRunning {
-			wc.workerProcess.TryRestart(data, wc.trafficFiles)
+			for _, p := range wc.workerProcess {
+				p.TryRestart(data, wc.trafficFiles)
+			}
 		} else {
 			wc.isRunning = true
-			wc.workerProcess.Start(data, wc.trafficFiles)
+			for _, p := range wc.workerProcess {
+				p.Start(data, wc.trafficFiles)
+			}
 		}
 	}
 }
 
-func NewWorkerController(tfd *traffic.TrafficData, listensMsg config.ListenUrl, workerProcess *process.ProcessController) *WorkerController {
+// Processes 返回所有 worker 进程的状态
+func (wc *WorkerController) Processes() []*process.ProcessInfo {
+	infos := make([]*process.ProcessInfo, 0, len(wc.workerProcess))
+	for i, p := range wc.workerProcess {
+		info := p.Info()
+		info.Index = i
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func NewWorkerController(tfd *traffic.TrafficData, listensMsg config.ListenUrl, workerProcess ...*process.ProcessController) *WorkerController {
 	traffics, files := traffic.Export(tfd, 3)
 	wc := &WorkerController{
 		traffics:      traffics,
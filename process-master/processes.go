@@ -0,0 +1,27 @@
+package process_master
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/process"
+)
+
+// Processes 当前节点 master、admin、worker 进程的状态
+func (m *Master) Processes() []*process.ProcessInfo {
+	infos := []*process.ProcessInfo{{
+		Name:   eosc.ProcessMaster,
+		Pid:    os.Getpid(),
+		Status: process.StatusName(process.StatusRunning),
+		Start:  m.startAt,
+		Uptime: time.Since(m.startAt).Truncate(time.Second).String(),
+	}}
+	infos = append(infos, m.adminController.Process())
+	return append(infos, m.workerController.Processes()...)
+}
+
+func (m *Master) ProcessesHandler(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, m.Processes())
+}
@@ -0,0 +1,66 @@
+package process_master
+
+import (
+	"sync"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/common/dispatcher"
+)
+
+const replayLogSize = 1024
+
+// sequenceEvent 由 DispatcherServer 按分发顺序分配序号的事件
+type sequenceEvent struct {
+	dispatcher.IEvent
+	sequence uint64
+}
+
+func (e *sequenceEvent) Sequence() uint64 {
+	return e.sequence
+}
+
+// replayLog 保存最近分发的事件，worker 重连时补发其缺少的部分
+// 全量重置的事件不保存，重置之前的序号无法再补发
+type replayLog struct {
+	locker sync.RWMutex
+	size   int
+	events []*sequenceEvent
+	// first 可以补发的最小序号
+	first uint64
+}
+
+func newReplayLog(size int) *replayLog {
+	return &replayLog{size: size, first: 1}
+}
+
+func (l *replayLog) append(e *sequenceEvent) {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+	switch e.Event() {
+	case eosc.EventInit, eosc.EventReset:
+		l.events = l.events[:0]
+		l.first = e.sequence + 1
+		return
+	}
+	if len(l.events) >= l.size {
+		l.events = append(l.events[:0], l.events[1:]...)
+		l.first = l.events[0].sequence
+	}
+	l.events = append(l.events, e)
+}
+
+// since 返回序号在 (after, upto] 内的事件，日志不能完整覆盖时返回 false
+func (l *replayLog) since(after, upto uint64) ([]*sequenceEvent, bool) {
+	l.locker.RLock()
+	defer l.locker.RUnlock()
+	if after > upto || after+1 < l.first {
+		return nil, false
+	}
+	events := make([]*sequenceEvent, 0, upto-after)
+	for _, e := range l.events {
+		if e.sequence > after && e.sequence <= upto {
+			events = append(events, e)
+		}
+	}
+	return events, uint64(len(events)) == upto-after
+}
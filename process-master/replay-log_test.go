@@ -0,0 +1,44 @@
+package process_master
+
+import (
+	"testing"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/common/dispatcher"
+)
+
+type testEvent struct {
+	dispatcher.InitEvent
+	command string
+}
+
+func (e testEvent) Event() string {
+	return e.command
+}
+
+func TestReplayLog(t *testing.T) {
+	l := newReplayLog(3)
+	for i, command := range []string{eosc.EventSet, eosc.EventReset, eosc.EventSet, eosc.EventDel, eosc.EventSet, eosc.EventSet} {
+		l.append(&sequenceEvent{IEvent: testEvent{command: command}, sequence: uint64(i + 1)})
+	}
+	tests := []struct {
+		after, upto uint64
+		want        int
+		ok          bool
+	}{
+		{after: 6, upto: 6, want: 0, ok: true},
+		{after: 4, upto: 6, want: 2, ok: true},
+		{after: 3, upto: 5, want: 2, ok: true},
+		// 序号 3 已被挤出日志
+		{after: 2, upto: 6, ok: false},
+		// 重置之前的序号只能全量同步
+		{after: 1, upto: 6, ok: false},
+		{after: 7, upto: 6, ok: false},
+	}
+	for _, tt := range tests {
+		events, ok := l.since(tt.after, tt.upto)
+		if ok != tt.ok || (ok && len(events) != tt.want) {
+			t.Errorf("since(%d,%d) = %d,%v, want %d,%v", tt.after, tt.upto, len(events), ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package process_master
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/log"
+	"github.com/julienschmidt/httprouter"
+)
+
+// WorkerStatusHandler 返回 worker 配置在集群各节点数据面的应用状态：applied/failed/pending
+// 带上 wait=5s 时本节点会等待 pending 结束；node=local 时只返回当前节点的状态，
+// 同时带上 hash、command 时只有与之一致的配置才算生效，供 open api 的 wait 参数等待其他节点
+func (m *Master) WorkerStatusHandler(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	id, ok := eosc.ToWorkerId(params.ByName("name"), params.ByName("profession"))
+	if !ok {
+		writeJson(w, http.StatusBadRequest, fmt.Sprintf("invalid worker:%s", params.ByName("name")))
+		return
+	}
+	query := r.URL.Query()
+	wait, _ := time.ParseDuration(query.Get("wait"))
+	if query.Get("node") == nodeLocal {
+		if hash, err := strconv.ParseUint(query.Get("hash"), 10, 64); err == nil {
+			writeJson(w, http.StatusOK, m.localApplyStatus(r.Context(), id, hash, query.Get("command"), wait))
+			return
+		}
+		writeJson(w, http.StatusOK, m.localWorkerStatus(r.Context(), id, wait))
+		return
+	}
+
+	self := m.etcdServer.Info()
+	nodes := m.etcdServer.Nodes()
+	statuses := make([]*ApplyState, 0, len(nodes))
+	for _, n := range nodes {
+		if self != nil && n.ID == self.ID {
+			s := m.localWorkerStatus(r.Context(), id, wait)
+			s.Node = n.Name
+			statuses = append(statuses, s)
+			continue
+		}
+		s := new(ApplyState)
+		uri := fmt.Sprintf("%s?node=%s&wait=%s", r.URL.Path, nodeLocal, url.QueryEscape(query.Get("wait")))
+		if err := requestNode(n.Admin, uri, s); err != nil {
+			log.Warnf("get worker %s status from %s:%v", id, n.Name, err)
+			s = &ApplyState{Id: id, Status: ApplyUnknown, Error: err.Error()}
+		}
+		s.Node = n.Name
+		statuses = append(statuses, s)
+	}
+	writeJson(w, http.StatusOK, statuses)
+}
+
+func (m *Master) localWorkerStatus(ctx context.Context, id string, wait time.Duration) *ApplyState {
+	tracker := m.dispatcherServe.Tracker()
+	var s *ApplyState
+	if wait > 0 {
+		ctx, cancel := context.WithTimeout(ctx, wait)
+		s = tracker.WaitState(ctx, id)
+		cancel()
+	} else {
+		s = tracker.Get(id)
+	}
+	if info := m.etcdServer.Info(); info != nil {
+		s.Node = info.Name
+	}
+	return s
+}
+
+func (m *Master) localApplyStatus(ctx context.Context, id string, hash uint64, command string, wait time.Duration) *ApplyState {
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	s := m.dispatcherServe.Tracker().WaitApplied(ctx, id, hash, command)
+	if info := m.etcdServer.Info(); info != nil {
+		s.Node = info.Name
+	}
+	return s
+}
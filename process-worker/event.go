@@ -9,6 +9,7 @@ import (
 
 	"github.com/eolinker/eosc"
 	"github.com/eolinker/eosc/log"
+	"github.com/eolinker/eosc/service"
 	"github.com/eolinker/eosc/variable"
 )
 
@@ -42,7 +43,7 @@ func (ws *WorkerServer) setEvent(namespace string, key string, data []byte) erro
 				return err
 			}
 
-			return ws.workers.Set(w.Id, w.Profession, w.Name, w.Driver, w.Body, ws.variableManager)
+			return ws.workers.Set(w.Id, w.Profession, w.Name, w.Driver, w.Schema, w.Body, ws.variableManager)
 		}
 	case eosc.NamespaceVariable:
 		{
@@ -97,18 +98,20 @@ func (ws *WorkerServer) delEvent(namespace string, key string) error {
 	}
 }
 
-func (ws *WorkerServer) resetEvent(data []byte) error {
+// resetEvent 重置所有配置，返回每个worker的应用结果
+func (ws *WorkerServer) resetEvent(data []byte) ([]*service.ApplyResult, error) {
 	eventData := make(map[string]map[string][]byte)
 	if len(data) > 0 {
 		err := json.Unmarshal(data, &eventData)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	pc := make([]*eosc.ProfessionConfig, 0)
 	wc := make([]*eosc.WorkerConfig, 0)
 	settings := make([]*eosc.WorkerConfig, 0)
+	results := make(map[string]*service.ApplyResult)
 	for namespace, config := range eventData {
 
 		switch namespace {
@@ -125,12 +128,15 @@ func (ws *WorkerServer) resetEvent(data []byte) error {
 			}
 		case eosc.NamespaceWorker:
 			{
-				for _, c := range config {
+				for key, c := range config {
 					w := new(eosc.WorkerConfig)
 					err := json.Unmarshal(c, w)
+					result := newApplyResult(eosc.NamespaceWorker, key, eosc.EventSet, c, err)
 					if err != nil {
+						results[key] = result
 						continue
 					}
+					results[w.Id] = result
 					log.Debug("init read worker:", w.Profession, ":", w.Name)
 					if w.Profession == "setting" {
 						settings = append(settings, w)
@@ -154,7 +160,10 @@ func (ws *WorkerServer) resetEvent(data []byte) error {
 		}
 	}
 
-	ws.professionManager.Reset(pc)
+	if err := ws.professionManager.Reset(pc); err != nil {
+		// admin 已拒绝无法解析的依赖，这里只记录，worker 按名称顺序创建
+		log.Error("reset profession:", err)
+	}
 	ws.onceInit.Do(func() {
 		for _, h := range ws.initHandler {
 			h()
@@ -165,9 +174,16 @@ func (ws *WorkerServer) resetEvent(data []byte) error {
 		err := ws.settings.SettingWorker(w.Name, w.Body, ws.variableManager)
 		if err != nil {
 			log.Warn("set setting :", err)
+			applyFailed(results[w.Id], err)
 		}
 	}
-	ws.workers.Reset(wc, ws.variableManager)
+	for id, err := range ws.workers.Reset(wc, ws.variableManager) {
+		applyFailed(results[id], err)
+	}
 
-	return nil
+	rs := make([]*service.ApplyResult, 0, len(results))
+	for _, r := range results {
+		rs = append(rs, r)
+	}
+	return rs, nil
 }
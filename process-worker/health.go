@@ -0,0 +1,33 @@
+package process_worker
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/eolinker/eosc/health"
+	"github.com/eolinker/eosc/traffic"
+)
+
+var (
+	errorWaitConfig = errors.New("waiting for config from master")
+	errorDraining   = errors.New("worker is draining")
+)
+
+// registerHealth 数据面端口上的就绪检查：已应用 master 下发的配置，且没有在排空
+func (w *ProcessWorker) registerHealth() {
+	health.Register("config", func() (string, error) {
+		select {
+		case <-w.server.Ready():
+			return "applied", nil
+		default:
+			return "", errorWaitConfig
+		}
+	})
+	health.Register("drain", func() (string, error) {
+		if atomic.LoadInt32(&w.draining) == 1 {
+			return "", fmt.Errorf("%w, active connections:%d", errorDraining, traffic.Active())
+		}
+		return "", nil
+	})
+}
@@ -0,0 +1,65 @@
+package process_worker
+
+import (
+	"net/http"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/eolinker/eosc"
+	grpc_unixsocket "github.com/eolinker/eosc/grpc-unixsocket"
+	"github.com/eolinker/eosc/log"
+	"github.com/eolinker/eosc/metrics"
+	"github.com/eolinker/eosc/service"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	applyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "worker",
+		Name:      "apply_duration_seconds",
+		Help:      "Time spent applying config events in the worker process.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"command"})
+	applyFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "worker",
+		Name:      "apply_failures_total",
+		Help:      "Config items failed to apply in the worker process.",
+	}, []string{"namespace", "command"})
+)
+
+func init() {
+	metrics.MustRegister(applyDuration, applyFailures)
+}
+
+// observeApply 记录一次事件的应用耗时及其中失败的配置项
+func observeApply(command string, start time.Time, results ...*service.ApplyResult) {
+	applyDuration.WithLabelValues(command).Observe(time.Since(start).Seconds())
+	for _, r := range results {
+		if !r.Success {
+			applyFailures.WithLabelValues(r.Namespace, command).Inc()
+		}
+	}
+}
+
+// serveMetrics 在 unix socket 上输出当前进程的指标，由 master 汇总
+func serveMetrics() (*http.Server, error) {
+	addr := service.ServerUnixAddr(os.Getpid(), eosc.ProcessWorker)
+	syscall.Unlink(addr)
+	l, err := grpc_unixsocket.Listener(addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(metrics.Path, metrics.Handler(metrics.Gatherer()))
+	srv := &http.Server{Handler: mux}
+	go func() {
+		err := srv.Serve(l)
+		if err != nil && err != http.ErrServerClosed {
+			log.Info("metrics server error: ", err)
+		}
+	}()
+	return srv, nil
+}
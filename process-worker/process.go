@@ -9,14 +9,19 @@
 package process_worker
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/eolinker/eosc/config"
 
 	"github.com/eolinker/eosc/process"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/eolinker/eosc/extends"
 
@@ -28,10 +33,13 @@ import (
 	"github.com/eolinker/eosc"
 	"github.com/eolinker/eosc/common/bean"
 
+	"github.com/eolinker/eosc/extends/remote"
 	"github.com/eolinker/eosc/log"
 	"github.com/eolinker/eosc/traffic"
 )
 
+const readyTimeout = time.Second * 10
+
 func Process() {
 
 	utils.InitStdTransport(eosc.ProcessWorker)
@@ -47,6 +55,12 @@ func Process() {
 	}
 
 	w.Start()
+	// 新进程应用完配置后才告知 master 就绪，master 随后开始排空旧进程
+	select {
+	case <-w.server.Ready():
+	case <-time.After(readyTimeout):
+		log.Warn("worker wait init config timeout")
+	}
 	writeOutput(process.StatusRunning, "")
 
 	w.wait()
@@ -67,15 +81,24 @@ func writeOutput(status int, msg string) {
 type ProcessWorker struct {
 	tf traffic.ITraffic
 
-	once   sync.Once
-	server *WorkerServer
+	once     sync.Once
+	server   *WorkerServer
+	metrics  *http.Server
+	draining int32
 }
 
 func (w *ProcessWorker) wait() {
 	sigc := make(chan os.Signal, 1)
 	signal.Notify(sigc, os.Interrupt, os.Kill, syscall.SIGQUIT, syscall.SIGUSR1, syscall.SIGUSR2)
+	var drained chan struct{}
 	for {
-		sig := <-sigc
+		var sig os.Signal
+		select {
+		case sig = <-sigc:
+		case <-drained:
+			w.close()
+			return
+		}
 		log.Infof("Caught signal pid:%d ppid:%d signal %s: .\n", os.Getpid(), os.Getppid(), sig.String())
 		switch sig {
 		case os.Interrupt, os.Kill:
@@ -92,6 +115,14 @@ func (w *ProcessWorker) wait() {
 			{
 				w.close()
 			}
+		case syscall.SIGUSR2:
+			{
+				// master 要求排空：停止接收新连接，已有请求处理完后退出
+				if drained == nil {
+					drained = make(chan struct{})
+					go w.drain(drained)
+				}
+			}
 		default:
 			continue
 		}
@@ -99,6 +130,37 @@ func (w *ProcessWorker) wait() {
 
 }
 
+// drain 关闭当前进程持有的端口并等待已有连接结束，期间定时向 master 回写剩余连接数
+func (w *ProcessWorker) drain(done chan struct{}) {
+	defer close(done)
+	atomic.StoreInt32(&w.draining, 1)
+	start := time.Now()
+	log.Info("worker start draining, active connections:", traffic.Active())
+	writeOutput(process.StatusDraining, fmt.Sprint("active:", traffic.Active()))
+	w.tf.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				active := traffic.Active()
+				log.Info("worker draining, active connections:", active)
+				writeOutput(process.StatusDraining, fmt.Sprint("active:", active))
+			}
+		}
+	}()
+	if err := traffic.Drain(ctx); err != nil {
+		log.Warn("worker drain:", err)
+	}
+	log.Info("worker drained in ", time.Since(start).Truncate(time.Millisecond))
+}
+
 // NewProcessWorker 创建新的worker进程
 // 启动时通过stdin传输配置信息
 func NewProcessWorker(arg *service.ProcessLoadArg) (*ProcessWorker, error) {
@@ -126,6 +188,11 @@ func NewProcessWorker(arg *service.ProcessLoadArg) (*ProcessWorker, error) {
 		server: server,
 		tf:     tf,
 	}
+	w.registerHealth()
+	w.metrics, err = serveMetrics()
+	if err != nil {
+		log.Warn("serve metrics:", err)
+	}
 
 	return w, nil
 }
@@ -134,6 +201,10 @@ func (w *ProcessWorker) close() {
 	w.once.Do(func() {
 		w.tf.Close()
 		w.server.Stop()
+		if w.metrics != nil {
+			w.metrics.Close()
+		}
+		remote.CloseAll()
 	})
 }
 
@@ -0,0 +1,44 @@
+package process_worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/eolinker/eosc/log"
+	"github.com/eolinker/eosc/service"
+)
+
+const reportTimeout = time.Second * 3
+
+func newApplyResult(namespace, key, command string, data []byte, err error) *service.ApplyResult {
+	r := &service.ApplyResult{
+		Namespace: namespace,
+		Key:       key,
+		Command:   command,
+		Hash:      service.ApplyHash(data),
+		Success:   true,
+	}
+	applyFailed(r, err)
+	return r
+}
+
+func applyFailed(r *service.ApplyResult, err error) {
+	if r == nil || err == nil {
+		return
+	}
+	r.Success = false
+	r.Error = err.Error()
+}
+
+// report 将配置在当前 worker 进程的应用结果回报给 master
+func report(client service.MasterDispatcherClient, results ...*service.ApplyResult) {
+	if len(results) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+	_, err := client.Report(service.WithProcess(ctx), &service.ApplyReport{Results: results})
+	if err != nil {
+		log.Warn("report apply result to master:", err)
+	}
+}
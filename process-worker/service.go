@@ -2,9 +2,11 @@ package process_worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/eolinker/eosc/common/bean"
@@ -37,6 +39,11 @@ type WorkerServer struct {
 	masterPid         int
 	onceInit          sync.Once
 	initHandler       []func()
+	readyOnce         sync.Once
+	ready             chan struct{}
+	// epoch、sequence 最后应用的事件，重连时 master 据此补发缺少的事件
+	epoch    uint64
+	sequence uint64
 }
 
 func NewWorkerServer(masterPid int, extends extends.IExtenderRegister, initHandlers ...func()) (*WorkerServer, error) {
@@ -50,6 +57,7 @@ func NewWorkerServer(masterPid int, extends extends.IExtenderRegister, initHandl
 		initHandler:       initHandlers,
 		variableManager:   variable.NewVariables(nil),
 		settings:          setting.GetSettings(),
+		ready:             make(chan struct{}),
 	}
 
 	ws.workers = workers.NewWorkerManager(ws.professionManager)
@@ -59,6 +67,17 @@ func NewWorkerServer(masterPid int, extends extends.IExtenderRegister, initHandl
 	return ws, nil
 }
 
+// Ready 首次从 master 同步的配置应用完成后关闭
+func (ws *WorkerServer) Ready() <-chan struct{} {
+	return ws.ready
+}
+
+func (ws *WorkerServer) markReady() {
+	ws.readyOnce.Do(func() {
+		close(ws.ready)
+	})
+}
+
 func (ws *WorkerServer) Stop() {
 	ws.cancel()
 }
@@ -104,7 +123,10 @@ func (ws *WorkerServer) createClient() (*grpc.ClientConn, service.MasterDispatch
 	}
 
 	client := service.NewMasterDispatcherClient(conn)
-	c, err := client.Listen(ws.ctx, &service.EmptyRequest{})
+	c, err := client.Listen(service.WithProcess(ws.ctx), &service.ListenRequest{
+		Epoch:    atomic.LoadUint64(&ws.epoch),
+		Sequence: atomic.LoadUint64(&ws.sequence),
+	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("listen master service error: %w,pid: %d\n", err, ws.masterPid)
 	}
@@ -114,6 +136,7 @@ func (ws *WorkerServer) createClient() (*grpc.ClientConn, service.MasterDispatch
 func (ws *WorkerServer) listen(conn *grpc.ClientConn, c service.MasterDispatcher_ListenClient) {
 	log.Debug("start listen")
 	defer conn.Close()
+	reporter := service.NewMasterDispatcherClient(conn)
 	for {
 		event, err := c.Recv()
 		log.Debug("recv:", err)
@@ -126,24 +149,48 @@ func (ws *WorkerServer) listen(conn *grpc.ClientConn, c service.MasterDispatcher
 			return
 		}
 		log.Debug("recv:", event.String())
+		start := time.Now()
 		switch event.Command {
 		case eosc.EventInit, eosc.EventReset:
 			{
-				err := ws.resetEvent(event.Data)
+				results, err := ws.resetEvent(event.Data)
+				ws.markReady()
 				if err != nil {
 					log.Error("reset server error: ", err)
+					observeApply(event.Command, start, newApplyResult("", "", event.Command, nil, err))
 					continue
 				}
+				observeApply(event.Command, start, results...)
+				report(reporter, results...)
 			}
 		case eosc.EventSet:
 			{
-				ws.setEvent(event.Namespace, event.Key, event.Data)
+				err := ws.setEvent(event.Namespace, event.Key, event.Data)
+				if err != nil {
+					log.Errorf("set %s %s:%v", event.Namespace, event.Key, err)
+				}
+				result := newApplyResult(event.Namespace, event.Key, event.Command, event.Data, err)
+				observeApply(event.Command, start, result)
+				report(reporter, result)
 			}
 		case eosc.EventDel:
 			{
-				ws.delEvent(event.Namespace, event.Key)
+				err := ws.delEvent(event.Namespace, event.Key)
+				if errors.Is(err, eosc.ErrorWorkerNotExits) {
+					err = nil
+				}
+				if err != nil {
+					log.Errorf("delete %s %s:%v", event.Namespace, event.Key, err)
+				}
+				result := newApplyResult(event.Namespace, event.Key, event.Command, event.Data, err)
+				observeApply(event.Command, start, result)
+				report(reporter, result)
 			}
 		}
+		if event.Sequence > 0 {
+			atomic.StoreUint64(&ws.epoch, event.Epoch)
+			atomic.StoreUint64(&ws.sequence, event.Sequence)
+		}
 	}
 	log.Debug("stop listen")
 }
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"github.com/eolinker/eosc/professions"
 	"github.com/eolinker/eosc/utils/config"
+	"github.com/eolinker/eosc/utils/dag"
 	"sync"
+	"time"
 
 	//port_reqiure "github.com/eolinker/eosc/common/port-reqiure"
 
@@ -15,22 +17,26 @@ import (
 type IWorkers interface {
 	eosc.IWorkers
 	Del(id string) error
-	Set(id, profession, name, driverName string, body []byte, variable eosc.IVariable) error
+	Set(id, profession, name, driverName string, schema int32, body []byte, variable eosc.IVariable) error
 	Update(id string, variable eosc.IVariable) error
-	Reset(wdl []*eosc.WorkerConfig, variable eosc.IVariable) error
+	Reset(wdl []*eosc.WorkerConfig, variable eosc.IVariable) map[string]error
 }
 
 type ConfigCache struct {
 	profession string
 	name       string
 	driver     string
+	schema     int32
 	config     []byte
+	// state 最后一次生效的配置与依赖
+	state *eosc.WorkerState
 }
 
 var _ IWorkers = (*Workers)(nil)
 
 type Workers struct {
 	locker      sync.Mutex
+	storeLocker sync.Mutex
 	professions professions.IProfessions
 	variables   eosc.IVariable
 	data        *WorkerDatas
@@ -44,7 +50,7 @@ func (wm *Workers) Update(id string, variable eosc.IVariable) error {
 	if !has {
 		return nil
 	}
-	return wm.set(id, con.profession, con.name, con.driver, con.config, variable)
+	return wm.set(id, con.profession, con.name, con.driver, con.schema, con.config, variable)
 }
 
 func (wm *Workers) Del(id string) error {
@@ -87,7 +93,8 @@ func NewWorkerManager(profession professions.IProfessions) *Workers {
 	}
 }
 
-func (wm *Workers) Reset(wdl []*eosc.WorkerConfig, variable eosc.IVariable) error {
+// Reset 按配置中的依赖关系并发重建所有 worker，被依赖的 worker 先构建，返回创建失败的 worker
+func (wm *Workers) Reset(wdl []*eosc.WorkerConfig, variable eosc.IVariable) map[string]error {
 
 	ps := wm.professions.Sort()
 
@@ -99,81 +106,169 @@ func (wm *Workers) Reset(wdl []*eosc.WorkerConfig, variable eosc.IVariable) erro
 	wm.locker.Lock()
 	defer wm.locker.Unlock()
 	wm.variables = variable
+	oldConfigs := wm.configs
 	wm.configs = make(map[string]*ConfigCache)
 	olddata := wm.data
 	wm.data = NewTypedWorkers()
 
 	log.Debug("worker init... size is ", len(wdl))
+	start := time.Now()
+	failed := make(map[string]error)
+	nodes := make([]*dag.Node, 0, len(wdl))
+	parsed := make(map[string]*workerConfig, len(wdl))
 	for _, p := range ps {
-		log.Debug("init profession:", p.Name)
 		for _, wd := range pm[p.Name] {
 			old, has := olddata.Del(wd.Id)
 			if has {
 				wm.data.Set(wd.Id, old)
 			}
 			log.Debug("init set:", wd.Id, " ", wd.Profession, " ", wd.Name, " ", wd.Driver, " ", string(wd.Body))
-			if err := wm.set(wd.Id, wd.Profession, wd.Name, wd.Driver, wd.Body, variable); err != nil {
+			c, err := wm.parse(wd.Id, wd.Profession, wd.Name, wd.Driver, wd.Schema, wd.Body, variable)
+			if err != nil {
 				log.Error("init set worker: ", err)
+				failed[wd.Id] = err
 				continue
 			}
+			if has {
+				c.prev = oldConfigs[wd.Id]
+			}
+			parsed[wd.Id] = c
+			nodes = append(nodes, &dag.Node{Id: wd.Id, Requires: config.DriverRequires(c.driver, c.conf)})
+		}
+	}
+	results := dag.Run(nodes, dag.DefaultConcurrency, func(id string) error {
+		return wm.apply(parsed[id])
+	})
+	// 按依赖顺序保存，结果与并发的完成顺序无关
+	data := NewTypedWorkers()
+	for _, r := range results {
+		if r.Err != nil {
+			log.Errorf("init set worker %s in %s: %v", r.Id, r.Spend, r.Err)
+			failed[r.Id] = r.Err
+		} else {
+			log.Debug("init worker ", r.Id, " in ", r.Spend)
+		}
+		if w, has := wm.data.Get(r.Id); has {
+			data.Set(r.Id, w)
+		}
+	}
+	// 新配置解析失败的 worker 保留原有实例，与单个 Set 失败时一致
+	for _, wd := range wdl {
+		if _, has := parsed[wd.Id]; has {
+			continue
+		}
+		if w, has := wm.data.Get(wd.Id); has {
+			data.Set(wd.Id, w)
+			if c, has := oldConfigs[wd.Id]; has {
+				wm.configs[wd.Id] = c
+			}
 		}
 	}
+	wm.data = data
+	log.Info("worker init ", len(wdl), " workers in ", time.Since(start), ", failed ", len(failed))
+
 	for _, ov := range olddata.All() {
 		variable.RemoveRequire(ov.Id())
 		ov.Stop()
 	}
-	return nil
+	return failed
 }
 
-func (wm *Workers) Set(id, profession, name, driverName string, body []byte, variable eosc.IVariable) error {
+func (wm *Workers) Set(id, profession, name, driverName string, schema int32, body []byte, variable eosc.IVariable) error {
 	wm.locker.Lock()
 	defer wm.locker.Unlock()
 
-	return wm.set(id, profession, name, driverName, body, variable)
+	return wm.set(id, profession, name, driverName, schema, body, variable)
+}
+
+// workerConfig 解析后待应用的 worker 配置
+type workerConfig struct {
+	id           string
+	cache        *ConfigCache
+	driver       eosc.IExtenderDriver
+	conf         interface{}
+	useVariables []string
+	// prev 已有 worker 上一次的配置
+	prev *ConfigCache
+}
+
+func (wm *Workers) set(id, profession, name, driverName string, schema int32, body []byte, variable eosc.IVariable) error {
+	c, err := wm.parse(id, profession, name, driverName, schema, body, variable)
+	if err != nil {
+		return err
+	}
+	c.prev = wm.configs[id]
+	return wm.apply(c)
 }
 
-func (wm *Workers) set(id, profession, name, driverName string, body []byte, variable eosc.IVariable) error {
+func (wm *Workers) parse(id, profession, name, driverName string, schema int32, body []byte, variable eosc.IVariable) (*workerConfig, error) {
 	log.Debug("set:", id, ",", profession, ",", name, ",", driverName)
 	p, has := wm.professions.Get(profession)
 	if !has {
-		return fmt.Errorf("%s:%w", profession, eosc.ErrorProfessionNotExist)
+		return nil, fmt.Errorf("%s:%w", profession, eosc.ErrorProfessionNotExist)
 	}
 	driver, has := p.GetDriver(driverName)
 	if !has {
-		return fmt.Errorf("%s,%w", driverName, eosc.ErrorDriverNotExist)
+		return nil, fmt.Errorf("%s,%w", driverName, eosc.ErrorDriverNotExist)
+	}
+	// 插件升级后 admin 写回前，raft 中仍是旧版本的配置
+	body, migrated, err := eosc.MigrateConfig(driver, int(schema), body)
+	if err != nil {
+		return nil, fmt.Errorf("worker %s:%w", id, err)
+	}
+	if migrated {
+		schema = int32(eosc.SchemaVersion(driver))
 	}
 	conf, useVariables, err := variable.Unmarshal(body, driver.ConfigType())
 	if err != nil {
-		return fmt.Errorf("worker unmarshal error:%s", err)
+		return nil, fmt.Errorf("worker unmarshal error:%s", err)
 	}
+	return &workerConfig{
+		id: id,
+		cache: &ConfigCache{
+			profession: profession,
+			name:       name,
+			driver:     driverName,
+			schema:     schema,
+			config:     body,
+		},
+		driver:       driver,
+		conf:         conf,
+		useVariables: useVariables,
+	}, nil
+}
 
-	requires, err := config.CheckConfig(conf, wm)
+// apply 创建或重置 worker，Reset 时会被并发调用
+func (wm *Workers) apply(c *workerConfig) error {
+	id := c.id
+	requires, err := config.CheckDriverConfig(c.driver, c.conf, wm)
 	if err != nil {
 		return err
 	}
-	if dc, ok := driver.(eosc.IExtenderConfigChecker); ok {
-		if e := dc.Check(conf, requires); err != nil {
+	if dc, ok := c.driver.(eosc.IExtenderConfigChecker); ok {
+		if e := dc.Check(c.conf, requires); err != nil {
 			return e
 		}
 	}
 	o, has := wm.data.Get(id)
 	if has {
-
-		e := o.Reset(conf, requires)
+		var prev *eosc.WorkerState
+		if c.prev != nil && c.prev.driver == c.cache.driver {
+			prev = c.prev.state
+		}
+		state, reset, e := eosc.ResetWorker(o, prev, c.conf, requires)
 		if e != nil {
 			return e
 		}
-		wm.variables.SetVariablesById(id, useVariables)
-		wm.configs[id] = &ConfigCache{
-			profession: profession,
-			name:       name,
-			driver:     driverName,
-			config:     body,
+		if !reset {
+			log.Debug("worker-data skip reset, nothing changed:", id)
 		}
+		c.cache.state = state
+		wm.store(c, nil)
 		return nil
 	}
 	// create
-	worker, err := driver.Create(id, name, conf, requires)
+	worker, err := c.driver.Create(id, c.cache.name, c.conf, requires)
 	if err != nil {
 		log.Warn("worker-data set worker create:", err)
 		return err
@@ -185,14 +280,18 @@ func (wm *Workers) set(id, profession, name, driverName string, body []byte, var
 	}
 
 	// store
-	wm.data.Set(id, worker)
-	wm.variables.SetVariablesById(id, useVariables)
-	wm.configs[id] = &ConfigCache{
-		profession: profession,
-		name:       name,
-		driver:     driverName,
-		config:     body,
-	}
+	c.cache.state = eosc.NewWorkerState(c.conf, requires)
+	wm.store(c, worker)
 	log.Debug("worker-data set worker done:", id)
 	return nil
 }
+
+func (wm *Workers) store(c *workerConfig, worker eosc.IWorker) {
+	wm.storeLocker.Lock()
+	defer wm.storeLocker.Unlock()
+	if worker != nil {
+		wm.data.Set(c.id, worker)
+	}
+	wm.variables.SetVariablesById(c.id, c.useVariables)
+	wm.configs[c.id] = c.cache
+}
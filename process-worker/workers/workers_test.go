@@ -0,0 +1,170 @@
+package workers
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/eolinker/eosc"
+	"github.com/eolinker/eosc/professions"
+	"github.com/eolinker/eosc/variable"
+)
+
+type testConfig struct {
+	Target eosc.RequireId `json:"target" skill:"test" required:"false"`
+}
+
+// testDriver 记录 worker 的创建顺序
+type testDriver struct {
+	locker  sync.Mutex
+	created []string
+}
+
+func (d *testDriver) ConfigType() reflect.Type {
+	return reflect.TypeOf((*testConfig)(nil))
+}
+
+func (d *testDriver) CreateWorker(id string) *testWorker {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	d.created = append(d.created, id)
+	return &testWorker{id: id}
+}
+
+type testExtenderDriver struct {
+	*testDriver
+}
+
+func (d testExtenderDriver) Create(id, name string, v interface{}, workers map[eosc.RequireId]eosc.IWorker) (eosc.IWorker, error) {
+	return d.CreateWorker(id), nil
+}
+
+type testFactory struct {
+	driver *testDriver
+}
+
+func (f testFactory) Render() interface{} {
+	return nil
+}
+
+func (f testFactory) Create(profession string, name string, label string, desc string, params map[string]interface{}) (eosc.IExtenderDriver, error) {
+	return testExtenderDriver{f.driver}, nil
+}
+
+type testExtenders struct {
+	driver *testDriver
+}
+
+func (e testExtenders) GetDriver(name string) (eosc.IExtenderDriverFactory, bool) {
+	return testFactory{driver: e.driver}, true
+}
+
+type testWorker struct {
+	locker  sync.Mutex
+	id      string
+	stopped bool
+}
+
+func (w *testWorker) Id() string {
+	return w.id
+}
+
+func (w *testWorker) Start() error {
+	return nil
+}
+
+func (w *testWorker) Reset(conf interface{}, workers map[eosc.RequireId]eosc.IWorker) error {
+	return nil
+}
+
+func (w *testWorker) Stop() error {
+	w.locker.Lock()
+	defer w.locker.Unlock()
+	w.stopped = true
+	return nil
+}
+
+func (w *testWorker) CheckSkill(skill string) bool {
+	return skill == "test"
+}
+
+func newTestWorkers() (*Workers, *testDriver) {
+	d := &testDriver{}
+	ps := professions.NewProfessions(testExtenders{driver: d})
+	ps.Reset([]*eosc.ProfessionConfig{{
+		Name:    "service",
+		Drivers: []*eosc.DriverConfig{{Id: "test:test", Name: "test"}},
+	}})
+	return NewWorkerManager(ps), d
+}
+
+func testWorkerConfig(name string, body string) *eosc.WorkerConfig {
+	return &eosc.WorkerConfig{
+		Id:         fmt.Sprint(name, "@service"),
+		Profession: "service",
+		Name:       name,
+		Driver:     "test",
+		Body:       []byte(body),
+	}
+}
+
+func TestResetOrder(t *testing.T) {
+	wm, d := newTestWorkers()
+	wdl := []*eosc.WorkerConfig{
+		testWorkerConfig("a", `{"target":"b@service"}`),
+		testWorkerConfig("b", `{"target":"c@service"}`),
+		testWorkerConfig("c", `{}`),
+	}
+	for i := 0; i < 20; i++ {
+		wdl = append(wdl, testWorkerConfig(fmt.Sprint("n", i), `{"target":"c@service"}`))
+	}
+	if failed := wm.Reset(wdl, variable.NewVariables(nil)); len(failed) > 0 {
+		t.Fatal("Reset() failed:", failed)
+	}
+	index := make(map[string]int)
+	for i, id := range d.created {
+		index[id] = i
+	}
+	if len(index) != len(wdl) {
+		t.Fatalf("created %d workers, want %d", len(index), len(wdl))
+	}
+	for _, wd := range wdl[:2] {
+		if index[wd.Id] < index["c@service"] {
+			t.Errorf("%s created before its require", wd.Id)
+		}
+	}
+	if index["a@service"] < index["b@service"] {
+		t.Error("a@service created before b@service")
+	}
+}
+
+func TestResetKeepsUnparsed(t *testing.T) {
+	wm, _ := newTestWorkers()
+	variables := variable.NewVariables(nil)
+	wm.Reset([]*eosc.WorkerConfig{
+		testWorkerConfig("a", `{}`),
+		testWorkerConfig("b", `{}`),
+	}, variables)
+	a, _ := wm.Get("a@service")
+	b, _ := wm.Get("b@service")
+
+	failed := wm.Reset([]*eosc.WorkerConfig{
+		testWorkerConfig("a", `{"target":`),
+	}, variables)
+	if _, has := failed["a@service"]; !has {
+		t.Fatalf("Reset() failed = %v, want a@service", failed)
+	}
+	if w, has := wm.Get("a@service"); !has || w != a {
+		t.Error("worker with invalid config should keep the old instance")
+	}
+	if a.(*testWorker).stopped {
+		t.Error("worker with invalid config should not be stopped")
+	}
+	if _, has := wm.Get("b@service"); has || !b.(*testWorker).stopped {
+		t.Error("removed worker should be stopped")
+	}
+	if err := wm.Update("a@service", variables); err != nil {
+		t.Error("Update() on kept worker:", err)
+	}
+}
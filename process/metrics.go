@@ -0,0 +1,22 @@
+package process
+
+import (
+	"github.com/eolinker/eosc/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	restartCrash  = "crash"
+	restartReload = "reload"
+)
+
+var restartsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "process",
+	Name:      "restarts_total",
+	Help:      "Child processes restarted by the master, by reason.",
+}, []string{"process", "reason"})
+
+func init() {
+	metrics.MustRegister(restartsTotal)
+}
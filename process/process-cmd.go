@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"sync"
 	"syscall"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 
@@ -20,22 +21,94 @@ const (
 	StatusExit
 	StatusRunning
 	StatusError
+	// StatusDraining 进程已停止接收新连接，正在等待已有请求结束
+	StatusDraining
 )
 
+// StatusFailed 进程反复异常退出，已停止重启
+const StatusFailed = "failed"
+
 type ProcessCmd struct {
 	name   string
 	cmd    *exec.Cmd
 	reader io.Reader
 	once   sync.Once
+	// locker 保护 status 与 msg，Read、Wait 与状态查询在不同的 goroutine
+	locker sync.RWMutex
 	status int
+	msg    string
+	stderr *tailBuffer
+	exited chan struct{}
+}
+
+// ProcessExit 进程一次运行的退出信息
+type ProcessExit struct {
+	Pid    int       `json:"pid"`
+	Code   int       `json:"code"`
+	Reason string    `json:"reason"`
+	Stderr string    `json:"stderr,omitempty"`
+	Time   time.Time `json:"time"`
 }
 
 func (p *ProcessCmd) Wait() error {
-	return p.cmd.Wait()
+	err := p.cmd.Wait()
+	p.setStatus(StatusExit)
+	close(p.exited)
+	return err
+}
+
+// Done 进程退出后关闭
+func (p *ProcessCmd) Done() <-chan struct{} {
+	return p.exited
+}
+
+// StatusName 进程状态的可读名称，未创建进程时为 stopped
+func StatusName(status int) string {
+	switch status {
+	case StatusStart:
+		return "starting"
+	case StatusExit:
+		return "exit"
+	case StatusRunning:
+		return "running"
+	case StatusError:
+		return "error"
+	case StatusDraining:
+		return "draining"
+	}
+	return "stopped"
 }
 
 func NewProcessCmd(name string, cmd *exec.Cmd, reader io.Reader) *ProcessCmd {
-	return &ProcessCmd{name: name, cmd: cmd, reader: reader, status: StatusStart}
+	return &ProcessCmd{name: name, cmd: cmd, reader: reader, status: StatusStart, exited: make(chan struct{})}
+}
+
+// Exit 在 Wait 返回后整理本次运行的退出码、原因以及最后的 stderr 输出
+func (p *ProcessCmd) Exit(err error) *ProcessExit {
+	exit := &ProcessExit{
+		Pid:  p.Pid(),
+		Code: -1,
+		Time: time.Now(),
+	}
+	if state := p.cmd.ProcessState; state != nil {
+		exit.Code = state.ExitCode()
+		exit.Reason = state.String()
+	} else if err != nil {
+		exit.Reason = err.Error()
+	}
+	if p.stderr != nil {
+		exit.Stderr = p.stderr.String()
+	}
+	return exit
+}
+
+// Drain 通知进程停止接收新连接，处理完已有请求后自行退出
+func (p *ProcessCmd) Drain() error {
+	return p.cmd.Process.Signal(syscall.SIGUSR2)
+}
+
+func (p *ProcessCmd) Kill() error {
+	return p.cmd.Process.Kill()
 }
 
 func (p *ProcessCmd) Close() error {
@@ -51,24 +124,49 @@ func (p *ProcessCmd) Pid() int {
 }
 
 func (p *ProcessCmd) Status() int {
+	p.locker.RLock()
+	defer p.locker.RUnlock()
 	return p.status
 }
 
+// Msg 进程最近一次回写状态时附带的信息
+func (p *ProcessCmd) Msg() string {
+	p.locker.RLock()
+	defer p.locker.RUnlock()
+	return p.msg
+}
+
+func (p *ProcessCmd) setStatus(status int) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	p.status = status
+}
+
+// Read 读取进程通过 stdout 回写的状态，直到进程退出
 func (p *ProcessCmd) Read() {
-	data, err := utils.ReadFrame(p.reader)
-	if err != nil {
-		p.status = StatusExit
-		log.Error(p.name, " ", err)
-		return
-	}
-	status := new(eosc.ProcessStatus)
-	err = proto.Unmarshal(data, status)
-	if err != nil {
-		p.status = StatusExit
-		log.Error(err)
-		return
+	for {
+		data, err := utils.ReadFrame(p.reader)
+		if err != nil {
+			p.locker.Lock()
+			if p.status == StatusStart {
+				p.status = StatusExit
+				log.Error(p.name, " ", err)
+			}
+			p.locker.Unlock()
+			return
+		}
+		status := new(eosc.ProcessStatus)
+		err = proto.Unmarshal(data, status)
+		if err != nil {
+			p.setStatus(StatusExit)
+			log.Error(err)
+			return
+		}
+		p.locker.Lock()
+		p.status = int(status.Status)
+		p.msg = status.Msg
+		p.locker.Unlock()
 	}
-	p.status = int(status.Status)
 }
 
 func (p *ProcessCmd) Cmd() *exec.Cmd {
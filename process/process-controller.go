@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -49,6 +50,36 @@ type ProcessController struct {
 	isStop      bool
 	isShutDown  int32
 	logWriter   io.Writer
+	starts      int
+	startAt     time.Time
+	restarts    restartState
+	grace       time.Duration
+	// stateLocker 保护对外展示的状态，避免启动进程期间查询被阻塞
+	stateLocker sync.RWMutex
+	failed      bool
+	lastExit    *ProcessExit
+	draining    map[int]*DrainInfo
+}
+
+// DrainInfo 正在排空的旧进程
+type DrainInfo struct {
+	Pid   int       `json:"pid"`
+	Since time.Time `json:"since"`
+	Msg   string    `json:"msg,omitempty"`
+	cmd   *ProcessCmd
+}
+
+// ProcessInfo 受控进程的运行状态
+type ProcessInfo struct {
+	Name     string       `json:"name"`
+	Index    int          `json:"index"`
+	Pid      int          `json:"pid,omitempty"`
+	Status   string       `json:"status"`
+	Restarts int          `json:"restarts"`
+	Start    time.Time    `json:"start,omitempty"`
+	Uptime   string       `json:"uptime,omitempty"`
+	LastExit *ProcessExit `json:"last_exit,omitempty"`
+	Draining []*DrainInfo `json:"draining,omitempty"`
 }
 
 func NewProcessController(ctx context.Context, name string, logWriter io.Writer, callback ...IProcessUpdate) *ProcessController {
@@ -61,6 +92,8 @@ func NewProcessController(ctx context.Context, name string, logWriter io.Writer,
 		cancel:      cancel,
 		restartChan: make(chan *StartArgs),
 		logWriter:   logWriter,
+		restarts:    restartState{policy: DefaultRestartPolicy},
+		draining:    make(map[int]*DrainInfo),
 	}
 	atomic.StoreInt32(&c.isShutDown, 1)
 	go c.doControl()
@@ -73,9 +106,26 @@ func (pc *ProcessController) Shutdown() {
 	atomic.StoreInt32(&pc.isShutDown, 1)
 	if pc.current != nil {
 		pc.current.Close()
+		pc.stateLocker.Lock()
 		pc.current = nil
+		pc.stateLocker.Unlock()
 	}
 }
+
+// SetRestartPolicy 设置进程异常退出后的重启策略
+func (pc *ProcessController) SetRestartPolicy(policy RestartPolicy) {
+	pc.locker.Lock()
+	defer pc.locker.Unlock()
+	pc.restarts = restartState{policy: policy}
+}
+
+// SetDrainTimeout 设置重启时旧进程的排空时间，为0时旧进程在新进程就绪后立即关闭
+func (pc *ProcessController) SetDrainTimeout(grace time.Duration) {
+	pc.locker.Lock()
+	defer pc.locker.Unlock()
+	pc.grace = grace
+}
+
 func (pc *ProcessController) Stop() {
 	pc.locker.Lock()
 	defer pc.locker.Unlock()
@@ -101,16 +151,25 @@ func newProcess(name string, data []byte, logWriter io.Writer, extraFiles []*os.
 		return nil, err
 	}
 
+	stderr := newTailBuffer(stderrTailSize)
 	cmd.Stdin = bytes.NewReader(utils.EncodeFrame(data))
 	cmd.Stdout = writer
-	cmd.Stderr = logWriter
+	if logWriter != nil {
+		cmd.Stderr = io.MultiWriter(logWriter, stderr)
+	} else {
+		cmd.Stderr = stderr
+	}
 	cmd.ExtraFiles = extraFiles
 
 	err = cmd.Start()
+	// 子进程已持有写端，父进程关闭后子进程退出时 Read 才能结束
+	writer.Close()
 	if err != nil {
+		reader.Close()
 		return nil, err
 	}
 	pc := NewProcessCmd(name, cmd, reader)
+	pc.stderr = stderr
 	go pc.Read()
 	return pc, nil
 }
@@ -119,26 +178,56 @@ func (pc *ProcessController) getClient() *ProcessCmd {
 	return pc.current
 }
 
+// check 等待进程退出，异常退出时按退避策略重新拉起，超过重启上限后进入 failed 状态
 func (pc *ProcessController) check(w *ProcessCmd, configData []byte, extraFiles []*os.File) {
 	err := w.Wait()
-	if err != nil {
-		log.Warn("worker exit:", err)
-	}
+	exit := w.Exit(err)
 	if atomic.LoadInt32(&pc.isShutDown) == 1 {
 		return
 	}
+	pc.locker.Lock()
+	if pc.current != w {
+		pc.locker.Unlock()
+		return
+	}
+	pc.stateLocker.Lock()
+	pc.lastExit = exit
+	pc.stateLocker.Unlock()
+	log.Warnf("%s process[%d] exit:%s", pc.name, exit.Pid, exit.Reason)
+	delay, ok := pc.restarts.next(exit.Time, exit.Time.Sub(pc.startAt))
+	if !ok {
+		pc.stateLocker.Lock()
+		pc.failed = true
+		pc.stateLocker.Unlock()
+		pc.locker.Unlock()
+		log.Errorf("%s process restart more than %d times in %s, give up", pc.name, pc.restarts.policy.MaxRestarts, pc.restarts.policy.Window)
+		pc.callback.Update(nil)
+		return
+	}
+	pc.locker.Unlock()
+
+	log.Infof("%s process restart after %s", pc.name, delay)
+	select {
+	case <-pc.ctx.Done():
+		return
+	case <-time.After(delay):
+	}
+
 	pc.locker.Lock()
 	defer pc.locker.Unlock()
-	if pc.current == w {
-		// 连接断开
-		err = pc.create(configData, extraFiles)
-		if err != nil {
-			log.Error("worker create:", err)
-		}
+	if pc.current != w || atomic.LoadInt32(&pc.isShutDown) == 1 {
+		// 等待期间已被重启或关闭
+		return
+	}
+	restartsTotal.WithLabelValues(pc.name, restartCrash).Inc()
+	err = pc.create(configData, extraFiles)
+	if err != nil {
+		log.Error(pc.name, " create:", err)
 	}
 }
 
-func (pc *ProcessController) run(configData []byte, extraFiles []*os.File) error {
+// run 启动新进程并替换当前进程，返回被替换的旧进程
+func (pc *ProcessController) run(configData []byte, extraFiles []*os.File) (*ProcessCmd, error) {
 	log.DebugF("create %s process start...\n", pc.name)
 
 	//cfg := pc.configBuild.Config()
@@ -146,26 +235,28 @@ func (pc *ProcessController) run(configData []byte, extraFiles []*os.File) error
 	p, err := newProcess(pc.name, configData, pc.logWriter, extraFiles)
 	if err != nil {
 		log.Warnf("new %s process: %w", pc.name, err)
-		return err
+		return nil, err
 	}
 
+	pc.stateLocker.Lock()
 	old := pc.current
 	pc.current = p
+	pc.starts++
+	pc.startAt = time.Now()
+	pc.stateLocker.Unlock()
 
 	go pc.check(pc.current, configData, extraFiles)
 
-	if old != nil {
-		old.Close()
-	}
-
-	return nil
+	return old, nil
 }
 func (pc *ProcessController) create(configData []byte, extraFiles []*os.File) error {
-	err := pc.run(configData, extraFiles)
+	old, err := pc.run(configData, extraFiles)
 	if err != nil {
 		log.Warn("new process[", pc.name, "]:", err)
 		return err
 	}
+	// 新进程就绪(或启动失败)后再让旧进程退出
+	defer pc.retire(old)
 
 	ticker := time.NewTimer(time.Millisecond * 5)
 
@@ -192,13 +283,101 @@ func (pc *ProcessController) create(configData []byte, extraFiles []*os.File) er
 	}
 }
 
+// retire 让被替换的旧进程退出：设置了排空时间时先通知其排空，超时后再关闭
+func (pc *ProcessController) retire(old *ProcessCmd) {
+	if old == nil {
+		return
+	}
+	if pc.grace <= 0 {
+		old.Close()
+		return
+	}
+	info := &DrainInfo{Pid: old.Pid(), Since: time.Now(), cmd: old}
+	pc.stateLocker.Lock()
+	pc.draining[info.Pid] = info
+	pc.stateLocker.Unlock()
+	go pc.drain(info, pc.grace)
+}
+
+func (pc *ProcessController) drain(info *DrainInfo, grace time.Duration) {
+	old := info.cmd
+	defer func() {
+		pc.stateLocker.Lock()
+		delete(pc.draining, info.Pid)
+		pc.stateLocker.Unlock()
+	}()
+
+	log.Infof("%s process[%d] start draining, grace %s", pc.name, info.Pid, grace)
+	if err := old.Drain(); err != nil {
+		log.Warnf("%s process[%d] drain:%v", pc.name, info.Pid, err)
+		old.Close()
+	}
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-old.Done():
+		log.Infof("%s process[%d] drained in %s", pc.name, info.Pid, time.Since(info.Since).Truncate(time.Millisecond))
+		return
+	case <-timer.C:
+		log.Warnf("%s process[%d] drain timeout after %s:%s", pc.name, info.Pid, grace, old.Msg())
+	case <-pc.ctx.Done():
+	}
+	old.Close()
+	select {
+	case <-old.Done():
+	case <-time.After(time.Second * 3):
+		old.Kill()
+	}
+}
+
 func (pc *ProcessController) Start(configData []byte, extraFiles []*os.File) error {
 	pc.locker.Lock()
 	defer pc.locker.Unlock()
 	atomic.StoreInt32(&pc.isShutDown, 0)
+	pc.resetFailed()
 	return pc.create(configData, extraFiles)
 }
 
+// resetFailed 配置变更触发的重启视为新的开始，清除 failed 状态与重启计数
+func (pc *ProcessController) resetFailed() {
+	pc.stateLocker.Lock()
+	pc.failed = false
+	pc.stateLocker.Unlock()
+	pc.restarts.reset()
+}
+
+// Info 返回当前进程的状态，restarts 为首次启动之后被重新拉起的次数
+func (pc *ProcessController) Info() *ProcessInfo {
+	pc.stateLocker.RLock()
+	defer pc.stateLocker.RUnlock()
+	info := &ProcessInfo{
+		Name:     pc.name,
+		Status:   StatusName(-1),
+		LastExit: pc.lastExit,
+	}
+	if pc.starts > 1 {
+		info.Restarts = pc.starts - 1
+	}
+	if pc.current != nil {
+		info.Pid = pc.current.Pid()
+		info.Status = StatusName(pc.current.Status())
+		info.Start = pc.startAt
+		if pc.current.Status() == StatusRunning {
+			info.Uptime = time.Since(pc.startAt).Truncate(time.Second).String()
+		}
+	}
+	if pc.failed {
+		info.Status = StatusFailed
+	}
+	for _, d := range pc.draining {
+		info.Draining = append(info.Draining, &DrainInfo{Pid: d.Pid, Since: d.Since, Msg: d.cmd.Msg()})
+	}
+	sort.Slice(info.Draining, func(i, j int) bool {
+		return info.Draining[i].Since.Before(info.Draining[j].Since)
+	})
+	return info
+}
+
 func (pc *ProcessController) TryRestart(configData []byte, extraFiles []*os.File) {
 
 	pc.restartChan <- &StartArgs{
@@ -211,6 +390,8 @@ func (pc *ProcessController) restart(configData []byte, extraFiles []*os.File) {
 	pc.locker.Lock()
 	defer pc.locker.Unlock()
 
+	pc.resetFailed()
+	restartsTotal.WithLabelValues(pc.name, restartReload).Inc()
 	err := pc.create(configData, extraFiles)
 	if err != nil {
 		log.Error("restart error: ", err)
@@ -0,0 +1,64 @@
+package process
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetire(t *testing.T) {
+	tests := []struct {
+		name   string
+		grace  time.Duration
+		args   []string
+		signal syscall.Signal
+	}{
+		{name: "no grace", grace: 0, args: []string{"sleep", "5"}, signal: syscall.SIGQUIT},
+		{name: "drained", grace: time.Second * 5, args: []string{"sleep", "5"}, signal: syscall.SIGUSR2},
+		// 忽略排空信号的进程在超时后被关闭
+		{name: "drain timeout", grace: time.Millisecond * 200, args: []string{"sh", "-c", `trap "" USR2; exec sleep 5`}, signal: syscall.SIGQUIT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(tt.args[0], tt.args[1:]...)
+			if err := cmd.Start(); err != nil {
+				t.Skip(err)
+			}
+			old := NewProcessCmd("test", cmd, nil)
+			go old.Wait()
+			// 等待 shell 设置好信号处理
+			time.Sleep(time.Millisecond * 100)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			pc := NewProcessController(ctx, "test", nil)
+			pc.SetDrainTimeout(tt.grace)
+			pc.retire(old)
+			if draining := pc.Info().Draining; (tt.grace > 0) != (len(draining) == 1) {
+				t.Errorf("draining = %v", draining)
+			}
+
+			select {
+			case <-old.Done():
+			case <-time.After(time.Second * 3):
+				old.Kill()
+				t.Fatal("old process not exit")
+			}
+			if old.Status() != StatusExit {
+				t.Errorf("status = %s", StatusName(old.Status()))
+			}
+			if ws := cmd.ProcessState.Sys().(syscall.WaitStatus); ws.Signal() != tt.signal {
+				t.Errorf("process exit with %v, want %v", ws.Signal(), tt.signal)
+			}
+			deadline := time.Now().Add(time.Second)
+			for len(pc.Info().Draining) > 0 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond * 10)
+			}
+			if draining := pc.Info().Draining; len(draining) != 0 {
+				t.Errorf("draining after exit = %v", draining)
+			}
+		})
+	}
+}
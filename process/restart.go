@@ -0,0 +1,105 @@
+package process
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const stderrTailSize = 4096
+
+// RestartPolicy 进程异常退出后的重启策略
+type RestartPolicy struct {
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxRestarts Window 时间内最多重启的次数，超过后不再拉起，进入 failed 状态；<=0 时不限制
+	MaxRestarts int
+	Window      time.Duration
+	// Stable 进程运行超过该时长视为正常，退避时间重新计算
+	Stable time.Duration
+}
+
+var DefaultRestartPolicy = RestartPolicy{
+	MinBackoff:  time.Millisecond * 500,
+	MaxBackoff:  time.Second * 30,
+	MaxRestarts: 5,
+	Window:      time.Minute,
+	Stable:      time.Minute,
+}
+
+type restartState struct {
+	policy   RestartPolicy
+	backoff  time.Duration
+	restarts []time.Time
+}
+
+// next 记录一次异常退出，返回下次重启前的等待时间；超过重启上限时返回 false
+func (s *restartState) next(now time.Time, uptime time.Duration) (time.Duration, bool) {
+	p := s.policy
+	if uptime >= p.Stable {
+		s.backoff = 0
+	}
+	if p.MaxRestarts > 0 {
+		kept := s.restarts[:0]
+		for _, t := range s.restarts {
+			if now.Sub(t) < p.Window {
+				kept = append(kept, t)
+			}
+		}
+		s.restarts = kept
+		if len(s.restarts) >= p.MaxRestarts {
+			return 0, false
+		}
+		s.restarts = append(s.restarts, now)
+	}
+	if s.backoff == 0 {
+		s.backoff = p.MinBackoff
+	} else {
+		s.backoff *= 2
+	}
+	if s.backoff > p.MaxBackoff {
+		s.backoff = p.MaxBackoff
+	}
+	return jitter(s.backoff), true
+}
+
+func (s *restartState) reset() {
+	s.backoff = 0
+	s.restarts = nil
+}
+
+// jitter 在 [d/2, d) 内取随机值，避免多个进程同时重启
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// tailBuffer 只保留最后写入的 size 字节，用于记录进程退出前的 stderr 输出
+type tailBuffer struct {
+	locker sync.Mutex
+	size   int
+	data   []byte
+}
+
+func newTailBuffer(size int) *tailBuffer {
+	return &tailBuffer{size: size}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	b.data = append(b.data, p...)
+	if over := len(b.data) - b.size; over > 0 {
+		b.data = append(b.data[:0], b.data[over:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	return string(b.data)
+}
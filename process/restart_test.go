@@ -0,0 +1,47 @@
+package process
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestartState(t *testing.T) {
+	s := &restartState{policy: RestartPolicy{
+		MinBackoff:  time.Second,
+		MaxBackoff:  time.Second * 4,
+		MaxRestarts: 3,
+		Window:      time.Minute,
+		Stable:      time.Minute,
+	}}
+	now := time.Now()
+	want := []time.Duration{time.Second, time.Second * 2, time.Second * 4}
+	for i, w := range want {
+		d, ok := s.next(now, time.Second)
+		if !ok {
+			t.Fatalf("restart %d: want ok", i)
+		}
+		if d < w/2 || d >= w {
+			t.Errorf("restart %d: backoff %s not in [%s,%s)", i, d, w/2, w)
+		}
+	}
+	if _, ok := s.next(now, time.Second); ok {
+		t.Fatal("want failed after max restarts")
+	}
+	// 窗口之外的重启不再计数，且运行稳定后退避时间重置
+	d, ok := s.next(now.Add(time.Minute*2), time.Minute*2)
+	if !ok {
+		t.Fatal("want ok after window")
+	}
+	if d >= time.Second {
+		t.Errorf("backoff %s not reset", d)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := newTailBuffer(8)
+	b.Write([]byte("hello "))
+	b.Write([]byte("world"))
+	if got := b.String(); got != "lo world" {
+		t.Errorf("tail = %q", got)
+	}
+}
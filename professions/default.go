@@ -0,0 +1,45 @@
+package professions
+
+import (
+	"github.com/eolinker/eosc"
+)
+
+// DefaultBody 返回单例 profession 下 driver 的默认配置，未声明时返回 false
+func DefaultBody(pc *eosc.ProfessionConfig, driver *eosc.DriverConfig) ([]byte, bool) {
+	if pc.Mod != eosc.ProfessionConfig_Singleton {
+		return nil, false
+	}
+	if driver.Default != "" {
+		return []byte(driver.Default), true
+	}
+	if pc.Default != "" {
+		return []byte(pc.Default), true
+	}
+	return nil, false
+}
+
+// DefaultWorkers 根据默认配置生成单例 profession 下的 worker，worker 名称与 driver 名称相同
+func DefaultWorkers(pc *eosc.ProfessionConfig) []*eosc.WorkerConfig {
+	rs := make([]*eosc.WorkerConfig, 0, len(pc.Drivers))
+	for _, d := range pc.Drivers {
+		body, has := DefaultBody(pc, d)
+		if !has {
+			continue
+		}
+		id, ok := eosc.ToWorkerId(d.Name, pc.Name)
+		if !ok {
+			continue
+		}
+		now := eosc.Now()
+		rs = append(rs, &eosc.WorkerConfig{
+			Id:         id,
+			Profession: pc.Name,
+			Name:       d.Name,
+			Driver:     d.Name,
+			Create:     now,
+			Update:     now,
+			Body:       body,
+		})
+	}
+	return rs
+}
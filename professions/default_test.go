@@ -0,0 +1,34 @@
+package professions
+
+import (
+	"testing"
+
+	"github.com/eolinker/eosc"
+)
+
+func TestDefaultWorkers(t *testing.T) {
+	pc := &eosc.ProfessionConfig{
+		Name:    "output",
+		Mod:     eosc.ProfessionConfig_Singleton,
+		Default: `{"enable":false}`,
+		Drivers: []*eosc.DriverConfig{
+			{Name: "file", Default: `{"enable":true}`},
+			{Name: "http"},
+		},
+	}
+	ws := DefaultWorkers(pc)
+	if len(ws) != 2 {
+		t.Fatalf("DefaultWorkers() size = %d, want 2", len(ws))
+	}
+	if ws[0].Id != "file@output" || ws[0].Driver != "file" || string(ws[0].Body) != `{"enable":true}` {
+		t.Errorf("DefaultWorkers()[0] = %v", ws[0])
+	}
+	if ws[1].Id != "http@output" || string(ws[1].Body) != `{"enable":false}` {
+		t.Errorf("DefaultWorkers()[1] = %v", ws[1])
+	}
+
+	pc.Mod = eosc.ProfessionConfig_Worker
+	if ws := DefaultWorkers(pc); len(ws) != 0 {
+		t.Errorf("DefaultWorkers() for worker mod = %v, want empty", ws)
+	}
+}
@@ -0,0 +1,149 @@
+package professions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eolinker/eosc"
+)
+
+const (
+	visiting = iota + 1
+	visited
+)
+
+// Resolve 按依赖关系对 profession 排序，被依赖的排在前面，同层按名称排序
+// 依赖不存在或存在循环依赖时返回错误
+func Resolve(configs []*eosc.ProfessionConfig) ([]*eosc.ProfessionConfig, error) {
+	cm := make(map[string]*eosc.ProfessionConfig, len(configs))
+	names := make([]string, 0, len(configs))
+	for _, c := range configs {
+		if c == nil {
+			continue
+		}
+		if _, has := cm[c.Name]; !has {
+			names = append(names, c.Name)
+		}
+		cm[c.Name] = c
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, dep := range cm[name].Dependencies {
+			if _, has := cm[dep]; !has {
+				return nil, fmt.Errorf("%s depend on %s:%w", name, dep, eosc.ErrorProfessionDependencies)
+			}
+		}
+	}
+
+	sorted := make([]*eosc.ProfessionConfig, 0, len(names))
+	state := make(map[string]int, len(names))
+	path := make([]string, 0, len(names))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for i, n := range path {
+				if n == name {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), name)
+			return fmt.Errorf("%s:%w", strings.Join(cycle, " -> "), eosc.ErrorProfessionDependencyCycle)
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range cm[name].Dependencies {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		sorted = append(sorted, cm[name])
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// Dependents 返回依赖指定 profession 的 profession 名称
+func Dependents(name string, configs []*eosc.ProfessionConfig) []string {
+	rs := make([]string, 0)
+	for _, c := range configs {
+		if c == nil || c.Name == name {
+			continue
+		}
+		for _, dep := range c.Dependencies {
+			if dep == name {
+				rs = append(rs, c.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(rs)
+	return rs
+}
+
+// Unresolvable 返回依赖无法解析的 profession 及原因，包括依赖不存在、循环依赖以及间接依赖了这些 profession 的
+func Unresolvable(configs []*eosc.ProfessionConfig) map[string]error {
+	cm := make(map[string]*eosc.ProfessionConfig, len(configs))
+	for _, c := range configs {
+		if c != nil {
+			cm[c.Name] = c
+		}
+	}
+	usable := make(map[string]bool, len(cm))
+	for changed := true; changed; {
+		changed = false
+		for name, c := range cm {
+			if usable[name] {
+				continue
+			}
+			ok := true
+			for _, dep := range c.Dependencies {
+				if !usable[dep] {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				usable[name] = true
+				changed = true
+			}
+		}
+	}
+	rs := make(map[string]error)
+	for name := range cm {
+		if usable[name] {
+			continue
+		}
+		// 只对 name 及其依赖排序，得到具体的缺失依赖或循环
+		closure := make([]*eosc.ProfessionConfig, 0, len(cm))
+		seen := map[string]bool{name: true}
+		queue := []string{name}
+		for len(queue) > 0 {
+			c := cm[queue[0]]
+			queue = queue[1:]
+			closure = append(closure, c)
+			for _, dep := range c.Dependencies {
+				if _, has := cm[dep]; has && !seen[dep] {
+					seen[dep] = true
+					queue = append(queue, dep)
+				}
+			}
+		}
+		_, err := Resolve(closure)
+		rs[name] = err
+	}
+	return rs
+}
@@ -0,0 +1,148 @@
+package professions
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/eolinker/eosc"
+)
+
+func profession(name string, dependencies ...string) *eosc.ProfessionConfig {
+	return &eosc.ProfessionConfig{Name: name, Dependencies: dependencies}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []*eosc.ProfessionConfig
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "empty",
+			configs: nil,
+			want:    []string{},
+		}, {
+			name: "order",
+			configs: []*eosc.ProfessionConfig{
+				profession("router", "service", "plugin"),
+				profession("service", "discovery"),
+				profession("plugin"),
+				profession("discovery"),
+			},
+			want: []string{"discovery", "plugin", "service", "router"},
+		}, {
+			name: "missing",
+			configs: []*eosc.ProfessionConfig{
+				profession("router", "service"),
+			},
+			wantErr: eosc.ErrorProfessionDependencies,
+		}, {
+			name: "cycle",
+			configs: []*eosc.ProfessionConfig{
+				profession("a", "b"),
+				profession("b", "c"),
+				profession("c", "a"),
+			},
+			wantErr: eosc.ErrorProfessionDependencyCycle,
+		}, {
+			name: "self",
+			configs: []*eosc.ProfessionConfig{
+				profession("a", "a"),
+			},
+			wantErr: eosc.ErrorProfessionDependencyCycle,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(tt.configs)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			names := make([]string, 0, len(got))
+			for _, c := range got {
+				names = append(names, c.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Resolve() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveCycleName(t *testing.T) {
+	_, err := Resolve([]*eosc.ProfessionConfig{
+		profession("a", "b"),
+		profession("b", "a"),
+	})
+	want := "a -> b -> a:profession dependency cycle"
+	if err == nil || err.Error() != want {
+		t.Errorf("Resolve() error = %v, want %s", err, want)
+	}
+}
+
+func TestResetDependencies(t *testing.T) {
+	ps := NewProfessions(nil)
+	if err := ps.Reset([]*eosc.ProfessionConfig{profession("router", "service"), profession("service")}); err != nil {
+		t.Fatal("Reset():", err)
+	}
+	err := ps.Reset([]*eosc.ProfessionConfig{profession("a", "b"), profession("b", "a")})
+	if !errors.Is(err, eosc.ErrorProfessionDependencyCycle) {
+		t.Errorf("Reset() error = %v, want %v", err, eosc.ErrorProfessionDependencyCycle)
+	}
+	if len(ps.List()) != 2 {
+		t.Errorf("Reset() with unresolvable dependencies should still apply, got %d professions", len(ps.List()))
+	}
+}
+
+func TestUnresolvable(t *testing.T) {
+	configs := []*eosc.ProfessionConfig{
+		profession("router", "service"),
+		profession("service", "discovery"),
+		profession("plugin"),
+		profession("a", "b"),
+		profession("b", "a"),
+	}
+	got := Unresolvable(configs)
+	want := map[string]error{
+		"router":  eosc.ErrorProfessionDependencies,
+		"service": eosc.ErrorProfessionDependencies,
+		"a":       eosc.ErrorProfessionDependencyCycle,
+		"b":       eosc.ErrorProfessionDependencyCycle,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Unresolvable() = %v", got)
+	}
+	for name, err := range want {
+		if !errors.Is(got[name], err) {
+			t.Errorf("Unresolvable()[%s] = %v, want %v", name, got[name], err)
+		}
+	}
+
+	ps := NewProfessions(nil)
+	ps.Reset(configs)
+	sorted := ps.Sort()
+	if len(sorted) != 1 || sorted[0].Name != "plugin" {
+		t.Errorf("Sort() should only return usable professions, got %d", len(sorted))
+	}
+	if ps.Unusable("plugin") != nil || ps.Unusable("router") == nil {
+		t.Errorf("Unusable() plugin = %v, router = %v", ps.Unusable("plugin"), ps.Unusable("router"))
+	}
+	// 已有的不可用 profession 不影响其他 profession 的修改
+	if err := ps.Set("plugin", profession("plugin")); err != nil {
+		t.Error("Set() usable profession:", err)
+	}
+	if err := ps.Set("plugin", profession("plugin", "a")); !errors.Is(err, eosc.ErrorProfessionDependencyCycle) {
+		t.Errorf("Set() depending on unusable profession error = %v", err)
+	}
+	if err := ps.Set("discovery", profession("discovery")); err != nil {
+		t.Error("Set() missing dependency:", err)
+	}
+	if ps.Unusable("router") != nil || len(ps.Sort()) != 4 {
+		t.Errorf("professions should be usable after the dependency is added, router: %v", ps.Unusable("router"))
+	}
+}
@@ -1,6 +1,10 @@
 package professions
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/eolinker/eosc"
 	"github.com/eolinker/eosc/log"
 )
@@ -14,12 +18,16 @@ type IProfessions interface {
 	List() []*Profession
 	Delete(name string) error
 	Set(name string, profession *eosc.ProfessionConfig) error
-	Reset(configs []*eosc.ProfessionConfig)
+	// Reset 依赖无法解析时仍然应用 configs，并返回错误，受影响的 profession 标记为不可用
+	Reset(configs []*eosc.ProfessionConfig) error
+	// Unusable 返回 profession 因依赖无法解析而不可用的原因，可用时返回 nil
+	Unusable(name string) error
 }
 
 type Professions struct {
-	data    eosc.Untyped[string, *Profession]
-	extends eosc.IExtenderDrivers
+	data     eosc.Untyped[string, *Profession]
+	unusable map[string]error
+	extends  eosc.IExtenderDrivers
 }
 
 func (ps *Professions) List() []*Profession {
@@ -27,60 +35,59 @@ func (ps *Professions) List() []*Profession {
 }
 
 func (ps *Professions) Delete(name string) error {
-	// todo 校验
-	_, has := ps.data.Del(name)
-	if !has {
+	if _, has := ps.data.Get(name); !has {
 		return eosc.ErrorProfessionNotExist
 	}
+	if dependents := Dependents(name, ps.configs()); len(dependents) > 0 {
+		return fmt.Errorf("%s depended on by %s:%w", name, strings.Join(dependents, ","), eosc.ErrorProfessionDependent)
+	}
+	ps.data.Del(name)
+	ps.unusable = Unresolvable(ps.configs())
 	return nil
 }
 
+func (ps *Professions) Unusable(name string) error {
+	return ps.unusable[name]
+}
+
+func (ps *Professions) configs() []*eosc.ProfessionConfig {
+	list := ps.data.List()
+	configs := make([]*eosc.ProfessionConfig, 0, len(list))
+	for _, p := range list {
+		configs = append(configs, p.ProfessionConfig)
+	}
+	return configs
+}
+
+// Sort 按依赖关系返回可用的非单例 profession
 func (ps *Professions) Sort() []*Profession {
 	list := ps.data.List()
 
 	sl := make([]*Profession, 0, len(list))
-	sm := make(map[string]int)
-	index := 0
-	for i, p := range list {
-		if p.Mod == eosc.ProfessionConfig_Singleton {
-			list[i] = nil
+	configs := make([]*eosc.ProfessionConfig, 0, len(list))
+	for _, c := range ps.configs() {
+		if err := ps.unusable[c.Name]; err != nil {
+			log.Error("skip unusable profession ", c.Name, ":", err)
 			continue
 		}
+		configs = append(configs, c)
 	}
-	for len(list) > 0 {
-		sc := 0
-		for i, v := range list {
-			if v == nil {
-				sc++
-				continue
-			}
-			dependenciesHas := 0
-			for _, dep := range v.Dependencies {
-				if _, has := sm[dep]; !has {
-					break
-				}
-
-				dependenciesHas++
-			}
-			if dependenciesHas == len(v.Dependencies) {
-				sl = append(sl, v)
-				sm[v.Name] = index
-				index++
-				sc++
-				list[i] = nil
-			}
-		}
-		if sc == 0 {
-			// todo profession dependencies error
-			break
+	sorted, err := Resolve(configs)
+	if err != nil {
+		// 依赖异常时按名称排序，避免 profession 下的 worker 被忽略
+		log.Error("sort profession:", err)
+		sorted = configs
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].Name < sorted[j].Name
+		})
+	}
+	for _, c := range sorted {
+		if c.Mod == eosc.ProfessionConfig_Singleton {
+			continue
 		}
-		tmp := list[:0]
-		for _, v := range list {
-			if v != nil {
-				tmp = append(tmp, v)
-			}
+		if p, has := ps.data.Get(c.Name); has {
+			sl = append(sl, p)
 		}
-		list = tmp
 	}
 	for i, s := range sl {
 		log.Info("index: ", i, " name: ", s.Name)
@@ -90,7 +97,9 @@ func (ps *Professions) Sort() []*Profession {
 
 func NewProfessions(extends eosc.IExtenderDrivers) IProfessions {
 	ps := &Professions{
-		extends: extends,
+		data:     eosc.BuildUntyped[string, *Profession](),
+		unusable: make(map[string]error),
+		extends:  extends,
 	}
 	return ps
 }
@@ -99,15 +108,33 @@ func (ps *Professions) Set(name string, c *eosc.ProfessionConfig) error {
 	if name == "setting" {
 		return nil
 	}
+	configs := make([]*eosc.ProfessionConfig, 0, ps.data.Count()+1)
+	for _, o := range ps.configs() {
+		if o.Name != name {
+			configs = append(configs, o)
+		}
+	}
+	// 只拒绝让 c 或原本可用的 profession 变为不可用的修改，已有的不可用 profession 不影响其他 profession 的修改
+	unusable := Unresolvable(append(configs, c))
+	if err := unusable[name]; err != nil {
+		return err
+	}
+	for n, err := range unusable {
+		if ps.unusable[n] == nil {
+			return err
+		}
+	}
 	p := NewProfession(c, ps.extends)
 	ps.data.Set(name, p)
+	ps.unusable = unusable
 
 	// todo refresh worker
 	return nil
 }
-func (ps *Professions) Reset(configs []*eosc.ProfessionConfig) {
+func (ps *Professions) Reset(configs []*eosc.ProfessionConfig) error {
 	data := eosc.BuildUntyped[string, *Profession]()
 	log.Debug("reset profession:", configs)
+	_, err := Resolve(configs)
 	for _, c := range configs {
 		log.Debug("add profession config:", c)
 		if c.Name == "setting" {
@@ -117,6 +144,8 @@ func (ps *Professions) Reset(configs []*eosc.ProfessionConfig) {
 		data.Set(c.Name, p)
 	}
 	ps.data = data
+	ps.unusable = Unresolvable(configs)
+	return err
 }
 func (ps *Professions) Get(name string) (*Profession, bool) {
 	p, b := ps.data.Get(name)
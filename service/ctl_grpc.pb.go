@@ -27,6 +27,7 @@ type CtiServiceClient interface {
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
 	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error)
 	Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*RemoveResponse, error)
+	Processes(ctx context.Context, in *ProcessesRequest, opts ...grpc.CallOption) (*ProcessesResponse, error)
 }
 
 type ctiServiceClient struct {
@@ -82,6 +83,15 @@ func (c *ctiServiceClient) Remove(ctx context.Context, in *RemoveRequest, opts .
 	return out, nil
 }
 
+func (c *ctiServiceClient) Processes(ctx context.Context, in *ProcessesRequest, opts ...grpc.CallOption) (*ProcessesResponse, error) {
+	out := new(ProcessesResponse)
+	err := c.cc.Invoke(ctx, "/service.CtiService/Processes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CtiServiceServer is the server API for CtiService service.
 // All implementations must embed UnimplementedCtiServiceServer
 // for forward compatibility
@@ -91,6 +101,7 @@ type CtiServiceServer interface {
 	List(context.Context, *ListRequest) (*ListResponse, error)
 	Info(context.Context, *InfoRequest) (*InfoResponse, error)
 	Remove(context.Context, *RemoveRequest) (*RemoveResponse, error)
+	Processes(context.Context, *ProcessesRequest) (*ProcessesResponse, error)
 	mustEmbedUnimplementedCtiServiceServer()
 }
 
@@ -113,6 +124,9 @@ func (UnimplementedCtiServiceServer) Info(context.Context, *InfoRequest) (*InfoR
 func (UnimplementedCtiServiceServer) Remove(context.Context, *RemoveRequest) (*RemoveResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Remove not implemented")
 }
+func (UnimplementedCtiServiceServer) Processes(context.Context, *ProcessesRequest) (*ProcessesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Processes not implemented")
+}
 func (UnimplementedCtiServiceServer) mustEmbedUnimplementedCtiServiceServer() {}
 
 // UnsafeCtiServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -216,6 +230,24 @@ func _CtiService_Remove_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CtiService_Processes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProcessesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CtiServiceServer).Processes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.CtiService/Processes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CtiServiceServer).Processes(ctx, req.(*ProcessesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CtiService_ServiceDesc is the grpc.ServiceDesc for CtiService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -243,6 +275,10 @@ var CtiService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Remove",
 			Handler:    _CtiService_Remove_Handler,
 		},
+		{
+			MethodName: "Processes",
+			Handler:    _CtiService_Processes_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "ctl.proto",
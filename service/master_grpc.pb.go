@@ -22,7 +22,8 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MasterDispatcherClient interface {
-	Listen(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (MasterDispatcher_ListenClient, error)
+	Listen(ctx context.Context, in *ListenRequest, opts ...grpc.CallOption) (MasterDispatcher_ListenClient, error)
+	Report(ctx context.Context, in *ApplyReport, opts ...grpc.CallOption) (*EmptyRequest, error)
 }
 
 type masterDispatcherClient struct {
@@ -33,7 +34,7 @@ func NewMasterDispatcherClient(cc grpc.ClientConnInterface) MasterDispatcherClie
 	return &masterDispatcherClient{cc}
 }
 
-func (c *masterDispatcherClient) Listen(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (MasterDispatcher_ListenClient, error) {
+func (c *masterDispatcherClient) Listen(ctx context.Context, in *ListenRequest, opts ...grpc.CallOption) (MasterDispatcher_ListenClient, error) {
 	stream, err := c.cc.NewStream(ctx, &MasterDispatcher_ServiceDesc.Streams[0], "/service.MasterDispatcher/Listen", opts...)
 	if err != nil {
 		return nil, err
@@ -65,11 +66,21 @@ func (x *masterDispatcherListenClient) Recv() (*Event, error) {
 	return m, nil
 }
 
+func (c *masterDispatcherClient) Report(ctx context.Context, in *ApplyReport, opts ...grpc.CallOption) (*EmptyRequest, error) {
+	out := new(EmptyRequest)
+	err := c.cc.Invoke(ctx, "/service.MasterDispatcher/Report", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MasterDispatcherServer is the server API for MasterDispatcher service.
 // All implementations must embed UnimplementedMasterDispatcherServer
 // for forward compatibility
 type MasterDispatcherServer interface {
-	Listen(*EmptyRequest, MasterDispatcher_ListenServer) error
+	Listen(*ListenRequest, MasterDispatcher_ListenServer) error
+	Report(context.Context, *ApplyReport) (*EmptyRequest, error)
 	mustEmbedUnimplementedMasterDispatcherServer()
 }
 
@@ -77,9 +88,12 @@ type MasterDispatcherServer interface {
 type UnimplementedMasterDispatcherServer struct {
 }
 
-func (UnimplementedMasterDispatcherServer) Listen(*EmptyRequest, MasterDispatcher_ListenServer) error {
+func (UnimplementedMasterDispatcherServer) Listen(*ListenRequest, MasterDispatcher_ListenServer) error {
 	return status.Errorf(codes.Unimplemented, "method Listen not implemented")
 }
+func (UnimplementedMasterDispatcherServer) Report(context.Context, *ApplyReport) (*EmptyRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Report not implemented")
+}
 func (UnimplementedMasterDispatcherServer) mustEmbedUnimplementedMasterDispatcherServer() {}
 
 // UnsafeMasterDispatcherServer may be embedded to opt out of forward compatibility for this service.
@@ -94,7 +108,7 @@ func RegisterMasterDispatcherServer(s grpc.ServiceRegistrar, srv MasterDispatche
 }
 
 func _MasterDispatcher_Listen_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(EmptyRequest)
+	m := new(ListenRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
@@ -114,13 +128,36 @@ func (x *masterDispatcherListenServer) Send(m *Event) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _MasterDispatcher_Report_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyReport)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterDispatcherServer).Report(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.MasterDispatcher/Report",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterDispatcherServer).Report(ctx, req.(*ApplyReport))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MasterDispatcher_ServiceDesc is the grpc.ServiceDesc for MasterDispatcher service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var MasterDispatcher_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "service.MasterDispatcher",
 	HandlerType: (*MasterDispatcherServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Report",
+			Handler:    _MasterDispatcher_Report_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "Listen",
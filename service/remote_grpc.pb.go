@@ -0,0 +1,429 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.19.4
+// source: remote.proto
+
+package service
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RemoteExtenderClient is the client API for RemoteExtender service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RemoteExtenderClient interface {
+	Factories(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*RemoteFactoriesResponse, error)
+	CreateDriver(ctx context.Context, in *RemoteDriverRequest, opts ...grpc.CallOption) (*RemoteDriverResponse, error)
+	CheckConfig(ctx context.Context, in *RemoteWorkerRequest, opts ...grpc.CallOption) (*EmptyRequest, error)
+	CreateWorker(ctx context.Context, in *RemoteWorkerRequest, opts ...grpc.CallOption) (*EmptyRequest, error)
+	ResetWorker(ctx context.Context, in *RemoteWorkerRequest, opts ...grpc.CallOption) (*EmptyRequest, error)
+	StartWorker(ctx context.Context, in *RemoteWorkerId, opts ...grpc.CallOption) (*EmptyRequest, error)
+	StopWorker(ctx context.Context, in *RemoteWorkerId, opts ...grpc.CallOption) (*EmptyRequest, error)
+	DestroyWorker(ctx context.Context, in *RemoteWorkerId, opts ...grpc.CallOption) (*EmptyRequest, error)
+	CheckSkill(ctx context.Context, in *RemoteSkillRequest, opts ...grpc.CallOption) (*RemoteSkillResponse, error)
+	Metrics(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*RemoteMetricsResponse, error)
+}
+
+type remoteExtenderClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRemoteExtenderClient(cc grpc.ClientConnInterface) RemoteExtenderClient {
+	return &remoteExtenderClient{cc}
+}
+
+func (c *remoteExtenderClient) Factories(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*RemoteFactoriesResponse, error) {
+	out := new(RemoteFactoriesResponse)
+	err := c.cc.Invoke(ctx, "/service.RemoteExtender/Factories", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *remoteExtenderClient) CreateDriver(ctx context.Context, in *RemoteDriverRequest, opts ...grpc.CallOption) (*RemoteDriverResponse, error) {
+	out := new(RemoteDriverResponse)
+	err := c.cc.Invoke(ctx, "/service.RemoteExtender/CreateDriver", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *remoteExtenderClient) CheckConfig(ctx context.Context, in *RemoteWorkerRequest, opts ...grpc.CallOption) (*EmptyRequest, error) {
+	out := new(EmptyRequest)
+	err := c.cc.Invoke(ctx, "/service.RemoteExtender/CheckConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *remoteExtenderClient) CreateWorker(ctx context.Context, in *RemoteWorkerRequest, opts ...grpc.CallOption) (*EmptyRequest, error) {
+	out := new(EmptyRequest)
+	err := c.cc.Invoke(ctx, "/service.RemoteExtender/CreateWorker", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *remoteExtenderClient) ResetWorker(ctx context.Context, in *RemoteWorkerRequest, opts ...grpc.CallOption) (*EmptyRequest, error) {
+	out := new(EmptyRequest)
+	err := c.cc.Invoke(ctx, "/service.RemoteExtender/ResetWorker", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *remoteExtenderClient) StartWorker(ctx context.Context, in *RemoteWorkerId, opts ...grpc.CallOption) (*EmptyRequest, error) {
+	out := new(EmptyRequest)
+	err := c.cc.Invoke(ctx, "/service.RemoteExtender/StartWorker", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *remoteExtenderClient) StopWorker(ctx context.Context, in *RemoteWorkerId, opts ...grpc.CallOption) (*EmptyRequest, error) {
+	out := new(EmptyRequest)
+	err := c.cc.Invoke(ctx, "/service.RemoteExtender/StopWorker", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *remoteExtenderClient) DestroyWorker(ctx context.Context, in *RemoteWorkerId, opts ...grpc.CallOption) (*EmptyRequest, error) {
+	out := new(EmptyRequest)
+	err := c.cc.Invoke(ctx, "/service.RemoteExtender/DestroyWorker", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *remoteExtenderClient) CheckSkill(ctx context.Context, in *RemoteSkillRequest, opts ...grpc.CallOption) (*RemoteSkillResponse, error) {
+	out := new(RemoteSkillResponse)
+	err := c.cc.Invoke(ctx, "/service.RemoteExtender/CheckSkill", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *remoteExtenderClient) Metrics(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*RemoteMetricsResponse, error) {
+	out := new(RemoteMetricsResponse)
+	err := c.cc.Invoke(ctx, "/service.RemoteExtender/Metrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RemoteExtenderServer is the server API for RemoteExtender service.
+// All implementations must embed UnimplementedRemoteExtenderServer
+// for forward compatibility
+type RemoteExtenderServer interface {
+	Factories(context.Context, *EmptyRequest) (*RemoteFactoriesResponse, error)
+	CreateDriver(context.Context, *RemoteDriverRequest) (*RemoteDriverResponse, error)
+	CheckConfig(context.Context, *RemoteWorkerRequest) (*EmptyRequest, error)
+	CreateWorker(context.Context, *RemoteWorkerRequest) (*EmptyRequest, error)
+	ResetWorker(context.Context, *RemoteWorkerRequest) (*EmptyRequest, error)
+	StartWorker(context.Context, *RemoteWorkerId) (*EmptyRequest, error)
+	StopWorker(context.Context, *RemoteWorkerId) (*EmptyRequest, error)
+	DestroyWorker(context.Context, *RemoteWorkerId) (*EmptyRequest, error)
+	CheckSkill(context.Context, *RemoteSkillRequest) (*RemoteSkillResponse, error)
+	Metrics(context.Context, *EmptyRequest) (*RemoteMetricsResponse, error)
+	mustEmbedUnimplementedRemoteExtenderServer()
+}
+
+// UnimplementedRemoteExtenderServer must be embedded to have forward compatible implementations.
+type UnimplementedRemoteExtenderServer struct {
+}
+
+func (UnimplementedRemoteExtenderServer) Factories(context.Context, *EmptyRequest) (*RemoteFactoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Factories not implemented")
+}
+func (UnimplementedRemoteExtenderServer) CreateDriver(context.Context, *RemoteDriverRequest) (*RemoteDriverResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDriver not implemented")
+}
+func (UnimplementedRemoteExtenderServer) CheckConfig(context.Context, *RemoteWorkerRequest) (*EmptyRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckConfig not implemented")
+}
+func (UnimplementedRemoteExtenderServer) CreateWorker(context.Context, *RemoteWorkerRequest) (*EmptyRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWorker not implemented")
+}
+func (UnimplementedRemoteExtenderServer) ResetWorker(context.Context, *RemoteWorkerRequest) (*EmptyRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetWorker not implemented")
+}
+func (UnimplementedRemoteExtenderServer) StartWorker(context.Context, *RemoteWorkerId) (*EmptyRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartWorker not implemented")
+}
+func (UnimplementedRemoteExtenderServer) StopWorker(context.Context, *RemoteWorkerId) (*EmptyRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopWorker not implemented")
+}
+func (UnimplementedRemoteExtenderServer) DestroyWorker(context.Context, *RemoteWorkerId) (*EmptyRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DestroyWorker not implemented")
+}
+func (UnimplementedRemoteExtenderServer) CheckSkill(context.Context, *RemoteSkillRequest) (*RemoteSkillResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckSkill not implemented")
+}
+func (UnimplementedRemoteExtenderServer) Metrics(context.Context, *EmptyRequest) (*RemoteMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Metrics not implemented")
+}
+func (UnimplementedRemoteExtenderServer) mustEmbedUnimplementedRemoteExtenderServer() {}
+
+// UnsafeRemoteExtenderServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RemoteExtenderServer will
+// result in compilation errors.
+type UnsafeRemoteExtenderServer interface {
+	mustEmbedUnimplementedRemoteExtenderServer()
+}
+
+func RegisterRemoteExtenderServer(s grpc.ServiceRegistrar, srv RemoteExtenderServer) {
+	s.RegisterService(&RemoteExtender_ServiceDesc, srv)
+}
+
+func _RemoteExtender_Factories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RemoteExtenderServer).Factories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.RemoteExtender/Factories",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RemoteExtenderServer).Factories(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RemoteExtender_CreateDriver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoteDriverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RemoteExtenderServer).CreateDriver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.RemoteExtender/CreateDriver",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RemoteExtenderServer).CreateDriver(ctx, req.(*RemoteDriverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RemoteExtender_CheckConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoteWorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RemoteExtenderServer).CheckConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.RemoteExtender/CheckConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RemoteExtenderServer).CheckConfig(ctx, req.(*RemoteWorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RemoteExtender_CreateWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoteWorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RemoteExtenderServer).CreateWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.RemoteExtender/CreateWorker",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RemoteExtenderServer).CreateWorker(ctx, req.(*RemoteWorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RemoteExtender_ResetWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoteWorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RemoteExtenderServer).ResetWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.RemoteExtender/ResetWorker",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RemoteExtenderServer).ResetWorker(ctx, req.(*RemoteWorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RemoteExtender_StartWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoteWorkerId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RemoteExtenderServer).StartWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.RemoteExtender/StartWorker",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RemoteExtenderServer).StartWorker(ctx, req.(*RemoteWorkerId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RemoteExtender_StopWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoteWorkerId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RemoteExtenderServer).StopWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.RemoteExtender/StopWorker",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RemoteExtenderServer).StopWorker(ctx, req.(*RemoteWorkerId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RemoteExtender_DestroyWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoteWorkerId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RemoteExtenderServer).DestroyWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.RemoteExtender/DestroyWorker",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RemoteExtenderServer).DestroyWorker(ctx, req.(*RemoteWorkerId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RemoteExtender_CheckSkill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoteSkillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RemoteExtenderServer).CheckSkill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.RemoteExtender/CheckSkill",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RemoteExtenderServer).CheckSkill(ctx, req.(*RemoteSkillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RemoteExtender_Metrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RemoteExtenderServer).Metrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/service.RemoteExtender/Metrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RemoteExtenderServer).Metrics(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RemoteExtender_ServiceDesc is the grpc.ServiceDesc for RemoteExtender service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RemoteExtender_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "service.RemoteExtender",
+	HandlerType: (*RemoteExtenderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Factories",
+			Handler:    _RemoteExtender_Factories_Handler,
+		},
+		{
+			MethodName: "CreateDriver",
+			Handler:    _RemoteExtender_CreateDriver_Handler,
+		},
+		{
+			MethodName: "CheckConfig",
+			Handler:    _RemoteExtender_CheckConfig_Handler,
+		},
+		{
+			MethodName: "CreateWorker",
+			Handler:    _RemoteExtender_CreateWorker_Handler,
+		},
+		{
+			MethodName: "ResetWorker",
+			Handler:    _RemoteExtender_ResetWorker_Handler,
+		},
+		{
+			MethodName: "StartWorker",
+			Handler:    _RemoteExtender_StartWorker_Handler,
+		},
+		{
+			MethodName: "StopWorker",
+			Handler:    _RemoteExtender_StopWorker_Handler,
+		},
+		{
+			MethodName: "DestroyWorker",
+			Handler:    _RemoteExtender_DestroyWorker_Handler,
+		},
+		{
+			MethodName: "CheckSkill",
+			Handler:    _RemoteExtender_CheckSkill_Handler,
+		},
+		{
+			MethodName: "Metrics",
+			Handler:    _RemoteExtender_Metrics_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "remote.proto",
+}
@@ -1,11 +1,18 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
 
 	"github.com/eolinker/eosc/env"
+	"google.golang.org/grpc/metadata"
 )
 
+const metadataPid = "eosc-pid"
+
 func ServerAddr(pid int, name string) string {
 
 	return env.SocketAddr(name, pid)
@@ -14,3 +21,27 @@ func ServerAddr(pid int, name string) string {
 func ServerUnixAddr(pid int, name string) string {
 	return env.SocketAddr(fmt.Sprintf("unix-%s", name), pid)
 }
+
+// ApplyHash 配置数据的摘要，master 与 worker 进程以此对应同一份配置的应用结果
+func ApplyHash(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
+
+// WithProcess 在调用 master 的请求中带上当前进程的 pid，用于区分同一节点上的多个 worker 进程
+func WithProcess(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, metadataPid, strconv.Itoa(os.Getpid()))
+}
+
+// ProcessFromContext 读取调用方进程的 pid
+func ProcessFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if vs := md.Get(metadataPid); len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
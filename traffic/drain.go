@@ -0,0 +1,60 @@
+package traffic
+
+import (
+	"context"
+	"sync"
+)
+
+// IDrainer 可排空的服务：停止接收新连接，等待已有连接处理完成
+type IDrainer interface {
+	Drain(ctx context.Context) error
+	// Active 仍在处理中的连接数
+	Active() int
+}
+
+var (
+	drainLocker sync.Mutex
+	drainers    []IDrainer
+)
+
+// RegisterDrainer 注册当前进程内需要在退出前排空的服务
+func RegisterDrainer(d IDrainer) {
+	drainLocker.Lock()
+	defer drainLocker.Unlock()
+	drainers = append(drainers, d)
+}
+
+func allDrainers() []IDrainer {
+	drainLocker.Lock()
+	defer drainLocker.Unlock()
+	ds := make([]IDrainer, len(drainers))
+	copy(ds, drainers)
+	return ds
+}
+
+// Drain 并行排空所有已注册的服务，全部完成或 ctx 结束时返回
+func Drain(ctx context.Context) error {
+	ds := allDrainers()
+	errs := make(chan error, len(ds))
+	for _, d := range ds {
+		go func(d IDrainer) {
+			errs <- d.Drain(ctx)
+		}(d)
+	}
+	var err error
+	for range ds {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Active 所有已注册服务中仍在处理的连接数
+func Active() int {
+	n := 0
+	for _, d := range allDrainers() {
+		n += d.Active()
+	}
+	return n
+}
@@ -0,0 +1,105 @@
+package traffic_http_fast
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+)
+
+// trackListener 记录 Accept 出的连接直到关闭；fasthttp 不统计被 Hijack 的连接（如 websocket），排空时需要单独等待
+type trackListener struct {
+	net.Listener
+	locker  sync.Mutex
+	conns   map[net.Conn]struct{}
+	changed chan struct{}
+}
+
+func newTrackListener(inner net.Listener) *trackListener {
+	return &trackListener{
+		Listener: inner,
+		conns:    make(map[net.Conn]struct{}),
+		changed:  make(chan struct{}),
+	}
+}
+
+func (l *trackListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		acceptTotal.WithLabelValues(l.Addr().String(), "error").Inc()
+		return nil, err
+	}
+	acceptTotal.WithLabelValues(l.Addr().String(), "success").Inc()
+	c := &trackConn{Conn: conn, l: l}
+	l.locker.Lock()
+	l.conns[c] = struct{}{}
+	l.locker.Unlock()
+	return c, nil
+}
+
+// Close worker 排空前会先关闭底层端口，重复关闭视为成功，以免 fasthttp 的 Shutdown 提前返回而不等待处理中的请求
+func (l *trackListener) Close() error {
+	if err := l.Listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
+
+func (l *trackListener) remove(c net.Conn) {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+	delete(l.conns, c)
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// Active 未关闭的连接数，包括被 Hijack 的连接
+func (l *trackListener) Active() int {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+	return len(l.conns)
+}
+
+// Wait 等待所有连接关闭，ctx 结束时关闭剩余的连接
+func (l *trackListener) Wait(ctx context.Context) error {
+	for {
+		l.locker.Lock()
+		n := len(l.conns)
+		changed := l.changed
+		l.locker.Unlock()
+		if n == 0 {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			l.closeAll()
+			return ctx.Err()
+		}
+	}
+}
+
+func (l *trackListener) closeAll() {
+	l.locker.Lock()
+	conns := make([]net.Conn, 0, len(l.conns))
+	for c := range l.conns {
+		conns = append(conns, c)
+	}
+	l.locker.Unlock()
+	for _, c := range conns {
+		c.Close()
+	}
+}
+
+type trackConn struct {
+	net.Conn
+	once sync.Once
+	l    *trackListener
+}
+
+func (c *trackConn) Close() error {
+	c.once.Do(func() {
+		c.l.remove(c)
+	})
+	return c.Conn.Close()
+}
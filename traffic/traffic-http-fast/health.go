@@ -0,0 +1,49 @@
+package traffic_http_fast
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/eolinker/eosc/env"
+	"github.com/eolinker/eosc/health"
+	"github.com/valyala/fasthttp"
+)
+
+// envHealthPath 数据面端口上 worker 健康检查的路径前缀，如 /system/health，未设置时不拦截任何请求
+const envHealthPath = "HEALTH_PATH"
+
+var healthPath, _ = env.GetEnv(envHealthPath)
+
+// withHealth 设置了 HEALTH_PATH 时在数据面端口上提供 worker 进程的存活与就绪检查，其他请求交给 next 处理
+func withHealth(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return withHealthPath(healthPath, next)
+}
+
+// withHealthPath 在 prefix/live、prefix/ready 上提供检查，prefix 为空时直接返回 next
+func withHealthPath(prefix string, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		return next
+	}
+	live, ready := prefix+"/live", prefix+"/ready"
+	return func(ctx *fasthttp.RequestCtx) {
+		if ctx.IsGet() {
+			switch string(ctx.Path()) {
+			case live:
+				writeReport(ctx, health.Live())
+				return
+			case ready:
+				writeReport(ctx, health.Ready())
+				return
+			}
+		}
+		next(ctx)
+	}
+}
+
+func writeReport(ctx *fasthttp.RequestCtx, report *health.Report) {
+	data, _ := json.Marshal(report)
+	ctx.SetStatusCode(report.StatusCode())
+	ctx.SetContentType("application/json")
+	ctx.SetBody(data)
+}
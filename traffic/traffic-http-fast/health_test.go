@@ -0,0 +1,38 @@
+package traffic_http_fast
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestWithHealthPath(t *testing.T) {
+	next := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(http.StatusTeapot)
+	}
+	tests := []struct {
+		name   string
+		prefix string
+		method string
+		path   string
+		want   int
+	}{
+		{name: "disabled", prefix: "", method: http.MethodGet, path: "/system/health/live", want: http.StatusTeapot},
+		{name: "live", prefix: "/_health/", method: http.MethodGet, path: "/_health/live", want: http.StatusOK},
+		{name: "ready", prefix: "/_health", method: http.MethodGet, path: "/_health/ready", want: http.StatusOK},
+		{name: "other path", prefix: "/_health", method: http.MethodGet, path: "/system/health/live", want: http.StatusTeapot},
+		{name: "post", prefix: "/_health", method: http.MethodPost, path: "/_health/live", want: http.StatusTeapot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.SetMethod(tt.method)
+			ctx.Request.SetRequestURI(tt.path)
+			withHealthPath(tt.prefix, next)(ctx)
+			if got := ctx.Response.StatusCode(); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package traffic_http_fast
 
 import (
+	"context"
 	"errors"
 	"net"
 	"sync"
@@ -25,15 +26,16 @@ type HttpService struct {
 	status int
 	inner  net.Listener
 	srv    *fasthttp.Server
+	conns  *trackListener
 }
 
 func (h *HttpService) Set(handler fasthttp.RequestHandler) {
 	h.locker.Lock()
 	defer h.locker.Unlock()
 	if handler == nil {
-		h.srv.Handler = NotFound
+		handler = NotFound
 	}
-	h.srv.Handler = handler
+	h.srv.Handler = withHealth(handler)
 
 }
 
@@ -46,11 +48,32 @@ func (h *HttpService) ShutDown() {
 	log.Debug("http service shutdown done")
 }
 
+// Drain 停止 Accept 并等待已有连接（包括被 Hijack 的 websocket 连接）处理完成，ctx 结束时关闭剩余的连接
+func (h *HttpService) Drain(ctx context.Context) error {
+	h.locker.Lock()
+	srv := h.srv
+	h.locker.Unlock()
+	if srv == nil {
+		return nil
+	}
+	if err := srv.ShutdownWithContext(ctx); err != nil {
+		h.conns.closeAll()
+		return err
+	}
+	return h.conns.Wait(ctx)
+}
+
+// Active 当前打开的连接数，包括被 Hijack 的连接
+func (h *HttpService) Active() int {
+	return h.conns.Active()
+}
+
 func NewHttpService(listener net.Listener) *HttpService {
 	s := &HttpService{
-		srv: &fasthttp.Server{Handler: NotFound, DisablePreParseMultipartForm: true},
+		srv:   &fasthttp.Server{Handler: withHealth(NotFound), DisablePreParseMultipartForm: true},
+		conns: newTrackListener(listener),
 	}
-	go s.srv.Serve(listener)
+	go s.srv.Serve(s.conns)
 	log.Debug("new http service:", listener.Addr())
 	return s
 }
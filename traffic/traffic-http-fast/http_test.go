@@ -0,0 +1,126 @@
+package traffic_http_fast
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/valyala/fasthttp"
+)
+
+func TestDrainHijacked(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewHttpService(l)
+	release := make(chan struct{})
+	hijacked := make(chan struct{})
+	srv.Set(func(ctx *fasthttp.RequestCtx) {
+		ctx.Hijack(func(c net.Conn) {
+			close(hijacked)
+			<-release
+			c.Write([]byte("bye"))
+		})
+	})
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\n\r\n"))
+	select {
+	case <-hijacked:
+	case <-time.After(time.Second):
+		t.Fatal("request not hijacked")
+	}
+	if n := srv.Active(); n != 1 {
+		t.Errorf("Active() = %d with a hijacked connection", n)
+	}
+	if n := testutil.ToFloat64(acceptTotal.WithLabelValues(l.Addr().String(), "success")); n != 1 {
+		t.Errorf("accepts_total = %v", n)
+	}
+
+	drained := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		drained <- srv.Drain(ctx)
+	}()
+	select {
+	case err := <-drained:
+		t.Fatal("Drain() returned before the hijacked connection closed:", err)
+	case <-time.After(time.Millisecond * 200):
+	}
+
+	close(release)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if got, _ := io.ReadAll(conn); !bytes.HasSuffix(got, []byte("bye")) {
+		t.Errorf("hijacked connection got %q", got)
+	}
+	if err := <-drained; err != nil {
+		t.Error("Drain():", err)
+	}
+	if n := srv.Active(); n != 0 {
+		t.Errorf("Active() = %d after drain", n)
+	}
+}
+
+func TestDrainAfterListenerClosed(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewHttpService(l)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	srv.Set(func(ctx *fasthttp.RequestCtx) {
+		close(started)
+		<-release
+		ctx.SetBodyString("done")
+	})
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\n\r\n"))
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("request not started")
+	}
+
+	// 与 worker 的 drain 顺序一致：先关闭端口，再排空
+	l.Close()
+	drained := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		drained <- srv.Drain(ctx)
+	}()
+	select {
+	case err := <-drained:
+		t.Fatal("Drain() returned before the request finished:", err)
+	case <-time.After(time.Millisecond * 200):
+	}
+
+	close(release)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	got, _ := io.ReadAll(conn)
+	if !bytes.HasPrefix(got, []byte("HTTP/1.1 200")) || !bytes.HasSuffix(got, []byte("done")) {
+		t.Errorf("in-flight request got %q", got)
+	}
+	if err := <-drained; err != nil {
+		t.Error("Drain():", err)
+	}
+	if n := srv.Active(); n != 0 {
+		t.Errorf("Active() = %d after drain", n)
+	}
+}
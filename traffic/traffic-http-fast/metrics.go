@@ -0,0 +1,17 @@
+package traffic_http_fast
+
+import (
+	"github.com/eolinker/eosc/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var acceptTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "traffic",
+	Name:      "accepts_total",
+	Help:      "Connections accepted by traffic listeners.",
+}, []string{"listener", "result"})
+
+func init() {
+	metrics.MustRegister(acceptTotal)
+}
@@ -1,12 +1,15 @@
 package traffic_http_fast
 
 import (
+	"context"
 	"sync"
 
 	"github.com/eolinker/eosc/log"
+	"github.com/eolinker/eosc/traffic"
 )
 
 var _ IHttpTraffic = (*HttpTraffic)(nil)
+var _ traffic.IDrainer = (*HttpTraffic)(nil)
 
 type IHttpTraffic interface {
 	Set(port int, srv *HttpService)
@@ -46,6 +49,40 @@ func (h *HttpTraffic) Close() {
 	return
 }
 
+// Drain 并行排空所有端口上的 http 服务
+func (h *HttpTraffic) Drain(ctx context.Context) error {
+	h.locker.Lock()
+	srvs := make([]*HttpService, 0, len(h.srvs))
+	for _, s := range h.srvs {
+		srvs = append(srvs, s)
+	}
+	h.locker.Unlock()
+
+	errs := make(chan error, len(srvs))
+	for _, s := range srvs {
+		go func(s *HttpService) {
+			errs <- s.Drain(ctx)
+		}(s)
+	}
+	var err error
+	for range srvs {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (h *HttpTraffic) Active() int {
+	h.locker.Lock()
+	defer h.locker.Unlock()
+	n := 0
+	for _, s := range h.srvs {
+		n += s.Active()
+	}
+	return n
+}
+
 func (h *HttpTraffic) Set(port int, srv *HttpService) {
 	h.locker.Lock()
 	defer h.locker.Unlock()
@@ -75,8 +112,10 @@ func (h *HttpTraffic) All() map[int]IService {
 }
 
 func NewHttpTraffic() *HttpTraffic {
-	return &HttpTraffic{
+	h := &HttpTraffic{
 		locker: sync.Mutex{},
 		srvs:   make(map[int]*HttpService),
 	}
+	traffic.RegisterDrainer(h)
+	return h
 }
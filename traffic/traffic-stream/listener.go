@@ -0,0 +1,96 @@
+package traffic_stream
+
+import (
+	"net"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type deadliner interface {
+	SetDeadline(t time.Time) error
+}
+
+type acceptResult struct {
+	conn net.Conn
+	err  error
+}
+
+// listenerNotClose 端口由多个服务及新旧 worker 进程共享，关闭时不关闭端口，只让阻塞中的 Accept 返回
+// 支持 SetDeadline 的 listener 通过 deadline 唤醒 Accept，其它 listener（如 cmux 拆分出的 listener）在后台 Accept，关闭时直接返回
+type listenerNotClose struct {
+	inner     net.Listener
+	addr      net.Addr
+	deadliner deadliner
+
+	once     sync.Once
+	closed   chan struct{}
+	start    sync.Once
+	accepted chan acceptResult
+}
+
+func newNotClose(inner net.Listener) *listenerNotClose {
+	l := &listenerNotClose{
+		inner:    inner,
+		addr:     inner.Addr(),
+		closed:   make(chan struct{}),
+		accepted: make(chan acceptResult),
+	}
+	if d, ok := inner.(deadliner); ok {
+		d.SetDeadline(time.Time{})
+		l.deadliner = d
+	}
+	return l
+}
+
+func (l *listenerNotClose) Accept() (net.Conn, error) {
+	select {
+	case <-l.closed:
+		return nil, syscall.EINVAL
+	default:
+	}
+	if l.deadliner != nil {
+		return l.inner.Accept()
+	}
+	l.start.Do(func() {
+		go l.acceptLoop()
+	})
+	select {
+	case r := <-l.accepted:
+		return r.conn, r.err
+	case <-l.closed:
+		return nil, syscall.EINVAL
+	}
+}
+
+// acceptLoop 关闭后收到的连接已不属于当前服务，直接关闭
+func (l *listenerNotClose) acceptLoop() {
+	for {
+		conn, err := l.inner.Accept()
+		select {
+		case l.accepted <- acceptResult{conn: conn, err: err}:
+			if err != nil {
+				return
+			}
+		case <-l.closed:
+			if conn != nil {
+				conn.Close()
+			}
+			return
+		}
+	}
+}
+
+func (l *listenerNotClose) Addr() net.Addr {
+	return l.addr
+}
+
+func (l *listenerNotClose) Close() error {
+	l.once.Do(func() {
+		close(l.closed)
+		if l.deadliner != nil {
+			l.deadliner.SetDeadline(time.Now())
+		}
+	})
+	return nil
+}
@@ -0,0 +1,141 @@
+package traffic_stream
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+
+	stream_context "github.com/eolinker/eosc/eocontext/stream-context"
+	"github.com/eolinker/eosc/log"
+)
+
+var _ IService = (*StreamService)(nil)
+
+type IService interface {
+	Set(handler stream_context.Handler)
+	ShutDown()
+}
+
+// StreamService 把端口上的连接交给 stream_context 处理，tlsConfig 不为空时先终止 TLS
+type StreamService struct {
+	listener  *listenerNotClose
+	tlsConfig *tls.Config
+
+	locker  sync.Mutex
+	handler stream_context.Handler
+	conns   map[net.Conn]struct{}
+	wg      sync.WaitGroup
+	done    chan struct{}
+}
+
+func NewStreamService(listener net.Listener, tlsConfig *tls.Config) *StreamService {
+	s := &StreamService{
+		listener:  newNotClose(listener),
+		tlsConfig: tlsConfig,
+		handler:   stream_context.HandlerFunc(closeStream),
+		conns:     make(map[net.Conn]struct{}),
+		done:      make(chan struct{}),
+	}
+	go s.serve()
+	log.Debug("new stream service:", listener.Addr())
+	return s
+}
+
+// closeStream 未设置 handler 时直接关闭连接
+func closeStream(ctx stream_context.IStreamContext) {}
+
+func (s *StreamService) Set(handler stream_context.Handler) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	if handler == nil {
+		handler = stream_context.HandlerFunc(closeStream)
+	}
+	s.handler = handler
+}
+
+func (s *StreamService) serve() {
+	defer close(s.done)
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			// listener 关闭后 Accept 返回
+			log.Debug("stream accept: ", err)
+			return
+		}
+		if s.tlsConfig != nil {
+			conn = tls.Server(conn, s.tlsConfig)
+		}
+		s.locker.Lock()
+		handler := s.handler
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.locker.Unlock()
+		go s.handle(conn, handler)
+	}
+}
+
+func (s *StreamService) handle(conn net.Conn, handler stream_context.Handler) {
+	defer func() {
+		s.locker.Lock()
+		delete(s.conns, conn)
+		s.locker.Unlock()
+		s.wg.Done()
+	}()
+	ctx, err := stream_context.NewContext(conn)
+	if err != nil {
+		log.Debug("stream handshake: ", err)
+		conn.Close()
+		return
+	}
+	defer ctx.Close()
+	handler.ServeStream(ctx)
+}
+
+// ShutDown 停止 Accept 并关闭所有连接
+func (s *StreamService) ShutDown() {
+	s.listener.Close()
+	<-s.done
+	s.closeConns()
+	s.wg.Wait()
+	log.Debug("stream service shutdown done")
+}
+
+// Drain 停止 Accept 并等待已有连接结束，ctx 结束时关闭剩余的连接
+func (s *StreamService) Drain(ctx context.Context) error {
+	s.listener.Close()
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		s.closeConns()
+		return ctx.Err()
+	}
+	wait := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(wait)
+	}()
+	select {
+	case <-wait:
+		return nil
+	case <-ctx.Done():
+		s.closeConns()
+		<-wait
+		return ctx.Err()
+	}
+}
+
+// Active 当前打开的连接数
+func (s *StreamService) Active() int {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	return len(s.conns)
+}
+
+func (s *StreamService) closeConns() {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	for c := range s.conns {
+		c.Close()
+	}
+}
@@ -0,0 +1,150 @@
+package traffic_stream
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/eolinker/eosc/eocontext"
+	"github.com/eolinker/eosc/eocontext/balance"
+	"github.com/eolinker/eosc/eocontext/eotest"
+	stream_context "github.com/eolinker/eosc/eocontext/stream-context"
+)
+
+// upperFilter 把发往上游的数据转为大写
+type upperFilter struct{}
+
+func (upperFilter) OnRequest(p []byte) ([]byte, error)  { return bytes.ToUpper(p), nil }
+func (upperFilter) OnResponse(p []byte) ([]byte, error) { return p, nil }
+
+type proxyFilter struct {
+	app eocontext.EoApp
+}
+
+func (f *proxyFilter) DoFilter(ctx eocontext.EoContext, next eocontext.IChain) error {
+	return stream_context.DoStreamFilter(f, ctx, next)
+}
+
+func (f *proxyFilter) DoStreamFilter(ctx stream_context.IStreamContext, next eocontext.IChain) error {
+	ctx.SetApp(f.app)
+	ctx.SetBalance(balance.NewRoundRobin())
+	ctx.AddDataFilter(upperFilter{})
+	return next.DoChain(ctx)
+}
+
+func (f *proxyFilter) Destroy() {}
+
+func echoServer(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+	return l
+}
+
+func TestProxy(t *testing.T) {
+	upstream := echoServer(t)
+	defer upstream.Close()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	srv := NewStreamService(l, nil)
+	app := eotest.NewApp(stream_context.SchemeTCP, eotest.NewNode(upstream.Addr().String(), nil))
+	srv.Set(stream_context.ChainHandler(eocontext.Filters{&proxyFilter{app: app}}))
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Write([]byte("hello stream"))
+	conn.(*net.TCPConn).CloseWrite()
+	got, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "HELLO STREAM" {
+		t.Errorf("got %q", got)
+	}
+	conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srv.Drain(ctx); err != nil {
+		t.Error(err)
+	}
+	if srv.Active() != 0 {
+		t.Errorf("active = %d after drain", srv.Active())
+	}
+}
+
+func TestTLS(t *testing.T) {
+	// 借用 httptest 生成的自签名证书
+	hs := httptest.NewUnstartedServer(nil)
+	hs.StartTLS()
+	certs := hs.TLS.Certificates
+	hs.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	srv := NewStreamService(l, &tls.Config{Certificates: certs, NextProtos: []string{"echo"}})
+	defer srv.ShutDown()
+	srv.Set(stream_context.HandlerFunc(func(ctx stream_context.IStreamContext) {
+		ctx.Conn().Write([]byte(ctx.Scheme() + " " + ctx.ServerName() + " " + ctx.NegotiatedProtocol() + " " + strconv.Itoa(ctx.LocalPort())))
+	}))
+
+	conn, err := tls.Dial("tcp", l.Addr().String(), &tls.Config{InsecureSkipVerify: true, ServerName: "example.com", NextProtos: []string{"echo"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	got, _ := io.ReadAll(conn)
+	want := "tls example.com echo " + strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// plainListener 隐藏 SetDeadline，模拟 cmux 拆分出的 listener
+type plainListener struct {
+	net.Listener
+}
+
+func TestDrainWithoutDeadline(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	srv := NewStreamService(plainListener{l}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srv.Drain(ctx); err != nil {
+		t.Fatal("Drain() should not wait for the next connection:", err)
+	}
+	srv.ShutDown()
+}
@@ -0,0 +1,115 @@
+package traffic_stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/eolinker/eosc/log"
+	"github.com/eolinker/eosc/traffic"
+)
+
+var _ IStreamTraffic = (*StreamTraffic)(nil)
+var _ traffic.IDrainer = (*StreamTraffic)(nil)
+
+// IStreamTraffic 按端口管理四层（非 http）服务
+type IStreamTraffic interface {
+	Set(port int, srv *StreamService)
+	Get(port int) (IService, bool)
+	All() map[int]IService
+	ShutDown(port int)
+	Close()
+}
+
+type StreamTraffic struct {
+	locker sync.Mutex
+	srvs   map[int]*StreamService
+}
+
+func NewStreamTraffic() *StreamTraffic {
+	s := &StreamTraffic{
+		srvs: make(map[int]*StreamService),
+	}
+	traffic.RegisterDrainer(s)
+	return s
+}
+
+func (s *StreamTraffic) Set(port int, srv *StreamService) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.srvs[port] = srv
+}
+
+func (s *StreamTraffic) Get(port int) (IService, bool) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	srv, has := s.srvs[port]
+	if has {
+		return srv, true
+	}
+	return nil, false
+}
+
+func (s *StreamTraffic) All() map[int]IService {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	srvs := make(map[int]IService, len(s.srvs))
+	for k, v := range s.srvs {
+		srvs[k] = v
+	}
+	return srvs
+}
+
+func (s *StreamTraffic) ShutDown(port int) {
+	s.locker.Lock()
+	srv, has := s.srvs[port]
+	delete(s.srvs, port)
+	s.locker.Unlock()
+	if has {
+		log.Debug("stream traffic shutdown,port is ", port)
+		srv.ShutDown()
+	}
+}
+
+func (s *StreamTraffic) Close() {
+	s.locker.Lock()
+	srvs := s.srvs
+	s.srvs = make(map[int]*StreamService)
+	s.locker.Unlock()
+	for _, srv := range srvs {
+		srv.ShutDown()
+	}
+}
+
+// Drain 并行排空所有端口上的四层服务
+func (s *StreamTraffic) Drain(ctx context.Context) error {
+	s.locker.Lock()
+	srvs := make([]*StreamService, 0, len(s.srvs))
+	for _, srv := range s.srvs {
+		srvs = append(srvs, srv)
+	}
+	s.locker.Unlock()
+
+	errs := make(chan error, len(srvs))
+	for _, srv := range srvs {
+		go func(srv *StreamService) {
+			errs <- srv.Drain(ctx)
+		}(srv)
+	}
+	var err error
+	for range srvs {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (s *StreamTraffic) Active() int {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	n := 0
+	for _, srv := range s.srvs {
+		n += srv.Active()
+	}
+	return n
+}
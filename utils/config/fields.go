@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/eolinker/eosc"
+)
+
+// AnyPath 路径中匹配数组元素或 map 任意值的节点
+const AnyPath = "*"
+
+// RequireField 配置中声明依赖的字段，用于在无法获取配置类型的进程中解析依赖
+type RequireField struct {
+	// Path 字段的 json 路径，数组元素与 map 的值以 AnyPath 表示
+	Path []string
+	// Skill 被依赖的 worker 需要实现的能力
+	Skill string
+	// Optional 字段为空或 worker 不存在时不报错
+	Optional bool
+	// Multi 字段为 []RequireId
+	Multi bool
+}
+
+// RequireFields 返回配置类型中所有声明依赖的字段
+func RequireFields(t reflect.Type) []RequireField {
+	return requireFields(t, nil, make(map[reflect.Type]bool))
+}
+
+func requireFields(t reflect.Type, path []string, visiting map[reflect.Type]bool) []RequireField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if visiting[t] {
+			return nil
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		var fields []RequireField
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" && !f.Anonymous {
+				continue
+			}
+			name, inline := jsonName(f)
+			if name == "-" {
+				continue
+			}
+			fieldPath := path
+			if !inline {
+				fieldPath = appendPath(path, name)
+			}
+			switch TypeName(f.Type) {
+			case _RequireTypeName:
+				required, has := f.Tag.Lookup("required")
+				fields = append(fields, RequireField{
+					Path:     fieldPath,
+					Skill:    f.Tag.Get("skill"),
+					Optional: has && strings.ToLower(required) == "false",
+				})
+			case _RequireSliceTypeName:
+				require, has := f.Tag.Lookup("require")
+				fields = append(fields, RequireField{
+					Path:     fieldPath,
+					Skill:    f.Tag.Get("skill"),
+					Optional: has && strings.ToLower(require) == "false",
+					Multi:    true,
+				})
+			default:
+				fields = append(fields, requireFields(f.Type, fieldPath, visiting)...)
+			}
+		}
+		return fields
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return requireFields(t.Elem(), appendPath(path, AnyPath), visiting)
+	}
+	return nil
+}
+
+// jsonName 字段的 json 名称，匿名且未指定名称的结构体字段在 json 中展开
+func jsonName(f reflect.StructField) (string, bool) {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name != "" {
+		return name, false
+	}
+	t := f.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if f.Anonymous && t.Kind() == reflect.Struct {
+		return "", true
+	}
+	return f.Name, false
+}
+
+func appendPath(path []string, name string) []string {
+	p := make([]string, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, name)
+}
+
+// CheckRequireFields 按依赖字段从 json 结构的配置中解析依赖，规则与 CheckConfig 一致
+func CheckRequireFields(v interface{}, fields []RequireField, workers eosc.IWorkers) (map[RequireId]eosc.IWorker, error) {
+	requires := make(map[RequireId]eosc.IWorker)
+	for _, f := range fields {
+		for _, value := range lookupPath(reflect.ValueOf(v), f.Path) {
+			if err := checkRequireValue(f, value, workers, requires); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return requires, nil
+}
+
+func checkRequireValue(f RequireField, v reflect.Value, workers eosc.IWorkers, requires map[RequireId]eosc.IWorker) error {
+	name := strings.Join(f.Path, ".")
+	ids := make([]string, 0, 1)
+	if f.Multi {
+		if v.IsValid() && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) {
+			for i := 0; i < v.Len(); i++ {
+				if id := stringOf(v.Index(i)); id != "" {
+					ids = append(ids, id)
+				}
+			}
+		}
+	} else {
+		id := stringOf(v)
+		if id == "" {
+			if !f.Optional {
+				return fmt.Errorf("%s:%w", name, eosc.ErrorRequire)
+			}
+			return nil
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) > 0 && f.Skill == "" {
+		return fmt.Errorf("field %s :%w", name, eosc.ErrorNotGetSillForRequire)
+	}
+	for _, id := range ids {
+		target, has := workers.Get(id)
+		if !has || target == nil {
+			if !f.Optional {
+				return fmt.Errorf("required %s:%w", id, eosc.ErrorWorkerNotExits)
+			}
+			continue
+		}
+		if !target.CheckSkill(f.Skill) {
+			return fmt.Errorf(" %s value %s:%w", name, id, eosc.ErrorTargetNotImplementSkill)
+		}
+		requires[RequireId(id)] = target
+	}
+	return nil
+}
+
+// lookupPath 返回路径匹配的所有值，最后一级不存在时返回无效值，由调用方按字段是否必填处理
+func lookupPath(v reflect.Value, path []string) []reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) {
+		v = v.Elem()
+	}
+	if len(path) == 0 {
+		return []reflect.Value{v}
+	}
+	if !v.IsValid() {
+		// 上层字段不存在时与空指针一致，不解析其中的依赖
+		return nil
+	}
+	var values []reflect.Value
+	switch {
+	case path[0] == AnyPath && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array):
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, lookupPath(v.Index(i), path[1:])...)
+		}
+	case path[0] == AnyPath && v.Kind() == reflect.Map:
+		it := v.MapRange()
+		for it.Next() {
+			values = append(values, lookupPath(it.Value(), path[1:])...)
+		}
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		values = lookupPath(v.MapIndex(reflect.ValueOf(path[0]).Convert(v.Type().Key())), path[1:])
+	default:
+		values = []reflect.Value{{}}
+	}
+	return values
+}
+
+func stringOf(v reflect.Value) string {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) {
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.Kind() != reflect.String {
+		return ""
+	}
+	return v.String()
+}
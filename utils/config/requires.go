@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/eolinker/eosc"
+)
+
+// Requires 返回配置中引用的 worker id，不检查被引用的 worker 是否存在，用于确定 worker 的构建顺序
+func Requires(v interface{}) []string {
+	r := &requireRecorder{ids: make(map[string]struct{})}
+	checkConfig(reflect.ValueOf(v), r)
+	return r.list()
+}
+
+// DriverRequires 同 Requires，driver 实现 eosc.IExtenderConfigRequires 时由 driver 解析
+func DriverRequires(driver eosc.IExtenderDriver, v interface{}) []string {
+	dr, ok := driver.(eosc.IExtenderConfigRequires)
+	if !ok {
+		return Requires(v)
+	}
+	r := &requireRecorder{ids: make(map[string]struct{})}
+	dr.Requires(v, r)
+	return r.list()
+}
+
+// CheckDriverConfig 同 CheckConfig，driver 实现 eosc.IExtenderConfigRequires 时由 driver 解析
+func CheckDriverConfig(driver eosc.IExtenderDriver, v interface{}, workers eosc.IWorkers) (map[RequireId]eosc.IWorker, error) {
+	dr, ok := driver.(eosc.IExtenderConfigRequires)
+	if !ok {
+		return CheckConfig(v, workers)
+	}
+	requires, err := dr.Requires(v, workers)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", TypeNameOf(v), err)
+	}
+	if requires == nil {
+		requires = make(map[RequireId]eosc.IWorker)
+	}
+	return requires, nil
+}
+
+func (r *requireRecorder) list() []string {
+	ids := make([]string, 0, len(r.ids))
+	for id := range r.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// requireRecorder 记录被查询的 id，并以满足任意能力的占位 worker 返回
+type requireRecorder struct {
+	ids map[string]struct{}
+}
+
+func (r *requireRecorder) Get(id string) (eosc.IWorker, bool) {
+	r.ids[id] = struct{}{}
+	return placeholder(id), true
+}
+
+type placeholder string
+
+func (p placeholder) Id() string {
+	return string(p)
+}
+
+func (p placeholder) Start() error {
+	return nil
+}
+
+func (p placeholder) Reset(conf interface{}, workers map[eosc.RequireId]eosc.IWorker) error {
+	return nil
+}
+
+func (p placeholder) Stop() error {
+	return nil
+}
+
+func (p placeholder) CheckSkill(skill string) bool {
+	return true
+}
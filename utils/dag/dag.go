@@ -0,0 +1,101 @@
+package dag
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultConcurrency 同时构建的节点数
+const DefaultConcurrency = 16
+
+var (
+	ErrorCycle = errors.New("dependency cycle")
+)
+
+// Node 待执行的节点，Requires 中不在本次执行范围内的 id 忽略
+type Node struct {
+	Id       string
+	Requires []string
+}
+
+// Result 节点的执行结果
+type Result struct {
+	Id    string
+	Err   error
+	Spend time.Duration
+}
+
+// Run 按依赖关系执行 fn：节点在其依赖都执行完后才执行，相互独立的节点最多 concurrency 个同时执行
+// 依赖执行失败时节点仍会执行，由 fn 自行处理；处于循环依赖中的节点不执行，返回 ErrorCycle
+// 返回结果与 nodes 的顺序一致
+func Run(nodes []*Node, concurrency int, fn func(id string) error) []*Result {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	index := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		index[n.Id] = i
+	}
+	pending := make([]int, len(nodes))
+	dependents := make([][]int, len(nodes))
+	for i, n := range nodes {
+		seen := make(map[int]bool, len(n.Requires))
+		for _, r := range n.Requires {
+			j, has := index[r]
+			if !has || j == i || seen[j] {
+				continue
+			}
+			seen[j] = true
+			pending[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	results := make([]*Result, len(nodes))
+	ready := make(chan int, len(nodes))
+	done := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(nodes); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ready {
+				begin := time.Now()
+				err := fn(nodes[i].Id)
+				results[i] = &Result{Id: nodes[i].Id, Err: err, Spend: time.Since(begin)}
+				done <- i
+			}
+		}()
+	}
+
+	running := 0
+	// 就绪的节点按输入顺序排队
+	for i := range nodes {
+		if pending[i] == 0 {
+			ready <- i
+			running++
+		}
+	}
+	for running > 0 {
+		i := <-done
+		running--
+		for _, d := range dependents[i] {
+			pending[d]--
+			if pending[d] == 0 {
+				ready <- d
+				running++
+			}
+		}
+	}
+	close(ready)
+	wg.Wait()
+
+	for i, r := range results {
+		if r == nil {
+			results[i] = &Result{Id: nodes[i].Id, Err: fmt.Errorf("%s:%w", nodes[i].Id, ErrorCycle)}
+		}
+	}
+	return results
+}
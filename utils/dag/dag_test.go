@@ -0,0 +1,50 @@
+package dag
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	nodes := []*Node{
+		{Id: "router", Requires: []string{"service", "plugin"}},
+		{Id: "service", Requires: []string{"discovery", "outside"}},
+		{Id: "discovery"},
+		{Id: "plugin"},
+		{Id: "a", Requires: []string{"b"}},
+		{Id: "b", Requires: []string{"a"}},
+	}
+	failed := errors.New("failed")
+	var locker sync.Mutex
+	finished := make(map[string]bool)
+	results := Run(nodes, 2, func(id string) error {
+		locker.Lock()
+		defer locker.Unlock()
+		for _, n := range nodes {
+			if n.Id != id {
+				continue
+			}
+			for _, r := range n.Requires {
+				if r != "outside" && !finished[r] {
+					t.Errorf("%s run before %s", id, r)
+				}
+			}
+		}
+		finished[id] = true
+		if id == "plugin" {
+			return failed
+		}
+		return nil
+	})
+
+	want := []error{nil, nil, nil, failed, ErrorCycle, ErrorCycle}
+	for i, r := range results {
+		if r.Id != nodes[i].Id {
+			t.Fatalf("result %d is %s, want %s", i, r.Id, nodes[i].Id)
+		}
+		if !errors.Is(r.Err, want[i]) || (want[i] == nil && r.Err != nil) {
+			t.Errorf("%s: err %v, want %v", r.Id, r.Err, want[i])
+		}
+	}
+}
@@ -71,6 +71,64 @@ func interfaceSet(originVal reflect.Value, targetVal reflect.Value, variables eo
 	return usedVariables, err
 }
 
+func objectSet(originVal reflect.Value, targetVal reflect.Value, variables eosc.IVariable) ([]string, error) {
+	if originVal.Kind() == reflect.Interface {
+		originVal = originVal.Elem()
+	}
+	if originVal.Kind() != reflect.Map {
+		return nil, fmt.Errorf("object deal %w %s", ErrorUnsupportedKind, originVal.Kind())
+	}
+	v, used, err := anySet(originVal, variables)
+	if err != nil {
+		return nil, err
+	}
+	targetVal.Set(reflect.ValueOf(Object(v.(map[string]interface{}))))
+	return used, nil
+}
+
+// anySet 按原始结构复制 json 值，只替换字符串中的变量
+func anySet(originVal reflect.Value, variables eosc.IVariable) (interface{}, []string, error) {
+	if originVal.Kind() == reflect.Interface {
+		originVal = originVal.Elem()
+	}
+	if !originVal.IsValid() {
+		return nil, nil, nil
+	}
+	usedVariables := make([]string, 0)
+	switch originVal.Kind() {
+	case reflect.Map:
+		v := make(map[string]interface{}, originVal.Len())
+		it := originVal.MapRange()
+		for it.Next() {
+			value, used, err := anySet(it.Value(), variables)
+			if err != nil {
+				return nil, nil, err
+			}
+			usedVariables = append(usedVariables, used...)
+			v[fmt.Sprint(it.Key().Interface())] = value
+		}
+		return v, usedVariables, nil
+	case reflect.Array, reflect.Slice:
+		v := make([]interface{}, 0, originVal.Len())
+		for i := 0; i < originVal.Len(); i++ {
+			value, used, err := anySet(originVal.Index(i), variables)
+			if err != nil {
+				return nil, nil, err
+			}
+			usedVariables = append(usedVariables, used...)
+			v = append(v, value)
+		}
+		return v, usedVariables, nil
+	case reflect.String:
+		val, used, success := NewBuilder(originVal.String()).Replace(variables)
+		if !success {
+			return nil, nil, ErrorVariableNotFound
+		}
+		return val, used, nil
+	}
+	return originVal.Interface(), nil, nil
+}
+
 func boolSet(originVal reflect.Value, targetVal reflect.Value) error {
 	if targetVal.Kind() == reflect.Ptr {
 		targetVal = targetVal.Elem()
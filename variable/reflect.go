@@ -11,8 +11,12 @@ import (
 var (
 	methodName = "Reset"
 	resetType  = reflect.TypeOf((*IVariableResetType)(nil)).Elem()
+	objectType = reflect.TypeOf(Object{})
 )
 
+// Object 保留原始 json 结构的配置，只替换其中的变量，用于无法获取配置类型的进程外插件
+type Object map[string]interface{}
+
 type IVariableResetType interface {
 	Reset(originVal reflect.Value, targetVal reflect.Value, variables eosc.IVariable) ([]string, error)
 }
@@ -26,6 +30,9 @@ func recurseReflect(originVal reflect.Value, targetVal reflect.Value, variables
 	if targetVal.Kind() == reflect.Ptr {
 		targetVal = targetVal.Elem()
 	}
+	if targetVal.Type() == objectType {
+		return objectSet(originVal, targetVal, variables)
+	}
 	usedVariables := make([]string, 0, variables.Len())
 	var used []string
 	var err error
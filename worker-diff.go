@@ -0,0 +1,137 @@
+package eosc
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// WorkerDiff worker 本次 Reset 相对上一次生效配置的变化
+type WorkerDiff struct {
+	// Fields 发生变化的配置字段（json 顶层字段名），配置不是 json 对象时为空
+	Fields []string
+	// Config 配置内容是否变化
+	Config bool
+	// Added 新增的依赖，Removed 移除的依赖，Replaced id 不变但实例已重建的依赖
+	Added    []RequireId
+	Removed  []RequireId
+	Replaced []RequireId
+}
+
+// Changed 配置或依赖是否有变化
+func (d *WorkerDiff) Changed() bool {
+	return d.Config || len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Replaced) > 0
+}
+
+// IWorkerDiffReset 实现该接口的 worker 在配置变化时由 DiffReset 代替 Reset，可以只处理变化的部分
+type IWorkerDiffReset interface {
+	DiffReset(conf interface{}, workers map[RequireId]IWorker, diff *WorkerDiff) error
+}
+
+// WorkerState worker 最后一次生效的配置与依赖，用于跳过没有变化的 Reset
+type WorkerState struct {
+	config   []byte
+	requires map[RequireId]IWorker
+}
+
+// NewWorkerState 记录解析变量后的配置与依赖，配置无法序列化时视为每次都有变化
+func NewWorkerState(conf interface{}, requires map[RequireId]IWorker) *WorkerState {
+	data, err := json.Marshal(conf)
+	if err != nil {
+		data = nil
+	}
+	return &WorkerState{config: data, requires: requires}
+}
+
+// Diff 返回从 s 到 next 的变化，s 为空时配置与依赖都视为新增
+func (s *WorkerState) Diff(next *WorkerState) *WorkerDiff {
+	diff := &WorkerDiff{}
+	var config []byte
+	var requires map[RequireId]IWorker
+	if s != nil {
+		config, requires = s.config, s.requires
+	}
+	if config == nil || next.config == nil || !bytes.Equal(config, next.config) {
+		diff.Config = true
+		diff.Fields = diffFields(config, next.config)
+	}
+	for id, w := range next.requires {
+		o, has := requires[id]
+		if !has {
+			diff.Added = append(diff.Added, id)
+		} else if !sameWorker(o, w) {
+			diff.Replaced = append(diff.Replaced, id)
+		}
+	}
+	for id := range requires {
+		if _, has := next.requires[id]; !has {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+	sortRequireIds(diff.Added)
+	sortRequireIds(diff.Removed)
+	sortRequireIds(diff.Replaced)
+	return diff
+}
+
+// ResetWorker 配置与依赖都没有变化时跳过 Reset，worker 实现 IWorkerDiffReset 时传入变化内容
+// 返回本次生效的状态，Reset 失败时返回 old
+func ResetWorker(w IWorker, old *WorkerState, conf interface{}, requires map[RequireId]IWorker) (*WorkerState, bool, error) {
+	state := NewWorkerState(conf, requires)
+	diff := old.Diff(state)
+	if !diff.Changed() {
+		return state, false, nil
+	}
+	var err error
+	if dr, ok := w.(IWorkerDiffReset); ok {
+		err = dr.DiffReset(conf, requires, diff)
+	} else {
+		err = w.Reset(conf, requires)
+	}
+	if err != nil {
+		return old, false, err
+	}
+	return state, true, nil
+}
+
+func diffFields(old, next []byte) []string {
+	var om, nm map[string]json.RawMessage
+	if json.Unmarshal(next, &nm) != nil {
+		return nil
+	}
+	if old != nil && json.Unmarshal(old, &om) != nil {
+		om = nil
+	}
+	fields := make([]string, 0, len(nm))
+	for k, v := range nm {
+		if o, has := om[k]; !has || !bytes.Equal(o, v) {
+			fields = append(fields, k)
+		}
+	}
+	for k := range om {
+		if _, has := nm[k]; !has {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// sameWorker 依赖是否为同一实例，无法比较的类型视为不同
+func sameWorker(a, b IWorker) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb || !ta.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+func sortRequireIds(ids []RequireId) {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+}
@@ -0,0 +1,117 @@
+package eosc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var errReset = errors.New("reset failed")
+
+type diffConfig struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type diffWorker struct {
+	id    string
+	err   error
+	reset int
+}
+
+func (w *diffWorker) Id() string                   { return w.id }
+func (w *diffWorker) Start() error                 { return nil }
+func (w *diffWorker) Stop() error                  { return nil }
+func (w *diffWorker) CheckSkill(skill string) bool { return false }
+func (w *diffWorker) Reset(conf interface{}, workers map[RequireId]IWorker) error {
+	w.reset++
+	return w.err
+}
+
+// diffResetWorker 实现 IWorkerDiffReset，记录收到的变化
+type diffResetWorker struct {
+	diffWorker
+	diff *WorkerDiff
+}
+
+func (w *diffResetWorker) DiffReset(conf interface{}, workers map[RequireId]IWorker, diff *WorkerDiff) error {
+	w.diff = diff
+	return w.err
+}
+
+func TestWorkerStateDiff(t *testing.T) {
+	a, b, c := &diffWorker{id: "a"}, &diffWorker{id: "b"}, &diffWorker{id: "c"}
+	conf := &diffConfig{Name: "demo", Count: 1}
+	requires := map[RequireId]IWorker{"a": a, "b": b}
+	old := NewWorkerState(conf, requires)
+	tests := []struct {
+		name     string
+		old      *WorkerState
+		conf     interface{}
+		requires map[RequireId]IWorker
+		want     *WorkerDiff
+	}{
+		{name: "unchanged", old: old, conf: &diffConfig{Name: "demo", Count: 1}, requires: map[RequireId]IWorker{"a": a, "b": b}, want: &WorkerDiff{}},
+		{name: "field changed", old: old, conf: &diffConfig{Name: "demo", Count: 2}, requires: requires, want: &WorkerDiff{Config: true, Fields: []string{"count"}}},
+		{name: "require added", old: old, conf: conf, requires: map[RequireId]IWorker{"a": a, "b": b, "c": c}, want: &WorkerDiff{Added: []RequireId{"c"}}},
+		{name: "require removed", old: old, conf: conf, requires: map[RequireId]IWorker{"a": a}, want: &WorkerDiff{Removed: []RequireId{"b"}}},
+		{name: "require replaced", old: old, conf: conf, requires: map[RequireId]IWorker{"a": a, "b": &diffWorker{id: "b"}}, want: &WorkerDiff{Replaced: []RequireId{"b"}}},
+		{name: "first reset", old: nil, conf: conf, requires: map[RequireId]IWorker{"a": a}, want: &WorkerDiff{Config: true, Fields: []string{"count", "name"}, Added: []RequireId{"a"}}},
+		{name: "not object", old: NewWorkerState("v1", nil), conf: "v2", want: &WorkerDiff{Config: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.old.Diff(NewWorkerState(tt.conf, tt.requires))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+			if got.Changed() != !reflect.DeepEqual(tt.want, &WorkerDiff{}) {
+				t.Errorf("Changed() = %v", got.Changed())
+			}
+		})
+	}
+}
+
+func TestResetWorker(t *testing.T) {
+	conf := &diffConfig{Name: "demo", Count: 1}
+	changed := &diffConfig{Name: "demo", Count: 2}
+	tests := []struct {
+		name      string
+		worker    IWorker
+		conf      interface{}
+		wantReset bool
+		wantErr   error
+		wantState string
+	}{
+		{name: "skip unchanged", worker: &diffWorker{}, conf: conf, wantState: "new"},
+		{name: "reset", worker: &diffWorker{}, conf: changed, wantReset: true, wantState: "new"},
+		{name: "diff reset", worker: &diffResetWorker{}, conf: changed, wantReset: true, wantState: "new"},
+		{name: "reset failed", worker: &diffWorker{err: errReset}, conf: changed, wantErr: errReset, wantState: "old"},
+		{name: "diff reset failed", worker: &diffResetWorker{diffWorker: diffWorker{err: errReset}}, conf: changed, wantErr: errReset, wantState: "old"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := NewWorkerState(conf, nil)
+			state, reset, err := ResetWorker(tt.worker, old, tt.conf, nil)
+			if err != tt.wantErr || reset != tt.wantReset {
+				t.Fatalf("ResetWorker() = %v, %v, want %v, %v", reset, err, tt.wantReset, tt.wantErr)
+			}
+			if (state == old) != (tt.wantState == "old") {
+				t.Errorf("ResetWorker() returned %s state", map[bool]string{true: "old", false: "new"}[state == old])
+			}
+			switch w := tt.worker.(type) {
+			case *diffResetWorker:
+				if w.reset != 0 {
+					t.Error("Reset() called on IWorkerDiffReset")
+				}
+				if w.diff == nil || !reflect.DeepEqual(w.diff.Fields, []string{"count"}) {
+					t.Errorf("DiffReset() got %+v", w.diff)
+				}
+			case *diffWorker:
+				if (w.reset > 0) != (tt.wantReset || tt.wantErr != nil) {
+					t.Errorf("Reset() called %d times", w.reset)
+				}
+			}
+		})
+	}
+}